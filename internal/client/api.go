@@ -36,33 +36,101 @@ var (
 )
 
 type MainDataTorrent struct {
-	Custom               map[string]string `json:"custom"`
-	TrackerErrors        map[string]string `json:"tracker_errors"`
-	InfoHash             string            `json:"hash"`
-	Name                 string            `json:"name"`
-	Comment              string            `json:"comment"`
-	DirectoryBase        string            `json:"directory_base"`
-	Message              string            `json:"message"`
-	Tags                 []string          `json:"tags"`
-	UploadTotal          int64             `json:"upload_total"`
-	AddedAt              int64             `json:"add_at"`
-	DownloadTotal        int64             `json:"download_total"`
-	UploadRate           int64             `json:"upload_rate"`
-	ConnectedDownloading int               `json:"connected_downloading"`
-	ConnectionCount      int               `json:"connection_count"`
-	Completed            int64             `json:"completed"`
-	TotalLength          int64             `json:"total_length"`
-	SelectedSize         int64             `json:"selected_size"`
-	DownloadRate         int64             `json:"download_rate"`
-	CompletedAt          int64             `json:"completed_at"`
-	ConnectedSeeding     int               `json:"connected_seeding"`
-	Corrupted            int64             `json:"corrupted"`
-	WastedStale          int64             `json:"wasted_stale"`
-	WastedDupe           int64             `json:"wasted_dupe"`
-	TotalSeeding         int               `json:"total_seeding"`
-	TotalDownloading     int               `json:"total_downloading"`
-	Private              bool              `json:"private"`
-	State                uint8             `json:"state"`
+	Custom               map[string]string  `json:"custom"`
+	TrackerErrors        map[string]string  `json:"tracker_errors"`
+	Move                 *TorrentMove       `json:"move"`
+	Allocating           *TorrentAllocation `json:"allocating"`
+	InfoHash             string             `json:"hash"`
+	Name                 string             `json:"name"`
+	Comment              string             `json:"comment"`
+	Source               string             `json:"source"`
+	DirectoryBase        string             `json:"directory_base"`
+	CompletePath         string             `json:"complete_path"`
+	Message              string             `json:"message"`
+	Allocation           string             `json:"allocation"`
+	Tags                 []string           `json:"tags"`
+	Scrub                TorrentScrub       `json:"scrub"`
+	UploadTotal          int64              `json:"upload_total"`
+	AddedAt              int64              `json:"add_at"`
+	DownloadTotal        int64              `json:"download_total"`
+	UploadRate           int64              `json:"upload_rate"`
+	ConnectedDownloading int                `json:"connected_downloading"`
+	ConnectionCount      int                `json:"connection_count"`
+	Completed            int64              `json:"completed"`
+	TotalLength          int64              `json:"total_length"`
+	SelectedSize         int64              `json:"selected_size"`
+	DownloadRate         int64              `json:"download_rate"`
+	CompletedAt          int64              `json:"completed_at"`
+	ConnectedSeeding     int                `json:"connected_seeding"`
+	Corrupted            int64              `json:"corrupted"`
+	WastedStale          int64              `json:"wasted_stale"`
+	WastedDupe           int64              `json:"wasted_dupe"`
+	TotalSeeding         int                `json:"total_seeding"`
+	TotalDownloading     int                `json:"total_downloading"`
+	UnverifiedPieces     uint32             `json:"unverified_pieces"`
+	Private              bool               `json:"private"`
+	DiskSpacePaused      bool               `json:"disk_space_paused"`
+	State                uint8              `json:"state"`
+}
+
+// TorrentMove is the progress of a data move, or the error of the last move
+// if it failed.
+type TorrentMove struct {
+	Target     string `json:"target"`
+	Phase      string `json:"phase"`
+	Error      string `json:"error,omitempty"`
+	BytesDone  int64  `json:"bytes_done"`
+	BytesTotal int64  `json:"bytes_total"`
+	FilesDone  int    `json:"files_done"`
+	FilesTotal int    `json:"files_total"`
+}
+
+func newTorrentMove(s *download.MoveStatus) *TorrentMove {
+	if s == nil {
+		return nil
+	}
+	return &TorrentMove{
+		Target:     s.Target,
+		Phase:      s.Phase.String(),
+		Error:      s.Error,
+		BytesDone:  s.BytesDone,
+		BytesTotal: s.BytesTotal,
+		FilesDone:  s.FilesDone,
+		FilesTotal: s.FilesTotal,
+	}
+}
+
+// TorrentAllocation is the progress of allocating the files of a torrent
+// before its hash check.
+type TorrentAllocation struct {
+	BytesDone  int64 `json:"bytes_done"`
+	BytesTotal int64 `json:"bytes_total"`
+}
+
+func newTorrentAllocation(s *download.AllocationStatus) *TorrentAllocation {
+	if s == nil {
+		return nil
+	}
+	return &TorrentAllocation{BytesDone: s.BytesDone, BytesTotal: s.BytesTotal}
+}
+
+// TorrentScrub is the background scrub state of a torrent.
+type TorrentScrub struct {
+	// VerifiedAt is the unix time all data was last hash checked, by a scrub
+	// or a full recheck. 0 when never.
+	VerifiedAt   int64  `json:"verified_at"`
+	PiecesDone   uint32 `json:"pieces_done"`
+	FailedPieces uint32 `json:"failed_pieces"`
+	Scrubbing    bool   `json:"scrubbing"`
+}
+
+func newTorrentScrub(s download.ScrubStatus) TorrentScrub {
+	return TorrentScrub{
+		VerifiedAt:   s.VerifiedAt,
+		PiecesDone:   s.PiecesDone,
+		FailedPieces: s.FailedPieces,
+		Scrubbing:    s.Scrubbing,
+	}
 }
 
 type TorrentList struct {
@@ -90,10 +158,13 @@ func (c *Client) GetTorrentList(keys []string) TorrentList {
 			TotalLength:          info.TotalLength,
 			SelectedSize:         info.SelectedSize,
 			Comment:              info.Comment,
+			Source:               info.Source,
 			AddedAt:              info.AddedAt,
 			CompletedAt:          info.CompletedAt,
 			DirectoryBase:        info.DownloadDir,
+			CompletePath:         info.CompletePath,
 			Private:              info.Private,
+			DiskSpacePaused:      info.DiskSpacePaused,
 			Corrupted:            info.Corrupted,
 			WastedStale:          info.WastedStale,
 			WastedDupe:           info.WastedDupe,
@@ -106,6 +177,11 @@ func (c *Client) GetTorrentList(keys []string) TorrentList {
 			TotalDownloading:     info.TotalDownloading,
 			ConnectedSeeding:     info.ConnectedSeeding,
 			ConnectedDownloading: info.ConnectedDownloading,
+			UnverifiedPieces:     info.UnverifiedPieces,
+			Move:                 newTorrentMove(info.Move),
+			Allocation:           info.Allocation.String(),
+			Allocating:           newTorrentAllocation(info.Allocating),
+			Scrub:                newTorrentScrub(info.Scrub),
 		}
 	}
 
@@ -135,51 +211,131 @@ func (c *Client) GetTransferSummary() TransferSummary {
 	}
 }
 
-func (c *Client) AddTorrent(raw []byte, m *metainfo.MetaInfo, info meta.Info, downloadPath string, tags []string, custom map[string]string, selectedFiles []int, skipHashCheck bool) error {
+// AddTorrentOptions are the optional settings of a torrent being added.
+type AddTorrentOptions struct {
+	Custom map[string]string
+	// CrossSeed reuses matching existing data, all pieces are hash checked.
+	CrossSeed *CrossSeed
+	// IncompletePath is where the data is downloaded before it moves to the
+	// download path on completion, empty to download in place.
+	IncompletePath string
+	Tags           []string
+	// SelectedFiles are the files to download, empty for all of them.
+	SelectedFiles []int
+	Allocation    download.Allocation
+	SkipHashCheck bool
+}
+
+// AddTorrent adds a torrent whose data belongs in downloadPath.
+func (c *Client) AddTorrent(raw []byte, m *metainfo.MetaInfo, info meta.Info, downloadPath string, opts AddTorrentOptions) error {
 	log.Info().Msgf("try add torrent %s", info.Hash)
+	incompletePath, skipHashCheck := opts.IncompletePath, opts.SkipHashCheck
 
 	if err := validateTorrentPaths(downloadPath, info); err != nil {
 		return errgo.Wrap(err, "invalid torrent file paths")
 	}
 
-	c.m.RLock()
-	if _, ok := c.downloadMap[info.Hash]; ok {
-		c.m.RUnlock()
-		return fmt.Errorf("torrent %s exists", info.Hash)
+	basePath, completePath := downloadPath, ""
+	if incompletePath != "" && incompletePath != downloadPath {
+		if err := validateTorrentPaths(incompletePath, info); err != nil {
+			return errgo.Wrap(err, "invalid torrent file paths")
+		}
+		basePath, completePath = incompletePath, downloadPath
 	}
+
+	c.m.RLock()
+	err := c.checkAddableLocked(info.Hash)
 	c.m.RUnlock()
+	if err != nil {
+		return err
+	}
 
-	h := info.Hash.Hex()
+	added := false
+	var sharedFiles []int
+	if opts.CrossSeed != nil {
+		links, shared, err := c.prepareCrossSeed(&info, basePath, opts.CrossSeed)
+		if err != nil {
+			return errgo.Wrap(err, "failed to cross-seed")
+		}
+		defer func() {
+			if !added {
+				removeLinks(links)
+			}
+		}()
+		sharedFiles = shared
+		skipHashCheck = false
+	}
 
-	dir := filepath.Join(c.session.TorrentPath, h[:2], h[2:4])
-	err := os.MkdirAll(dir, os.ModePerm)
+	// torrents allocated up front fail their own check if the disk is too small
+	if opts.Allocation == download.AllocSparse && !skipHashCheck {
+		need, err := download.RequiredSpace(c.session.Config.App, &info, basePath, opts.SelectedFiles)
+		if err != nil {
+			return err
+		}
+		if err := c.checkFreeSpace(basePath, need); err != nil {
+			return err
+		}
+	}
+
+	c.m.Lock()
+	if err := c.checkAddableLocked(info.Hash); err != nil {
+		c.m.Unlock()
+		return err
+	}
+	if err := c.resolveAddConflicts(&info, sharedFiles, basePath, completePath); err != nil {
+		c.m.Unlock()
+		return err
+	}
+	release := c.reserveAddLocked(info.Hash, download.OwnedPaths(&info, basePath, completePath))
+	c.m.Unlock()
+	defer release()
+
+	torrentPath, err := c.saveTorrentFile(info.Hash, raw)
 	if err != nil {
-		return errgo.Wrap(err, fmt.Sprintf("failed to create director %q", dir))
+		return err
 	}
 
-	err = os.WriteFile(filepath.Join(dir, h+".torrent"), raw, 0644)
+	d, err := c.NewDownload(m, info, basePath, completePath, opts.Tags, opts.Custom, opts.SelectedFiles, sharedFiles, skipHashCheck, opts.Allocation)
 	if err != nil {
-		return errgo.Wrap(err, "failed to save torrent to disk")
+		_ = os.Remove(torrentPath)
+		return err
 	}
 
 	c.m.Lock()
-	defer c.m.Unlock()
-	if _, ok := c.downloadMap[info.Hash]; ok {
-		return fmt.Errorf("torrent %s exists", info.Hash)
+	c.addDownloadLocked(d)
+	c.m.Unlock()
+	added = true
+	return nil
+}
+
+// saveTorrentFile stores the torrent file of a torrent being added in the
+// session and returns its path.
+func (c *Client) saveTorrentFile(ih metainfo.Hash, raw []byte) (string, error) {
+	h := ih.Hex()
+
+	dir := filepath.Join(c.session.TorrentPath, h[:2], h[2:4])
+	err := os.MkdirAll(dir, os.ModePerm)
+	if err != nil {
+		return "", errgo.Wrap(err, fmt.Sprintf("failed to create director %q", dir))
 	}
 
-	d, err := c.NewDownload(m, info, downloadPath, tags, custom, selectedFiles, skipHashCheck)
+	path := filepath.Join(dir, h+".torrent")
+	err = os.WriteFile(path, raw, 0644)
 	if err != nil {
-		return err
+		return "", errgo.Wrap(err, "failed to save torrent to disk")
 	}
+	return path, nil
+}
 
+// addDownloadLocked registers a new download. Caller must hold c.m.
+func (c *Client) addDownloadLocked(d *Download) {
 	c.downloads = append(c.downloads, d)
 
 	slices.SortFunc(c.downloads, func(a, b *Download) int {
 		return bytes.Compare(a.InfoHashBytes(), b.InfoHashBytes())
 	})
 
-	c.downloadMap[info.Hash] = d
+	c.downloadMap[d.InfoHash()] = d
 	c.infoHashes = lo.Keys(c.downloadMap)
 	keys := hashesToBytes(c.infoHashes)
 	c.mseKeys.Store(&keys)
@@ -189,30 +345,36 @@ func (c *Client) AddTorrent(raw []byte, m *metainfo.MetaInfo, info meta.Info, do
 	if c.session.DownloadSlots.Load() > 0 {
 		c.triggerQueueRebalance()
 	}
-
-	return nil
 }
 
 // validateTorrentPaths checks that all file paths in the torrent
 // are safe and don't escape the download directory.
 func validateTorrentPaths(basePath string, info meta.Info) error {
-	base := filepath.Clean(basePath)
 	for _, f := range info.Files {
-		p := filepath.Clean(f.Path)
-		if p == "." || p == "" {
-			return fmt.Errorf("invalid torrent file path: %q", f.Path)
-		}
-		if filepath.IsAbs(p) || filepath.VolumeName(p) != "" || strings.HasPrefix(p, ".."+string(filepath.Separator)) {
-			return fmt.Errorf("torrent file path escapes base: %q", f.Path)
-		}
-		full := filepath.Clean(filepath.Join(base, p))
-		if !strings.HasPrefix(full, base+string(filepath.Separator)) && full != base {
-			return fmt.Errorf("torrent file path escapes base: %q", f.Path)
+		if err := validateTorrentPath(basePath, f.Path); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+// validateTorrentPath checks that path is relative and stays inside basePath.
+func validateTorrentPath(basePath string, path string) error {
+	base := filepath.Clean(basePath)
+	p := filepath.Clean(path)
+	if p == "." || p == "" {
+		return fmt.Errorf("invalid torrent file path: %q", path)
+	}
+	if filepath.IsAbs(p) || filepath.VolumeName(p) != "" || p == ".." || strings.HasPrefix(p, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("torrent file path escapes base: %q", path)
+	}
+	full := filepath.Clean(filepath.Join(base, p))
+	if !strings.HasPrefix(full, base+string(filepath.Separator)) && full != base {
+		return fmt.Errorf("torrent file path escapes base: %q", path)
+	}
+	return nil
+}
+
 type TorrentFile struct {
 	Path     []string `json:"path"`
 	Index    int      `json:"index"`
@@ -378,6 +540,7 @@ func (c *Client) RemoveTorrent(h metainfo.Hash, removeData bool) error {
 
 	delete(c.downloadMap, h)
 	c.downloads = gslice.Remove(c.downloads, d)
+	c.session.Paths.Remove(h)
 	c.infoHashes = lo.Keys(c.downloadMap)
 	keys := hashesToBytes(c.infoHashes)
 	c.mseKeys.Store(&keys)
@@ -10,6 +10,7 @@ import (
 	"os"
 	"sync"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/atomic"
 
 	"neptune/internal/config"
@@ -26,11 +27,13 @@ func New(cfg config.Config, sessionPath string, debug bool) *Client {
 
 	c := &Client{
 		session:          sess,
-		checkQueue:       make([]metainfo.Hash, 0, 3),
 		downloadMap:      make(map[metainfo.Hash]*Download),
+		createJobs:       make(map[metainfo.Hash]*createJob),
+		adding:           make(map[metainfo.Hash]struct{}),
 		connChan:         make(chan incomingConn, 1),
 		fh:               make(map[string]*os.File),
 		queueRebalanceCh: make(chan empty.Empty, 1),
+		diskSpaceMetrics: newDiskSpaceMetrics(),
 	}
 
 	if s, err := download.PiecePickStrategyFromString(cfg.App.PiecePickStrategy); err == nil {
@@ -39,6 +42,7 @@ func New(cfg config.Config, sessionPath string, debug bool) *Client {
 
 	c.startUploadPool()
 	sess.InitMetrics()
+	prometheus.MustRegister(c.diskSpaceMetrics.collectors()...)
 
 	return c
 }
@@ -52,21 +56,35 @@ type incomingConn struct {
 type Client struct {
 	session           *session.Session
 	downloadMap       map[metainfo.Hash]*Download
+	createJobs        map[metainfo.Hash]*createJob
+	adding            map[metainfo.Hash]struct{} // torrents being added, see reserveAddLocked
 	connChan          chan incomingConn
 	fh                map[string]*os.File
 	queueRebalanceCh  chan empty.Empty
+	checkQueueStop    chan empty.Empty
+	checkQueueDone    chan empty.Empty
+	diskSpaceMetrics  diskSpaceMetrics
+	mseKeys           atomic.Pointer[[][]byte]
 	downloads         []*Download
 	infoHashes        []metainfo.Hash
-	mseKeys           atomic.Pointer[[][]byte]
-	checkQueue        []metainfo.Hash
 	piecePickStrategy atomic.Uint32
 	m                 sync.RWMutex
+	createMu          sync.Mutex
 }
 
 type DownloadInfo struct {
-	Custom map[string]string
-	Name   string
-	Tags   []string
+	Custom       map[string]string
+	Name         string
+	Comment      string
+	CreatedBy    string
+	Encoding     string
+	Source       string
+	Tags         []string
+	WebSeeds     []string
+	HTTPSeeds    []string
+	Nodes        []string
+	CreationDate int64
+	Private      bool
 }
 
 func (c *Client) GetTorrent(h metainfo.Hash) (DownloadInfo, error) {
@@ -80,9 +98,18 @@ func (c *Client) GetTorrent(h metainfo.Hash) (DownloadInfo, error) {
 
 	info := d.Info(nil)
 	return DownloadInfo{
-		Name:   info.Name,
-		Tags:   info.Tags,
-		Custom: info.Custom,
+		Name:         info.Name,
+		Tags:         info.Tags,
+		Custom:       info.Custom,
+		Comment:      info.Comment,
+		CreatedBy:    info.CreatedBy,
+		CreationDate: info.CreationDate,
+		Encoding:     info.Encoding,
+		Source:       info.Source,
+		WebSeeds:     info.WebSeeds,
+		HTTPSeeds:    info.HTTPSeeds,
+		Nodes:        info.Nodes,
+		Private:      info.Private,
 	}, nil
 }
 
@@ -39,8 +39,8 @@ func (c *Client) DumpState(w io.Writer) {
 			entries = append(entries, downloadEntry{idx: i, d: d})
 		}
 	}
-	checkQueueLen := len(c.checkQueue)
 	c.m.RUnlock()
+	checkQueueLen := c.session.HashCheck.QueueLen()
 
 	// Build per-download debug data.
 	downloads := make([]any, len(entries))
@@ -6,10 +6,18 @@ package client
 import (
 	"fmt"
 
+	"github.com/rs/zerolog/log"
+
+	"neptune/internal/download"
+	"neptune/internal/hashcheck"
 	"neptune/internal/metainfo"
+	"neptune/internal/session/store"
 )
 
-func (c *Client) RecheckTorrent(h metainfo.Hash) error {
+// RecheckTorrent queues a hash check of a torrent. With files or ranges, only
+// the pieces of those files and in those ranges are checked, otherwise all of
+// them.
+func (c *Client) RecheckTorrent(h metainfo.Hash, files []int, ranges []download.PieceRange) error {
 	c.m.RLock()
 	d, ok := c.downloadMap[h]
 	c.m.RUnlock()
@@ -18,5 +26,108 @@ func (c *Client) RecheckTorrent(h metainfo.Hash) error {
 		return fmt.Errorf("torrent %s not exists", h)
 	}
 
+	if len(files) != 0 || len(ranges) != 0 {
+		return d.RecheckPieces(files, ranges)
+	}
 	return d.AsyncCheck()
 }
+
+// HashChecks returns progress for every queued or running hash check.
+func (c *Client) HashChecks() []hashcheck.Progress {
+	return c.session.HashCheck.Checks()
+}
+
+// CancelCheck aborts the queued or running hash check of a torrent. The
+// torrent returns to the state and bitfield it had before the check.
+func (c *Client) CancelCheck(h metainfo.Hash) error {
+	return c.session.HashCheck.Cancel(h)
+}
+
+func (c *Client) MoveCheckToTop(h metainfo.Hash) error {
+	return c.session.HashCheck.MoveToTop(h)
+}
+
+func (c *Client) MoveCheckToBottom(h metainfo.Hash) error {
+	return c.session.HashCheck.MoveToBottom(h)
+}
+
+func (c *Client) PauseChecks() {
+	c.session.HashCheck.Pause()
+}
+
+func (c *Client) ResumeChecks() {
+	c.session.HashCheck.Resume()
+}
+
+func (c *Client) ChecksPaused() bool {
+	return c.session.HashCheck.Paused()
+}
+
+// restoreCheckQueue re-queues the hash checks that were pending at the last
+// shutdown, in their old order. It must run after all resumes are loaded.
+func (c *Client) restoreCheckQueue() error {
+	queue, err := c.session.Store.CheckQueue()
+	if err != nil {
+		return err
+	}
+
+	c.m.RLock()
+	byHex := make(map[string]*Download, len(c.downloads))
+	for _, d := range c.downloads {
+		byHex[d.InfoHash().Hex()] = d
+	}
+	c.m.RUnlock()
+
+	for _, check := range queue {
+		d, ok := byHex[check.InfoHash]
+		if !ok {
+			continue
+		}
+		if err := d.RestoreCheck(check.Pieces); err != nil {
+			log.Warn().Err(err).Str("info_hash", check.InfoHash).Msg("failed to restore queued hash check")
+		}
+	}
+	return nil
+}
+
+// persistCheckQueue saves the hash check queue whenever it changes, until
+// stopCheckQueue is called.
+func (c *Client) persistCheckQueue() {
+	defer close(c.checkQueueDone)
+
+	for {
+		select {
+		case <-c.checkQueueStop:
+			return
+		case <-c.session.HashCheck.Changed():
+			c.saveCheckQueue()
+		}
+	}
+}
+
+// stopCheckQueue saves the queue one last time and stops persisting it, so
+// checks aborted by shutdown are still queued after restart.
+func (c *Client) stopCheckQueue() {
+	if c.checkQueueStop == nil {
+		return
+	}
+	close(c.checkQueueStop)
+	<-c.checkQueueDone
+	c.saveCheckQueue()
+}
+
+func (c *Client) saveCheckQueue() {
+	hashes := c.session.HashCheck.Hashes()
+	queue := make([]store.QueuedCheck, len(hashes))
+	c.m.RLock()
+	for i, h := range hashes {
+		queue[i].InfoHash = h.Hex()
+		if d, ok := c.downloadMap[h]; ok {
+			queue[i].Pieces = d.CheckPieces()
+		}
+	}
+	c.m.RUnlock()
+	if err := c.session.Store.SaveCheckQueue(queue); err != nil {
+		log.Err(err).Msg("failed to save hash check queue")
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package client
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"neptune/internal/download"
+	"neptune/internal/hashcheck"
+	"neptune/internal/metainfo"
+	"neptune/internal/mktorrent"
+	"neptune/internal/pkg/bm"
+	"neptune/internal/version"
+)
+
+var ErrCreateJobNotFound = errors.New("torrent creation job not found")
+
+// createJobTTL is how long the result of a finished creation is kept.
+const createJobTTL = time.Hour
+
+// CreateTorrent describes a torrent to create from local data.
+type CreateTorrent struct {
+	// Tags of the torrent when it is added.
+	Tags []string
+	mktorrent.Options
+	// Add adds the created torrent to the session, seeding the data it was
+	// created from without checking it again.
+	Add bool
+}
+
+// CreateStatus is the state of a torrent creation job.
+type CreateStatus struct {
+	Error string
+	// State is "queued", "hashing", "done" or "failed".
+	State string
+	// Torrent is the bencoded torrent file once the job is done.
+	Torrent    []byte
+	BytesDone  int64
+	BytesTotal int64
+	InfoHash   metainfo.Hash
+	Added      bool
+}
+
+type createJob struct {
+	err     error
+	plan    *mktorrent.Plan
+	torrent *mktorrent.Torrent
+	added   bool
+	done    bool
+}
+
+// CreateTorrent scans the data of a torrent to create and queues hashing it
+// as a hash check, returning the id of the job. The check is listed with the
+// job id as info hash and can be canceled or reordered like any other.
+func (c *Client) CreateTorrent(req CreateTorrent) (metainfo.Hash, error) {
+	if req.CreatedBy == "" {
+		req.CreatedBy = fmt.Sprintf("Neptune/%d.%d.%d", version.MAJOR, version.MINOR, version.PATCH)
+	}
+	plan, err := mktorrent.NewPlan(req.Options)
+	if err != nil {
+		return metainfo.Hash{}, err
+	}
+
+	var id metainfo.Hash
+	_, _ = rand.Read(id[:])
+	check, err := c.session.HashCheck.Enqueue(id, plan.BasePath)
+	if err != nil {
+		return metainfo.Hash{}, err
+	}
+
+	job := &createJob{plan: plan}
+	c.createMu.Lock()
+	c.createJobs[id] = job
+	c.createMu.Unlock()
+
+	go c.runCreate(id, job, check, req)
+	return id, nil
+}
+
+func (c *Client) runCreate(id metainfo.Hash, job *createJob, check *hashcheck.Check, req CreateTorrent) {
+	defer check.Done()
+
+	torrent, err := func() (*mktorrent.Torrent, error) {
+		if err := check.Wait(c.session.Ctx); err != nil {
+			return nil, err
+		}
+		return job.plan.Build(c.session.Ctx, check, c.session.IOContext)
+	}()
+
+	added := false
+	if err == nil && req.Add {
+		err = c.addCreatedTorrent(torrent, job.plan.BasePath, req.Tags)
+		added = err == nil
+	}
+	if err != nil {
+		log.Err(err).Str("path", req.Path).Msg("failed to create torrent")
+	} else {
+		log.Info().Str("path", req.Path).Stringer("info_hash", torrent.Info.Hash).Msg("torrent created")
+	}
+
+	c.createMu.Lock()
+	job.torrent, job.err, job.added, job.done = torrent, err, added, true
+	c.createMu.Unlock()
+
+	time.AfterFunc(createJobTTL, func() {
+		c.createMu.Lock()
+		delete(c.createJobs, id)
+		c.createMu.Unlock()
+	})
+}
+
+// CreateStatus returns the progress of a creation job, and the torrent once
+// it is done. A finished job is kept for createJobTTL.
+func (c *Client) CreateStatus(id metainfo.Hash) (CreateStatus, error) {
+	c.createMu.Lock()
+	defer c.createMu.Unlock()
+
+	job, ok := c.createJobs[id]
+	if !ok {
+		return CreateStatus{}, ErrCreateJobNotFound
+	}
+
+	s := CreateStatus{BytesTotal: job.plan.TotalLength(), State: "queued"}
+	switch {
+	case job.err != nil:
+		s.State = "failed"
+		s.Error = job.err.Error()
+	case job.done:
+		s.State = "done"
+		s.BytesDone = s.BytesTotal
+		s.Torrent = job.torrent.Raw
+		s.InfoHash = job.torrent.Info.Hash
+		s.Added = job.added
+	default:
+		if p, ok := c.session.HashCheck.Get(id); ok && p.Status == hashcheck.Hashing {
+			s.State = "hashing"
+			s.BytesDone = p.BytesDone
+		}
+	}
+	return s, nil
+}
+
+// addCreatedTorrent adds a torrent just created from the data under basePath,
+// seeding it with all pieces verified.
+func (c *Client) addCreatedTorrent(torrent *mktorrent.Torrent, basePath string, tags []string) error {
+	info := torrent.Info
+
+	c.m.RLock()
+	err := c.checkAddableLocked(info.Hash)
+	c.m.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	c.m.Lock()
+	if err := c.checkAddableLocked(info.Hash); err != nil {
+		c.m.Unlock()
+		return err
+	}
+	// the data is where it is, it can not be renamed
+	files := download.OwnedPaths(&info, basePath)
+	conflicts := c.pathConflicts(info.Hash, files, func(owner *Download, ownerFile, file int) bool {
+		return owner.SameFileData(ownerFile, &info, file)
+	})
+	if len(conflicts) != 0 {
+		c.m.Unlock()
+		return conflicts[0].error()
+	}
+	release := c.reserveAddLocked(info.Hash, files)
+	c.m.Unlock()
+	defer release()
+
+	torrentPath, err := c.saveTorrentFile(info.Hash, torrent.Raw)
+	if err != nil {
+		return err
+	}
+
+	completed := bm.New(info.NumPieces)
+	completed.Fill()
+	d, err := download.New(c.session, torrent.MetaInfo, info, basePath, tags, nil, nil, download.InitState{
+		State:             download.Seeding,
+		CompletedPieces:   completed,
+		PiecePickStrategy: download.PiecePickStrategy(c.piecePickStrategy.Load()),
+		Allocation:        download.AllocSparse,
+	})
+	if err != nil {
+		_ = os.Remove(torrentPath)
+		return err
+	}
+
+	c.m.Lock()
+	c.addDownloadLocked(d)
+	c.m.Unlock()
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/download"
+	"neptune/internal/meta"
+	"neptune/internal/metainfo"
+	"neptune/internal/mktorrent"
+)
+
+func waitCreated(t *testing.T, c *Client, id metainfo.Hash) CreateStatus {
+	t.Helper()
+	var s CreateStatus
+	require.Eventually(t, func() bool {
+		var err error
+		s, err = c.CreateStatus(id)
+		require.NoError(t, err)
+		return s.State == "done" || s.State == "failed"
+	}, 10*time.Second, 10*time.Millisecond)
+	return s
+}
+
+func TestCreateTorrentAndSeed(t *testing.T) {
+	c := newPathTestClient(t, "")
+	dir := filepath.Join(t.TempDir(), "album")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "01.flac"), randomBytes(3*crossSeedPieceLength+10), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cover.jpg"), randomBytes(500), 0o644))
+
+	id, err := c.CreateTorrent(CreateTorrent{
+		Options: mktorrent.Options{Path: dir, Trackers: [][]string{{"https://tracker.example/announce"}}},
+		Tags:    []string{"created"},
+		Add:     true,
+	})
+	require.NoError(t, err)
+
+	s := waitCreated(t, c, id)
+	require.Equal(t, "done", s.State, s.Error)
+	require.True(t, s.Added)
+	require.Equal(t, s.BytesTotal, s.BytesDone)
+
+	m, err := metainfo.Load(s.Torrent)
+	require.NoError(t, err)
+	info, err := meta.FromTorrent(*m)
+	require.NoError(t, err)
+	require.Equal(t, s.InfoHash, info.Hash)
+	require.Equal(t, "album", info.Name)
+
+	c.m.RLock()
+	d := c.downloadMap[info.Hash]
+	c.m.RUnlock()
+	require.NotNil(t, d)
+	require.Equal(t, download.Seeding, d.GetState())
+	require.Equal(t, []string{"created"}, d.Info(nil).Tags)
+
+	owners, err := c.PathOwners(filepath.Join(dir, "cover.jpg"))
+	require.NoError(t, err)
+	require.Equal(t, []PathOwner{{Path: filepath.Join(dir, "cover.jpg"), InfoHash: info.Hash.Hex(), File: 1}}, owners)
+}
+
+func TestCreateTorrentErrors(t *testing.T) {
+	c := newPathTestClient(t, "")
+
+	_, err := c.CreateTorrent(CreateTorrent{Options: mktorrent.Options{Path: filepath.Join(t.TempDir(), "missing")}})
+	require.Error(t, err)
+
+	_, err = c.CreateStatus(metainfo.Hash{1})
+	require.ErrorIs(t, err, ErrCreateJobNotFound)
+}
@@ -0,0 +1,277 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package client
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/rs/zerolog/log"
+
+	"neptune/internal/download"
+	"neptune/internal/meta"
+	"neptune/internal/metainfo"
+	"neptune/internal/pkg/reflink"
+)
+
+// LinkMode is how a cross-seeded torrent uses the existing files it matched.
+type LinkMode uint8
+
+const (
+	LinkRemap    LinkMode = iota // point the file at the existing path (default)
+	LinkHardlink                 // hardlink the existing file into the torrent
+	LinkReflink                  // clone the existing file into the torrent
+)
+
+// ParseLinkMode converts an API string to LinkMode.
+func ParseLinkMode(s string) (LinkMode, error) {
+	switch s {
+	case "", "remap":
+		return LinkRemap, nil
+	case "hardlink":
+		return LinkHardlink, nil
+	case "reflink":
+		return LinkReflink, nil
+	default:
+		return 0, fmt.Errorf("invalid link mode %q: must be 'remap', 'hardlink', or 'reflink'", s)
+	}
+}
+
+// CrossSeed is existing data a torrent being added reuses instead of
+// downloading it again, such as the same content from another tracker with a
+// different name, piece size or folder layout.
+type CrossSeed struct {
+	// Paths are existing files, or directories searched for files.
+	Paths []string
+	// Torrents are loaded torrents whose files are candidates.
+	Torrents []metainfo.Hash
+	Link     LinkMode
+}
+
+// crossSeedSamples is how many pieces of a file are hash checked to tell
+// candidates of the same size apart.
+const crossSeedSamples = 3
+
+// fileCandidate is an existing file that may hold the data of a torrent file.
+type fileCandidate struct {
+	path string
+	// file is the index of the file in torrent infoHash, -1 for a file of no
+	// torrent.
+	file     int
+	infoHash metainfo.Hash
+}
+
+// crossSeedCandidates returns the existing files of cs by size.
+func (c *Client) crossSeedCandidates(cs *CrossSeed) (map[int64][]fileCandidate, error) {
+	bySize := make(map[int64][]fileCandidate)
+	for _, root := range cs.Paths {
+		root, err := filepath.Abs(root)
+		if err != nil {
+			return nil, err
+		}
+		err = filepath.WalkDir(root, func(path string, e fs.DirEntry, err error) error {
+			if err != nil || !e.Type().IsRegular() {
+				return err
+			}
+			stat, err := e.Info()
+			if err != nil {
+				return err
+			}
+			bySize[stat.Size()] = append(bySize[stat.Size()], fileCandidate{path: path, file: -1})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.m.RLock()
+	defer c.m.RUnlock()
+	for _, ih := range cs.Torrents {
+		d, ok := c.downloadMap[ih]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", download.ErrTorrentNotFound, ih)
+		}
+		addCompletedFiles(bySize, d)
+	}
+	return bySize, nil
+}
+
+// addCompletedFiles adds the files of d with all pieces verified to bySize.
+func addCompletedFiles(bySize map[int64][]fileCandidate, d *Download) {
+	for _, f := range d.CompletedFiles() {
+		bySize[f.Length] = append(bySize[f.Length], fileCandidate{path: f.Path, file: f.Index, infoHash: d.InfoHash()})
+	}
+}
+
+// fileMatch is the existing file matched to a file of a torrent.
+type fileMatch struct {
+	fileCandidate
+	// verified is set when sample pieces of the file passed the hash check
+	// against the candidate, not only its size and name matched.
+	verified bool
+}
+
+// matchFiles maps the files of info to existing files of the same size. With
+// verify, a candidate must pass the hash check of a few pieces of the file,
+// and files too small to hold a piece on their own take the candidate with
+// the same name, or the only one of their size. Without verify, only a
+// candidate with the same name matches.
+func matchFiles(info *meta.Info, bySize map[int64][]fileCandidate, verify bool) map[int]fileMatch {
+	matches := make(map[int]fileMatch)
+	for i, f := range info.Files {
+		if f.Length == 0 {
+			continue
+		}
+		candidates := bySize[f.Length]
+		if len(candidates) == 0 {
+			continue
+		}
+
+		// the same name is the most likely match, try it first
+		name := filepath.Base(f.Path)
+		candidates = slices.Clone(candidates)
+		slices.SortStableFunc(candidates, func(a, b fileCandidate) int {
+			return boolCompare(filepath.Base(a.path) == name, filepath.Base(b.path) == name)
+		})
+		sameName := filepath.Base(candidates[0].path) == name
+
+		start, end := info.ContainedPieces(i)
+		if !verify || start == end {
+			if sameName || (verify && len(candidates) == 1) {
+				matches[i] = fileMatch{fileCandidate: candidates[0]}
+			}
+			continue
+		}
+
+		samples := samplePieces(start, end, crossSeedSamples)
+		for _, candidate := range candidates {
+			if verifySamples(info, i, candidate.path, samples) {
+				matches[i] = fileMatch{fileCandidate: candidate, verified: true}
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// boolCompare orders true before false.
+func boolCompare(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// samplePieces picks up to n pieces spread over [start, end).
+func samplePieces(start, end uint32, n int) []uint32 {
+	count := int(end - start)
+	if count <= n {
+		pieces := make([]uint32, 0, count)
+		for p := start; p < end; p++ {
+			pieces = append(pieces, p)
+		}
+		return pieces
+	}
+	pieces := make([]uint32, 0, n)
+	for k := range n {
+		pieces = append(pieces, start+uint32(k*(count-1)/(n-1)))
+	}
+	return pieces
+}
+
+// verifySamples reports whether pieces of file index hash the same with the
+// data of the file at path.
+func verifySamples(info *meta.Info, index int, path string, pieces []uint32) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	buf := make([]byte, info.PieceLength)
+	for _, piece := range pieces {
+		data := buf[:info.PieceLen(piece)]
+		if _, err := f.ReadAt(data, info.PieceOffsetInFile(index, piece)); err != nil && !errors.Is(err, io.EOF) {
+			return false
+		}
+		digest := sha1.Sum(data)
+		if !bytes.Equal(digest[:], info.Pieces[piece][:]) {
+			return false
+		}
+	}
+	return true
+}
+
+// prepareCrossSeed matches the files of info to the existing data of cs and
+// makes the torrent use it: remapped files point at the existing paths, the
+// others are linked into basePath. It returns the links it created and the
+// files that share their data with an existing file, which the torrent only
+// reads until its hash check confirms them. Files that already exist in
+// basePath are kept as they are.
+func (c *Client) prepareCrossSeed(info *meta.Info, basePath string, cs *CrossSeed) (links []string, shared []int, err error) {
+	bySize, err := c.crossSeedCandidates(cs)
+	if err != nil {
+		return nil, nil, err
+	}
+	matches := matchFiles(info, bySize, true)
+	log.Info().Stringer("info_hash", info.Hash).Int("files", len(info.Files)).Int("matched", len(matches)).
+		Msg("cross-seed: matched existing files")
+
+	for _, i := range slices.Sorted(maps.Keys(matches)) {
+		src := matches[i].path
+		if cs.Link == LinkRemap {
+			info.Files[i].SetPath(src)
+			shared = append(shared, i)
+			continue
+		}
+
+		dst := info.Files[i].FullPath(basePath)
+		if _, err := os.Lstat(dst); err == nil {
+			continue
+		}
+		// a path another torrent owns but did not write yet
+		if len(c.session.Paths.FileOwners(dst)) != 0 {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+			removeLinks(links)
+			return nil, nil, err
+		}
+		if cs.Link == LinkHardlink {
+			err = os.Link(src, dst)
+		} else {
+			err = reflink.Clone(src, dst)
+		}
+		if err != nil {
+			removeLinks(links)
+			return nil, nil, fmt.Errorf("failed to link %q to %q: %w", src, dst, err)
+		}
+		links = append(links, dst)
+		if cs.Link == LinkHardlink {
+			shared = append(shared, i)
+		}
+	}
+	return links, shared, nil
+}
+
+func removeLinks(links []string) {
+	for _, link := range links {
+		if err := os.Remove(link); err != nil {
+			log.Err(err).Str("path", link).Msg("failed to remove cross-seed link")
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package client
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trim21/go-bencode"
+
+	"neptune/internal/download"
+	"neptune/internal/meta"
+	"neptune/internal/metainfo"
+)
+
+const crossSeedPieceLength = 16 * 1024
+
+// crossSeedTorrent builds a torrent of a folder with an nfo file in front of
+// movie, so the pieces of movie are not aligned with the file.
+func crossSeedTorrent(t *testing.T, nfo, movie []byte) ([]byte, *metainfo.MetaInfo, meta.Info) {
+	t.Helper()
+	data := append(bytes.Clone(nfo), movie...)
+	var pieces []byte
+	for off := 0; off < len(data); off += crossSeedPieceLength {
+		digest := sha1.Sum(data[off:min(off+crossSeedPieceLength, len(data))])
+		pieces = append(pieces, digest[:]...)
+	}
+	infoBytes, err := bencode.Marshal(metainfo.Info{
+		Name:        "Movie.2026.Tracker",
+		PieceLength: crossSeedPieceLength,
+		Pieces:      pieces,
+		Files: []metainfo.FileInfo{
+			{Path: []string{"movie.nfo"}, Length: int64(len(nfo))},
+			{Path: []string{"movie.mkv"}, Length: int64(len(movie))},
+		},
+	})
+	require.NoError(t, err)
+
+	m := &metainfo.MetaInfo{InfoBytes: infoBytes}
+	info, err := meta.FromTorrent(*m)
+	require.NoError(t, err)
+	raw, err := bencode.Marshal(m)
+	require.NoError(t, err)
+	return raw, m, info
+}
+
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte(rand.IntN(256))
+	}
+	return b
+}
+
+// writeCrossSeedData writes the movie under another name, next to a file of
+// the same size with other data.
+func writeCrossSeedData(t *testing.T, movie []byte) (dir, src string) {
+	t.Helper()
+	dir = t.TempDir()
+	src = filepath.Join(dir, "Movie 2026.mkv")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "decoy.mkv"), bytes.Repeat([]byte{1}, len(movie)), 0o644))
+	require.NoError(t, os.WriteFile(src, movie, 0o644))
+	return dir, src
+}
+
+func TestMatchFiles(t *testing.T) {
+	movie := randomBytes(5*crossSeedPieceLength + 100)
+	_, _, info := crossSeedTorrent(t, randomBytes(300), movie)
+	dir, src := writeCrossSeedData(t, movie)
+
+	c := &Client{downloadMap: map[metainfo.Hash]*Download{}}
+	bySize, err := c.crossSeedCandidates(&CrossSeed{Paths: []string{dir}})
+	require.NoError(t, err)
+
+	require.Equal(t, map[int]fileMatch{1: {fileCandidate: fileCandidate{path: src, file: -1}, verified: true}}, matchFiles(&info, bySize, true), "the nfo has no candidate, the decoy fails the hash check")
+	require.Empty(t, matchFiles(&info, bySize, false), "no file has the same name")
+}
+
+func TestSamplePieces(t *testing.T) {
+	require.Equal(t, []uint32{2, 3}, samplePieces(2, 4, 3))
+	require.Equal(t, []uint32{0, 4, 9}, samplePieces(0, 10, 3))
+	require.Empty(t, samplePieces(3, 3, 3))
+}
+
+func TestAddTorrentCrossSeed(t *testing.T) {
+	movie := randomBytes(5*crossSeedPieceLength + 100)
+	nfo := randomBytes(300)
+
+	for _, link := range []LinkMode{LinkRemap, LinkHardlink} {
+		c := newPathTestClient(t, "")
+		dir, src := writeCrossSeedData(t, movie)
+		downloadDir := t.TempDir()
+
+		raw, m, info := crossSeedTorrent(t, nfo, movie)
+		err := c.AddTorrent(raw, m, info, downloadDir, AddTorrentOptions{
+			SkipHashCheck: true,
+			CrossSeed:     &CrossSeed{Paths: []string{dir}, Link: link},
+		})
+		require.NoError(t, err)
+
+		if link == LinkHardlink {
+			a, err := os.Stat(src)
+			require.NoError(t, err)
+			b, err := os.Stat(filepath.Join(downloadDir, "movie.mkv"))
+			require.NoError(t, err)
+			require.True(t, os.SameFile(a, b))
+		} else {
+			owners, err := c.PathOwners(src)
+			require.NoError(t, err)
+			require.Equal(t, []PathOwner{{Path: src, InfoHash: info.Hash.Hex(), File: 1}}, owners)
+		}
+
+		// the pieces of the movie that do not hold nfo data are verified, the
+		// rest is downloaded
+		require.Eventually(t, func() bool {
+			return c.GetTorrentFiles(info.Hash)[1].Progress > 0.5
+		}, 10*time.Second, 10*time.Millisecond, "link mode %d", link)
+		require.Zero(t, c.GetTorrentFiles(info.Hash)[0].Progress)
+	}
+}
+
+func TestAddTorrentCrossSeedLeavesUnverifiedData(t *testing.T) {
+	movie := randomBytes(5*crossSeedPieceLength + 100)
+	nfo := randomBytes(300)
+
+	// the sampled pieces match, one piece in between does not
+	bad := bytes.Clone(movie)
+	bad[2*crossSeedPieceLength] ^= 0xff
+
+	for _, link := range []LinkMode{LinkRemap, LinkHardlink} {
+		c := newPathTestClient(t, "")
+		dir, src := writeCrossSeedData(t, bad)
+		downloadDir := t.TempDir()
+
+		raw, m, info := crossSeedTorrent(t, nfo, movie)
+		err := c.AddTorrent(raw, m, info, downloadDir, AddTorrentOptions{
+			SkipHashCheck: true,
+			CrossSeed:     &CrossSeed{Paths: []string{dir}, Link: link},
+		})
+		require.NoError(t, err)
+
+		// the hash check finds the bad piece and gives the torrent its own
+		// copy of the file, or none
+		require.Eventually(t, func() bool {
+			owners, err := c.PathOwners(src)
+			return err == nil && len(owners) == 0 && c.downloadMap[info.Hash].GetState() != download.Checking
+		}, 10*time.Second, 10*time.Millisecond, "link mode %d", link)
+		if stat, err := os.Stat(filepath.Join(downloadDir, "movie.mkv")); err == nil {
+			a, err := os.Stat(src)
+			require.NoError(t, err)
+			require.False(t, os.SameFile(a, stat), "only a clone is allowed")
+		}
+		got, err := os.ReadFile(src)
+		require.NoError(t, err)
+		require.Equal(t, bad, got)
+	}
+}
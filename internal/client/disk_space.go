@@ -0,0 +1,226 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package client
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+	"github.com/trim21/errgo"
+
+	"neptune/internal/download"
+	"neptune/internal/pkg/disk_io"
+	"neptune/internal/pkg/diskfree"
+)
+
+const diskSpaceCheckInterval = 30 * time.Second
+
+// diskSpaceResumeMargin is how far above min-free-space a filesystem has to be
+// before its paused torrents resume. Without it a torrent near the limit is
+// paused again by the data it writes right after resuming.
+const diskSpaceResumeMargin = 256 << 20
+
+// DiskSpace is the free space of a filesystem holding torrent data.
+type DiskSpace struct {
+	// MountPoint is where the filesystem is mounted, or the data path of its
+	// first torrent when the mount is unknown.
+	MountPoint string `json:"mount_point"`
+	Device     string `json:"device"`
+	Available  uint64 `json:"available"`
+	Torrents   int    `json:"torrents"`
+	// Paused is the number of torrents paused for low disk space.
+	Paused int  `json:"paused"`
+	Low    bool `json:"low"`
+}
+
+type diskSpaceMetrics struct {
+	available *prometheus.GaugeVec
+	paused    *prometheus.GaugeVec
+}
+
+func newDiskSpaceMetrics() diskSpaceMetrics {
+	labels := []string{"device", "mount_point"}
+	return diskSpaceMetrics{
+		available: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "neptune_disk_available_bytes",
+			Help: "Free disk space of filesystems holding torrent data.",
+		}, labels),
+		paused: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "neptune_disk_space_paused_torrents",
+			Help: "Number of torrents paused for low disk space.",
+		}, labels),
+	}
+}
+
+func (m diskSpaceMetrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.available, m.paused}
+}
+
+// diskSpaceKey identifies a filesystem. Paths the IO scheduler cannot map to
+// a device are kept apart by path.
+type diskSpaceKey struct {
+	path string
+	id   disk_io.DeviceID
+}
+
+type diskSpaceGroup struct {
+	path      string
+	downloads []*Download
+	space     DiskSpace
+	// resume is true when there is enough free space to resume the paused
+	// torrents.
+	resume bool
+}
+
+// startDiskSpaceWatchdog pauses the downloading torrents of a filesystem
+// whose free space drops below min-free-space, and resumes them once it is
+// diskSpaceResumeMargin above.
+func (c *Client) startDiskSpaceWatchdog() {
+	ticker := time.NewTicker(diskSpaceCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.session.Ctx.Done():
+			return
+		case <-ticker.C:
+			c.checkDiskSpace()
+		}
+	}
+}
+
+func (c *Client) checkDiskSpace() {
+	groups := c.diskSpaceGroups()
+
+	c.diskSpaceMetrics.available.Reset()
+	c.diskSpaceMetrics.paused.Reset()
+
+	resumed := false
+	for _, g := range groups {
+		g.space.Paused = 0
+		for _, d := range g.downloads {
+			if g.space.Low {
+				if d.PauseForDiskSpace() {
+					log.Warn().Stringer("info_hash", d.InfoHash()).Str("mount_point", g.space.MountPoint).
+						Uint64("available", g.space.Available).Msg("pause torrent for low disk space")
+				}
+			} else if g.resume && d.ResumeForDiskSpace() {
+				log.Info().Stringer("info_hash", d.InfoHash()).Str("mount_point", g.space.MountPoint).
+					Msg("resume torrent paused for low disk space")
+				resumed = true
+			}
+			if d.DiskSpacePaused() {
+				g.space.Paused++
+			}
+		}
+
+		c.diskSpaceMetrics.available.WithLabelValues(g.space.Device, g.space.MountPoint).Set(float64(g.space.Available))
+		c.diskSpaceMetrics.paused.WithLabelValues(g.space.Device, g.space.MountPoint).Set(float64(g.space.Paused))
+	}
+
+	// resumed torrents compete for download slots again
+	if resumed {
+		c.triggerQueueRebalance()
+	}
+}
+
+// DiskSpace returns the free space of every filesystem holding torrent data.
+func (c *Client) DiskSpace() []DiskSpace {
+	groups := c.diskSpaceGroups()
+	spaces := make([]DiskSpace, len(groups))
+	for i, g := range groups {
+		spaces[i] = g.space
+	}
+	return spaces
+}
+
+// diskSpaceGroups groups the torrents by the filesystem holding their data,
+// sorted by mount point.
+func (c *Client) diskSpaceGroups() []*diskSpaceGroup {
+	c.m.RLock()
+	downloads := slices.Clone(c.downloads)
+	c.m.RUnlock()
+
+	minFree := c.session.Config.App.MinFreeSpace
+	byKey := make(map[diskSpaceKey]*diskSpaceGroup)
+	var groups []*diskSpaceGroup
+	for _, d := range downloads {
+		path := d.BasePath()
+		id, _ := c.session.IOContext.DeviceForPath(path)
+		key := diskSpaceKey{id: id}
+		if id == (disk_io.DeviceID{}) {
+			key.path = path
+		}
+
+		g, ok := byKey[key]
+		if !ok {
+			g = &diskSpaceGroup{path: path, space: DiskSpace{MountPoint: path, Device: "default"}}
+			if id != (disk_io.DeviceID{}) {
+				g.space.Device = id.String()
+				if mount := c.session.IOContext.MountPoint(id); mount != "" {
+					g.space.MountPoint = mount
+				}
+			}
+			byKey[key] = g
+			groups = append(groups, g)
+		}
+		g.downloads = append(g.downloads, d)
+		g.space.Torrents++
+		if d.DiskSpacePaused() {
+			g.space.Paused++
+		}
+	}
+
+	groups = slices.DeleteFunc(groups, func(g *diskSpaceGroup) bool {
+		available, err := diskfree.AvailableFor(g.path)
+		if err != nil {
+			log.Warn().Err(err).Str("path", g.path).Msg("failed to get free disk space")
+			return true
+		}
+		g.space.Available = available
+		g.space.Low = minFree > 0 && available < uint64(minFree)
+		g.resume = minFree <= 0 || available >= uint64(minFree)+diskSpaceResumeMargin
+		return false
+	})
+	slices.SortFunc(groups, func(a, b *diskSpaceGroup) int {
+		return cmp.Compare(a.space.MountPoint, b.space.MountPoint)
+	})
+	return groups
+}
+
+// checkFreeSpace fails if basePath does not have need bytes free on top of
+// min-free-space.
+func (c *Client) checkFreeSpace(basePath string, need int64) error {
+	if need <= 0 {
+		return nil
+	}
+	available, err := diskfree.AvailableFor(basePath)
+	if err != nil {
+		return errgo.Wrap(err, "failed to get free disk space")
+	}
+	reserve := uint64(max(c.session.Config.App.MinFreeSpace, 0))
+	if available < reserve || uint64(need) > available-reserve {
+		return fmt.Errorf("not enough disk space in %q: torrent needs %s, %s available, %s reserved by min-free-space",
+			basePath, humanize.IBytes(uint64(need)), humanize.IBytes(available), humanize.IBytes(reserve))
+	}
+	return nil
+}
+
+// checkStartSpace is checkFreeSpace for a torrent about to start. Torrents
+// allocated up front fail their own check if the disk is too small.
+func (c *Client) checkStartSpace(d *Download) error {
+	if d.Allocation() != download.AllocSparse {
+		return nil
+	}
+	need, err := d.RequiredSpace()
+	if err != nil {
+		return err
+	}
+	return c.checkFreeSpace(d.BasePath(), need)
+}
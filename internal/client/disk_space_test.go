@@ -0,0 +1,73 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/config"
+	"neptune/internal/download"
+	"neptune/internal/session"
+	"neptune/internal/session/store"
+)
+
+func TestCheckFreeSpace(t *testing.T) {
+	c := &Client{session: &session.Session{}}
+	dir := t.TempDir()
+
+	require.NoError(t, c.checkFreeSpace(dir, 1))
+	require.NoError(t, c.checkFreeSpace(dir, 0))
+
+	err := c.checkFreeSpace(dir, 1<<62)
+	require.ErrorContains(t, err, "not enough disk space")
+
+	c.session.Config.App.MinFreeSpace = 1 << 62
+	require.ErrorContains(t, c.checkFreeSpace(dir, 1), "reserved by min-free-space")
+}
+
+func TestDiskSpaceWatchdog(t *testing.T) {
+	resetMetrics()
+	sessionPath := t.TempDir()
+	hash := writeTorrentFile(t, sessionPath, "test.data")
+
+	c := New(config.Config{App: config.Application{
+		P2PPort:                randomPort(t),
+		MaxHTTPParallel:        4,
+		GlobalConnectionLimit:  100,
+		TorrentConnectionLimit: 20,
+	}}, sessionPath, false)
+	t.Cleanup(c.Shutdown)
+
+	require.NoError(t, c.loadFromResume(store.Resume{
+		BasePath: t.TempDir(),
+		InfoHash: hash.Hex(),
+		State:    store.ResumeActive,
+	}, 1))
+	d := c.downloadMap[hash]
+	require.Equal(t, download.Downloading, d.GetState())
+
+	c.session.Config.App.MinFreeSpace = 1 << 62
+	c.checkDiskSpace()
+	require.True(t, d.DiskSpacePaused())
+	require.Equal(t, download.PendingDownloading, d.GetState())
+
+	spaces := c.DiskSpace()
+	require.Len(t, spaces, 1)
+	require.True(t, spaces[0].Low)
+	require.Equal(t, 1, spaces[0].Torrents)
+	require.Equal(t, 1, spaces[0].Paused)
+
+	// just above the limit is not enough to resume
+	c.session.Config.App.MinFreeSpace = int64(spaces[0].Available) - diskSpaceResumeMargin/2
+	c.checkDiskSpace()
+	require.True(t, d.DiskSpacePaused())
+	require.False(t, c.DiskSpace()[0].Low)
+
+	c.session.Config.App.MinFreeSpace = 0
+	c.checkDiskSpace()
+	require.False(t, d.DiskSpacePaused())
+	require.Equal(t, download.Downloading, d.GetState())
+}
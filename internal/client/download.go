@@ -4,13 +4,17 @@
 package client
 
 import (
+	"errors"
+	"slices"
 	"time"
 
+	"github.com/rs/zerolog/log"
 	"github.com/samber/lo"
 
 	"neptune/internal/download"
 	"neptune/internal/meta"
 	"neptune/internal/metainfo"
+	"neptune/internal/piece_store"
 	"neptune/internal/session/store"
 )
 
@@ -18,15 +22,21 @@ func (c *Client) NewDownload(
 	m *metainfo.MetaInfo,
 	info meta.Info,
 	basePath string,
+	completePath string,
 	tags []string,
 	custom map[string]string,
 	selectedFiles []int,
+	sharedFiles []int,
 	skipHashCheck bool,
+	allocation download.Allocation,
 ) (*Download, error) {
 	return download.New(c.session, m, info, basePath, tags, custom, selectedFiles, download.InitState{
 		State:             download.Checking,
+		CompletePath:      completePath,
 		PiecePickStrategy: download.PiecePickStrategy(c.piecePickStrategy.Load()),
 		SkipHashCheck:     skipHashCheck,
+		Allocation:        allocation,
+		SharedFiles:       sharedFiles,
 	})
 }
 
@@ -52,12 +62,74 @@ func (c *Client) loadFromResume(r store.Resume, totalDownloads int) error {
 	return nil
 }
 
+// ScheduleMove starts moving the data of a torrent to targetBasePath. The
+// move runs in the background and reports progress in the torrent list.
 func (c *Client) ScheduleMove(ih metainfo.Hash, targetBasePath string) error {
+	d, release, err := c.reserveLocation(ih, targetBasePath)
+	if err != nil {
+		return err
+	}
+	return d.StartMove(targetBasePath, release)
+}
+
+// SetLocation points a torrent at data already moved to basePath by the
+// user, without touching any file.
+func (c *Client) SetLocation(ih metainfo.Hash, basePath string, recheck bool) error {
+	d, release, err := c.reserveLocation(ih, basePath)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return d.SetLocation(basePath, recheck)
+}
+
+func (c *Client) CancelMove(ih metainfo.Hash) error {
 	c.m.RLock()
 	d, ok := c.downloadMap[ih]
 	c.m.RUnlock()
 	if !ok {
 		return download.ErrTorrentNotFound
 	}
-	return d.RequestMove(targetBasePath)
+	if !d.HasState(download.Moving) {
+		return errors.New("torrent is not being moved")
+	}
+	d.CancelMove()
+	return nil
+}
+
+// recoverMoves finishes or rolls back the data moves interrupted by a crash,
+// and points the resume records of finished moves at the new location.
+func (c *Client) recoverMoves(all []store.Resume) error {
+	moves, err := c.session.Store.Moves()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range moves {
+		i := slices.IndexFunc(all, func(r store.Resume) bool { return r.InfoHash == m.InfoHash })
+		if i < 0 {
+			if err := c.session.Store.DeleteMove(m.InfoHash); err != nil {
+				return err
+			}
+			continue
+		}
+
+		log.Info().Str("info_hash", m.InfoHash).Str("source", m.Source).Str("target", m.Target).
+			Bool("committed", m.Committed).Msg("recovering interrupted move")
+		if m.Committed {
+			all[i].BasePath = m.Target
+			if err := c.session.Store.Upsert(&all[i]); err != nil {
+				return err
+			}
+		}
+		if err := piece_store.RecoverMove(m.Files, m.Source, m.Target, m.Committed); err != nil {
+			// keep the journal, recovery is retried on the next start
+			log.Err(err).Str("info_hash", m.InfoHash).Msg("failed to recover interrupted move")
+			continue
+		}
+		if err := c.session.Store.DeleteMove(m.InfoHash); err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -0,0 +1,98 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package client
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"neptune/internal/download"
+	"neptune/internal/metainfo"
+)
+
+// ExportedTorrent is the torrent file and magnet link of a loaded torrent.
+type ExportedTorrent struct {
+	Magnet string
+	// Torrent is the bencoded torrent file.
+	Torrent []byte
+}
+
+// ExportTorrent returns the torrent file of a loaded torrent as it was added,
+// or with trackers, with its announce list replaced by the current trackers.
+// The magnet link always has the current trackers.
+func (c *Client) ExportTorrent(ih metainfo.Hash, trackers bool) (ExportedTorrent, error) {
+	c.m.RLock()
+	d, ok := c.downloadMap[ih]
+	c.m.RUnlock()
+	if !ok {
+		return ExportedTorrent{}, download.ErrTorrentNotFound
+	}
+
+	raw, err := exportTorrentFile(d, trackers)
+	if err != nil {
+		return ExportedTorrent{}, err
+	}
+	return ExportedTorrent{
+		Torrent: raw,
+		Magnet:  metainfo.MagnetURI(ih, d.Name(), d.AnnounceList()),
+	}, nil
+}
+
+func exportTorrentFile(d *Download, trackers bool) ([]byte, error) {
+	raw, err := os.ReadFile(d.TorrentFilePath())
+	if err != nil {
+		return nil, err
+	}
+	if !trackers {
+		return raw, nil
+	}
+	return metainfo.SetAnnounceList(raw, d.AnnounceList())
+}
+
+// ExportTorrents writes the torrent files of the given torrents, all of them
+// when hashes is empty, to w as a tar archive of "<info hash>.torrent"
+// entries. Unknown torrents fail before anything is written.
+func (c *Client) ExportTorrents(w io.Writer, hashes []metainfo.Hash, trackers bool) error {
+	c.m.RLock()
+	var downloads []*Download
+	if len(hashes) == 0 {
+		downloads = append(downloads, c.downloads...)
+	} else {
+		for _, ih := range hashes {
+			d, ok := c.downloadMap[ih]
+			if !ok {
+				c.m.RUnlock()
+				return fmt.Errorf("%w: %s", download.ErrTorrentNotFound, ih)
+			}
+			downloads = append(downloads, d)
+		}
+	}
+	c.m.RUnlock()
+
+	tw := tar.NewWriter(w)
+	now := time.Now()
+	for _, d := range downloads {
+		raw, err := exportTorrentFile(d, trackers)
+		if err != nil {
+			return err
+		}
+		err = tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     d.InfoHashHex() + ".torrent",
+			Size:     int64(len(raw)),
+			Mode:     0o644,
+			ModTime:  now,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := tw.Write(raw); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
@@ -0,0 +1,77 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package client
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/download"
+	"neptune/internal/metainfo"
+)
+
+func TestExportTorrent(t *testing.T) {
+	c := newPathTestClient(t, "")
+	ih, err := addPathTestTorrent(t, c, t.TempDir(), "movie.mkv", "", 1)
+	require.NoError(t, err)
+	require.NoError(t, c.AddTracker(ih, "https://tracker.example/announce", 0))
+
+	exported, err := c.ExportTorrent(ih, false)
+	require.NoError(t, err)
+	m, err := metainfo.Load(exported.Torrent)
+	require.NoError(t, err)
+	require.Equal(t, ih, m.HashInfoBytes())
+	require.Empty(t, m.Announce)
+	require.Equal(t, "magnet:?xt=urn:btih:"+ih.Hex()+"&dn=movie.mkv&tr=https%3A%2F%2Ftracker.example%2Fannounce", exported.Magnet)
+
+	exported, err = c.ExportTorrent(ih, true)
+	require.NoError(t, err)
+	m, err = metainfo.Load(exported.Torrent)
+	require.NoError(t, err)
+	require.Equal(t, ih, m.HashInfoBytes())
+	require.Equal(t, "https://tracker.example/announce", m.Announce)
+	require.Equal(t, metainfo.AnnounceList{{"https://tracker.example/announce"}}, m.AnnounceList)
+
+	_, err = c.ExportTorrent(metainfo.Hash{1}, false)
+	require.ErrorIs(t, err, download.ErrTorrentNotFound)
+}
+
+func TestExportTorrents(t *testing.T) {
+	c := newPathTestClient(t, "")
+	a, err := addPathTestTorrent(t, c, t.TempDir(), "a.mkv", "", 1)
+	require.NoError(t, err)
+	b, err := addPathTestTorrent(t, c, t.TempDir(), "b.mkv", "", 2)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, c.ExportTorrents(&buf, nil, false))
+
+	names := map[string]bool{}
+	tr := tar.NewReader(&buf)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		raw, err := io.ReadAll(tr)
+		require.NoError(t, err)
+		m, err := metainfo.Load(raw)
+		require.NoError(t, err)
+		require.Equal(t, m.HashInfoBytes().Hex()+".torrent", h.Name)
+		names[h.Name] = true
+	}
+	require.Equal(t, map[string]bool{a.Hex() + ".torrent": true, b.Hex() + ".torrent": true}, names)
+
+	buf.Reset()
+	err = c.ExportTorrents(&buf, []metainfo.Hash{a, {1}}, false)
+	require.ErrorIs(t, err, download.ErrTorrentNotFound)
+	require.Zero(t, buf.Len(), "nothing is written for an unknown torrent")
+}
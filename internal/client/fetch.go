@@ -0,0 +1,160 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"neptune/internal/config"
+	"neptune/internal/pkg/global"
+)
+
+var (
+	// ErrMagnetRedirect is returned when a torrent URL redirects to a magnet
+	// link, which can't be added.
+	ErrMagnetRedirect  = errors.New("url redirects to a magnet link, adding magnet links is not supported")
+	ErrTorrentTooLarge = errors.New("torrent file is larger than max-rpc-request-body-size")
+)
+
+const (
+	defaultFetchTimeout      = 30 * time.Second
+	defaultFetchMaxRedirects = 10
+)
+
+// FetchTorrentFile downloads a torrent file from an http or https URL. Each
+// request, redirects included, carries the headers and cookies configured for
+// its own domain only.
+func (c *Client) FetchTorrentFile(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("only http and https urls are supported, got %q", u.Scheme)
+	}
+
+	cfg := c.session.Config.App.Fetch
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultFetchTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", global.UserAgent)
+	applyFetchDomain(req, cfg.Domains)
+
+	resp, err := newFetchClient(cfg).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if loc := resp.Header.Get("Location"); isRedirect(resp.StatusCode) && strings.HasPrefix(strings.ToLower(loc), "magnet:") {
+		return nil, fmt.Errorf("%w: %s", ErrMagnetRedirect, loc)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	// the same limit as a torrent file sent to torrent.add, 0 means no limit
+	limit := c.session.Config.App.MaxRequestBodySize
+	if limit <= 0 {
+		return io.ReadAll(resp.Body)
+	}
+	if resp.ContentLength > limit {
+		return nil, ErrTorrentTooLarge
+	}
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(raw)) > limit {
+		return nil, ErrTorrentTooLarge
+	}
+	return raw, nil
+}
+
+func newFetchClient(cfg config.FetchConfig) *http.Client {
+	maxRedirects := cfg.MaxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = defaultFetchMaxRedirects
+	}
+
+	return &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			// a magnet link is handed back to the caller as the response
+			if req.URL.Scheme == "magnet" {
+				return http.ErrUseLastResponse
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("redirect to unsupported url scheme %q", req.URL.Scheme)
+			}
+			if len(via) > maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+
+			// the headers of the first request are copied to every redirect,
+			// no domain of an earlier hop may leak its headers to another one
+			for _, prev := range via {
+				if d, ok := fetchDomain(cfg.Domains, prev.URL.Hostname()); ok {
+					for k := range d.Headers {
+						req.Header.Del(k)
+					}
+				}
+			}
+			req.Header.Del("Cookie")
+			applyFetchDomain(req, cfg.Domains)
+			return nil
+		},
+	}
+}
+
+func isRedirect(status int) bool {
+	return status >= 300 && status < 400
+}
+
+// applyFetchDomain adds the headers and cookies configured for the host of
+// req.
+func applyFetchDomain(req *http.Request, domains map[string]config.FetchDomain) {
+	d, ok := fetchDomain(domains, req.URL.Hostname())
+	if !ok {
+		return
+	}
+	for k, v := range d.Headers {
+		req.Header.Set(k, v)
+	}
+	for name, value := range d.Cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+}
+
+// fetchDomain returns the config of the longest domain that host is or is a
+// subdomain of.
+func fetchDomain(domains map[string]config.FetchDomain, host string) (config.FetchDomain, bool) {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	var best config.FetchDomain
+	bestLen := -1
+	for domain, cfg := range domains {
+		d := strings.ToLower(strings.TrimSuffix(domain, "."))
+		if host != d && !strings.HasSuffix(host, "."+d) {
+			continue
+		}
+		if len(d) > bestLen {
+			best, bestLen = cfg, len(d)
+		}
+	}
+	return best, bestLen >= 0
+}
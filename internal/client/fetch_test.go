@@ -0,0 +1,156 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package client
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/config"
+)
+
+func TestFetchTorrentFile(t *testing.T) {
+	c := newPathTestClient(t, "")
+	c.session.Config.App.MaxRequestBodySize = 16
+	c.session.Config.App.Fetch.Domains = map[string]config.FetchDomain{
+		"127.0.0.1": {
+			Headers: map[string]string{"X-Passkey": "secret"},
+			Cookies: map[string]string{"uid": "1"},
+		},
+	}
+
+	// the other host must not see the headers of 127.0.0.1
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Passkey") != "" || r.Header.Get("Cookie") != "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("d4:infodee"))
+	}))
+	t.Cleanup(other.Close)
+	otherURL := strings.Replace(other.URL, "127.0.0.1", "localhost", 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("uid")
+		if r.Header.Get("X-Passkey") != "secret" || err != nil || cookie.Value != "1" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/a.torrent":
+			_, _ = w.Write([]byte("d4:infodee"))
+		case "/redirect":
+			http.Redirect(w, r, "/a.torrent", http.StatusFound)
+		case "/chain":
+			http.Redirect(w, r, "/redirect", http.StatusFound)
+		case "/other":
+			http.Redirect(w, r, otherURL+"/a.torrent", http.StatusFound)
+		case "/magnet":
+			http.Redirect(w, r, "magnet:?xt=urn:btih:"+strings.Repeat("a", 40), http.StatusFound)
+		case "/large":
+			_, _ = w.Write([]byte(strings.Repeat("a", 17)))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	for _, path := range []string{"/a.torrent", "/redirect", "/other"} {
+		raw, err := c.FetchTorrentFile(t.Context(), srv.URL+path)
+		require.NoError(t, err, path)
+		require.Equal(t, "d4:infodee", string(raw))
+	}
+
+	_, err := c.FetchTorrentFile(t.Context(), srv.URL+"/magnet")
+	require.ErrorIs(t, err, ErrMagnetRedirect)
+
+	_, err = c.FetchTorrentFile(t.Context(), srv.URL+"/large")
+	require.ErrorIs(t, err, ErrTorrentTooLarge)
+
+	_, err = c.FetchTorrentFile(t.Context(), srv.URL+"/missing")
+	require.Error(t, err)
+
+	_, err = c.FetchTorrentFile(t.Context(), "ftp://127.0.0.1/a.torrent")
+	require.Error(t, err)
+
+	c.session.Config.App.Fetch.MaxRedirects = 1
+	_, err = c.FetchTorrentFile(t.Context(), srv.URL+"/other")
+	require.NoError(t, err)
+	_, err = c.FetchTorrentFile(t.Context(), srv.URL+"/chain")
+	require.ErrorContains(t, err, "stopped after 1 redirects")
+}
+
+func TestFetchTorrentFileRedirectChain(t *testing.T) {
+	c := newPathTestClient(t, "")
+	c.session.Config.App.Fetch.Domains = map[string]config.FetchDomain{
+		"127.0.0.1": {Headers: map[string]string{"X-Passkey": "secret"}},
+		"localhost": {Headers: map[string]string{"X-Token": "token"}},
+	}
+
+	// 127.0.0.1 -> localhost -> 127.0.0.2, the last host has no config and
+	// must see the headers of neither earlier hop
+	ln, err := net.Listen("tcp", "127.0.0.2:0")
+	if err != nil {
+		t.Skipf("can not listen on 127.0.0.2: %v", err)
+	}
+	last := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Passkey") != "" || r.Header.Get("X-Token") != "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("d4:infodee"))
+	}))
+	_ = last.Listener.Close()
+	last.Listener = ln
+	last.Start()
+	t.Cleanup(last.Close)
+
+	middle := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Passkey") != "" || r.Header.Get("X-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		http.Redirect(w, r, last.URL+"/a.torrent", http.StatusFound)
+	}))
+	t.Cleanup(middle.Close)
+	middleURL := strings.Replace(middle.URL, "127.0.0.1", "localhost", 1)
+
+	first := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Passkey") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		http.Redirect(w, r, middleURL+"/redirect", http.StatusFound)
+	}))
+	t.Cleanup(first.Close)
+
+	raw, err := c.FetchTorrentFile(t.Context(), first.URL+"/a.torrent")
+	require.NoError(t, err)
+	require.Equal(t, "d4:infodee", string(raw))
+}
+
+func TestFetchDomain(t *testing.T) {
+	domains := map[string]config.FetchDomain{
+		"example.org":         {Cookies: map[string]string{"a": "1"}},
+		"tracker.example.org": {Cookies: map[string]string{"b": "2"}},
+	}
+
+	d, ok := fetchDomain(domains, "tracker.example.org")
+	require.True(t, ok)
+	require.Equal(t, "2", d.Cookies["b"])
+
+	d, ok = fetchDomain(domains, "dl.Example.org")
+	require.True(t, ok)
+	require.Equal(t, "1", d.Cookies["a"])
+
+	_, ok = fetchDomain(domains, "badexample.org")
+	require.False(t, ok)
+}
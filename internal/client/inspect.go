@@ -0,0 +1,107 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package client
+
+import (
+	"time"
+
+	"github.com/trim21/errgo"
+
+	"neptune/internal/meta"
+	"neptune/internal/metainfo"
+)
+
+// InspectedFile is a file of an inspected torrent.
+type InspectedFile struct {
+	Path   string `json:"path"`
+	Index  int    `json:"index"`
+	Length int64  `json:"length"`
+}
+
+// FileTreeNode is a file or directory of an inspected torrent. Directories
+// have the total length of the files under them and an index of -1.
+type FileTreeNode struct {
+	Name     string          `json:"name"`
+	Children []*FileTreeNode `json:"children,omitempty"`
+	Length   int64           `json:"length"`
+	Index    int             `json:"index"`
+}
+
+// TorrentInspection is what a torrent file holds.
+type TorrentInspection struct {
+	CreationDate time.Time
+	Tree         *FileTreeNode
+	Name         string
+	Comment      string
+	CreatedBy    string
+	Source       string
+	Files        []InspectedFile
+	Trackers     metainfo.AnnounceList
+	TotalLength  int64
+	PieceLength  int64
+	NumPieces    uint32
+	InfoHash     metainfo.Hash
+	Private      bool
+	Loaded       bool
+}
+
+// InspectTorrent checks a parsed torrent the way AddTorrent does and
+// describes it, without touching the session or the disk.
+func (c *Client) InspectTorrent(m *metainfo.MetaInfo, info meta.Info) (TorrentInspection, error) {
+	if err := validateTorrentPaths(c.session.Config.App.DownloadDir, info); err != nil {
+		return TorrentInspection{}, errgo.Wrap(err, "invalid torrent file paths")
+	}
+
+	c.m.RLock()
+	_, loaded := c.downloadMap[info.Hash]
+	c.m.RUnlock()
+
+	r := TorrentInspection{
+		InfoHash:    info.Hash,
+		Name:        info.Name,
+		Comment:     m.Comment,
+		CreatedBy:   info.CreatedBy,
+		Source:      info.Source,
+		Trackers:    m.UpvertedAnnounceList(),
+		TotalLength: info.TotalLength,
+		PieceLength: info.PieceLength,
+		NumPieces:   info.NumPieces,
+		Private:     info.Private,
+		Loaded:      loaded,
+		Files:       make([]InspectedFile, len(info.Files)),
+		Tree:        &FileTreeNode{Name: info.Name, Index: -1},
+	}
+	if info.CreationDate > 0 {
+		r.CreationDate = time.Unix(info.CreationDate, 0)
+	}
+
+	for i, f := range info.Files {
+		r.Files[i] = InspectedFile{Path: f.Path, Index: i, Length: f.Length}
+		r.Tree.add(f.RawPath, i, f.Length)
+	}
+	if raw, err := m.UnmarshalInfo(); err == nil && len(raw.Files) == 0 {
+		// the tree of a single file torrent is the file itself
+		r.Tree = r.Tree.Children[0]
+	}
+	return r, nil
+}
+
+// add inserts the file at path under n, adding its length to every directory
+// on the way.
+func (n *FileTreeNode) add(path []string, index int, length int64) {
+	n.Length += length
+	if len(path) == 1 {
+		n.Children = append(n.Children, &FileTreeNode{Name: path[0], Length: length, Index: index})
+		return
+	}
+	for _, child := range n.Children {
+		if child.Index == -1 && child.Name == path[0] {
+			child.add(path[1:], index, length)
+			return
+		}
+	}
+	dir := &FileTreeNode{Name: path[0], Index: -1}
+	n.Children = append(n.Children, dir)
+	dir.add(path[1:], index, length)
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trim21/go-bencode"
+
+	"neptune/internal/meta"
+	"neptune/internal/metainfo"
+)
+
+func inspectTestTorrent(t *testing.T, files []metainfo.FileInfo) ([]byte, *metainfo.MetaInfo, meta.Info) {
+	t.Helper()
+	infoBytes, err := bencode.Marshal(metainfo.Info{
+		Name:        "Show",
+		PieceLength: crossSeedPieceLength,
+		Pieces:      make([]byte, 20),
+		Files:       files,
+	})
+	require.NoError(t, err)
+
+	raw, err := bencode.Marshal(&metainfo.MetaInfo{
+		InfoBytes:    infoBytes,
+		Announce:     "https://tracker.example/announce",
+		Comment:      "season one",
+		CreatedBy:    "mktorrent 1.1",
+		CreationDate: 1700000000,
+	})
+	require.NoError(t, err)
+	m, err := metainfo.Load(raw)
+	require.NoError(t, err)
+	info, err := meta.FromTorrent(*m)
+	require.NoError(t, err)
+	return raw, m, info
+}
+
+func TestInspectTorrent(t *testing.T) {
+	c := newPathTestClient(t, "")
+	raw, m, info := inspectTestTorrent(t, []metainfo.FileInfo{
+		{Path: []string{"s01", "e01.mkv"}, Length: 100},
+		{Path: []string{"s01", "e02.mkv"}, Length: 200},
+		{Path: []string{"show.nfo"}, Length: 10},
+	})
+
+	r, err := c.InspectTorrent(m, info)
+	require.NoError(t, err)
+	require.Equal(t, info.Hash, r.InfoHash)
+	require.Equal(t, "Show", r.Name)
+	require.Equal(t, "season one", r.Comment)
+	require.Equal(t, "mktorrent 1.1", r.CreatedBy)
+	require.Equal(t, time.Unix(1700000000, 0), r.CreationDate)
+	require.Equal(t, metainfo.AnnounceList{{"https://tracker.example/announce"}}, r.Trackers)
+	require.Equal(t, int64(310), r.TotalLength)
+	require.Equal(t, uint32(1), r.NumPieces)
+	require.False(t, r.Loaded)
+	require.Len(t, r.Files, 3)
+	require.Equal(t, &FileTreeNode{Name: "Show", Length: 310, Index: -1, Children: []*FileTreeNode{
+		{Name: "s01", Length: 300, Index: -1, Children: []*FileTreeNode{
+			{Name: "e01.mkv", Length: 100, Index: 0},
+			{Name: "e02.mkv", Length: 200, Index: 1},
+		}},
+		{Name: "show.nfo", Length: 10, Index: 2},
+	}}, r.Tree)
+	require.Empty(t, c.downloads, "inspecting adds nothing")
+
+	require.NoError(t, c.AddTorrent(raw, m, info, t.TempDir(), AddTorrentOptions{SkipHashCheck: true}))
+	r, err = c.InspectTorrent(m, info)
+	require.NoError(t, err)
+	require.True(t, r.Loaded)
+
+	got, err := c.GetTorrent(info.Hash)
+	require.NoError(t, err)
+	require.Equal(t, "mktorrent 1.1", got.CreatedBy)
+	require.Equal(t, int64(1700000000), got.CreationDate)
+	require.Equal(t, "season one", got.Comment)
+}
+
+func TestInspectTorrentSingleFile(t *testing.T) {
+	c := newPathTestClient(t, "")
+	ih, err := addPathTestTorrent(t, c, t.TempDir(), "movie.mkv", "", 1)
+	require.NoError(t, err)
+
+	exported, err := c.ExportTorrent(ih, false)
+	require.NoError(t, err)
+	m, err := metainfo.Load(exported.Torrent)
+	require.NoError(t, err)
+	info, err := meta.FromTorrent(*m)
+	require.NoError(t, err)
+
+	r, err := c.InspectTorrent(m, info)
+	require.NoError(t, err)
+	require.True(t, r.Loaded)
+	require.Equal(t, &FileTreeNode{Name: "movie.mkv", Length: 16 * 1024, Index: 0}, r.Tree)
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package client
+
+import (
+	"cmp"
+	"slices"
+
+	"neptune/internal/meta"
+)
+
+// FileMatch is a completed file of a loaded torrent that holds the data of a
+// file of the torrent being matched.
+type FileMatch struct {
+	Path     string `json:"path"`
+	InfoHash string `json:"info_hash"`
+	// Index is the file in the torrent being matched, File the one in the
+	// loaded torrent.
+	Index int `json:"index"`
+	File  int `json:"file"`
+	// Verified is set when sample pieces of the file passed the hash check,
+	// unset when only the name and size matched.
+	Verified bool `json:"verified"`
+}
+
+// TorrentMatch is how much of a torrent the loaded torrents already have.
+type TorrentMatch struct {
+	Files []FileMatch `json:"files"`
+	// Torrents are the loaded torrents holding matched files.
+	Torrents     []string `json:"torrents"`
+	MatchedBytes int64    `json:"matched_bytes"`
+	// Percent is the share of pieces whose data is all in verified files,
+	// which a cross-seed add can verify instead of downloading.
+	Percent          float64 `json:"percent"`
+	VerifiablePieces uint32  `json:"verifiable_pieces"`
+	// MatchedPieces are the pieces whose data is all in matched files, also
+	// counting the files matched by name only.
+	MatchedPieces uint32 `json:"matched_pieces"`
+}
+
+// MatchTorrent finds the completed files of loaded torrents with the size of
+// a file of info. Without verify, a file matches one with the same name. With
+// verify, sample pieces of the file are hash checked against the candidates
+// instead, which also finds renamed files. Only files that passed the check
+// count toward VerifiablePieces and Percent.
+func (c *Client) MatchTorrent(info *meta.Info, verify bool) TorrentMatch {
+	bySize := make(map[int64][]fileCandidate)
+	c.m.RLock()
+	for _, d := range c.downloads {
+		addCompletedFiles(bySize, d)
+	}
+	c.m.RUnlock()
+	for _, candidates := range bySize {
+		slices.SortFunc(candidates, func(a, b fileCandidate) int { return cmp.Compare(a.path, b.path) })
+	}
+
+	matches := matchFiles(info, bySize, verify)
+
+	r := TorrentMatch{Files: make([]FileMatch, 0, len(matches)), Torrents: []string{}}
+	for i, match := range matches {
+		r.Files = append(r.Files, FileMatch{
+			Path:     match.path,
+			InfoHash: match.infoHash.Hex(),
+			Index:    i,
+			File:     match.file,
+			Verified: match.verified,
+		})
+		r.MatchedBytes += info.Files[i].Length
+		if !slices.Contains(r.Torrents, match.infoHash.Hex()) {
+			r.Torrents = append(r.Torrents, match.infoHash.Hex())
+		}
+	}
+	slices.SortFunc(r.Files, func(a, b FileMatch) int { return cmp.Compare(a.Index, b.Index) })
+	slices.Sort(r.Torrents)
+
+	for piece := range info.NumPieces {
+		matched, verifiable := true, true
+		for chunk := range info.PieceFileChunks(piece) {
+			if chunk.Length == 0 {
+				continue
+			}
+			match, ok := matches[chunk.FileIndex]
+			if !ok {
+				matched, verifiable = false, false
+				break
+			}
+			verifiable = verifiable && match.verified
+		}
+		if matched {
+			r.MatchedPieces++
+		}
+		if verifiable {
+			r.VerifiablePieces++
+		}
+	}
+	if info.NumPieces != 0 {
+		r.Percent = float64(r.VerifiablePieces) / float64(info.NumPieces) * 100
+	}
+	return r
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package client
+
+import (
+	"crypto/sha1"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trim21/go-bencode"
+
+	"neptune/internal/download"
+	"neptune/internal/meta"
+	"neptune/internal/metainfo"
+)
+
+func singleFileInfo(t *testing.T, name string, data []byte, pieceLength int) meta.Info {
+	t.Helper()
+	var pieces []byte
+	for off := 0; off < len(data); off += pieceLength {
+		digest := sha1.Sum(data[off:min(off+pieceLength, len(data))])
+		pieces = append(pieces, digest[:]...)
+	}
+	infoBytes, err := bencode.Marshal(metainfo.Info{
+		Name:        name,
+		PieceLength: int64(pieceLength),
+		Pieces:      pieces,
+		Length:      int64(len(data)),
+	})
+	require.NoError(t, err)
+	info, err := meta.FromTorrent(metainfo.MetaInfo{InfoBytes: infoBytes})
+	require.NoError(t, err)
+	return info
+}
+
+func TestMatchTorrent(t *testing.T) {
+	c := newPathTestClient(t, "")
+	movie := randomBytes(5*crossSeedPieceLength + 100)
+	nfo := randomBytes(300)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "movie.nfo"), nfo, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "movie.mkv"), movie, 0o644))
+	raw, m, info := crossSeedTorrent(t, nfo, movie)
+	require.NoError(t, c.AddTorrent(raw, m, info, dir, AddTorrentOptions{}))
+	require.Eventually(t, func() bool {
+		return c.downloadMap[info.Hash].GetState() == download.Seeding
+	}, 10*time.Second, 10*time.Millisecond)
+
+	want := FileMatch{Path: filepath.Join(dir, "movie.mkv"), InfoHash: info.Hash.Hex(), Index: 0, File: 1}
+
+	// same name, another piece size
+	same := singleFileInfo(t, "movie.mkv", movie, 2*crossSeedPieceLength)
+	r := c.MatchTorrent(&same, false)
+	require.Equal(t, []FileMatch{want}, r.Files)
+	require.Equal(t, []string{info.Hash.Hex()}, r.Torrents)
+	require.Equal(t, int64(len(movie)), r.MatchedBytes)
+	require.Equal(t, same.NumPieces, r.MatchedPieces)
+	require.Zero(t, r.VerifiablePieces, "a name match is not verified")
+	require.Zero(t, r.Percent)
+
+	want.Verified = true
+	r = c.MatchTorrent(&same, true)
+	require.Equal(t, []FileMatch{want}, r.Files)
+	require.Equal(t, same.NumPieces, r.VerifiablePieces)
+	require.InDelta(t, 100, r.Percent, 0.001)
+
+	renamed := singleFileInfo(t, "Movie 2026.mkv", movie, crossSeedPieceLength)
+	r = c.MatchTorrent(&renamed, false)
+	require.Empty(t, r.Files)
+	require.Zero(t, r.Percent)
+
+	r = c.MatchTorrent(&renamed, true)
+	require.Equal(t, []FileMatch{want}, r.Files)
+	require.Equal(t, uint32(6), r.VerifiablePieces)
+	require.Equal(t, uint32(6), r.MatchedPieces)
+
+	other := singleFileInfo(t, "movie.mkv", randomBytes(len(movie)), crossSeedPieceLength)
+	r = c.MatchTorrent(&other, true)
+	require.Empty(t, r.Files, "same name and size but other data")
+}
@@ -0,0 +1,37 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package client
+
+import (
+	"slices"
+	"time"
+)
+
+const mountCheckInterval = 30 * time.Second
+
+// startMountWatchdog recovers the torrents that failed because the mount
+// holding their data was missing, once it is back.
+func (c *Client) startMountWatchdog() {
+	ticker := time.NewTicker(mountCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.session.Ctx.Done():
+			return
+		case <-ticker.C:
+			c.recoverMounts()
+		}
+	}
+}
+
+func (c *Client) recoverMounts() {
+	c.m.RLock()
+	downloads := slices.Clone(c.downloads)
+	c.m.RUnlock()
+
+	for _, d := range downloads {
+		d.RecoverMount()
+	}
+}
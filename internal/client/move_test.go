@@ -0,0 +1,68 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package client
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/session/store"
+)
+
+func TestRecoverMoves(t *testing.T) {
+	c := newTestClientWithStore(t)
+	root := t.TempDir()
+	source := filepath.Join(root, "source")
+	target := filepath.Join(root, "target")
+	for _, dir := range []string{source, target} {
+		require.NoError(t, os.MkdirAll(dir, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "committed"), []byte("data"), 0o644))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "copying"), []byte("data"), 0o644))
+	}
+
+	const committed, copying, removed = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", "cccccccccccccccccccccccccccccccccccccccc"
+	for _, r := range []store.Resume{
+		{InfoHash: committed, BasePath: source},
+		{InfoHash: copying, BasePath: source},
+	} {
+		require.NoError(t, c.session.Store.Upsert(&r))
+	}
+	for _, m := range []store.MoveJournal{
+		{InfoHash: committed, Source: source, Target: target, Files: []string{"committed"}, Committed: true},
+		{InfoHash: copying, Source: source, Target: target, Files: []string{"copying"}},
+		{InfoHash: removed, Source: source, Target: target, Files: []string{"removed"}},
+	} {
+		require.NoError(t, c.session.Store.SaveMove(&m))
+	}
+
+	all, err := c.session.Store.All()
+	require.NoError(t, err)
+	require.NoError(t, c.recoverMoves(all))
+
+	_, err = os.Stat(filepath.Join(source, "committed"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+	require.FileExists(t, filepath.Join(target, "committed"))
+	require.FileExists(t, filepath.Join(source, "copying"))
+	_, err = os.Stat(filepath.Join(target, "copying"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	basePaths := map[string]string{}
+	for _, r := range all {
+		basePaths[r.InfoHash] = r.BasePath
+	}
+	require.Equal(t, map[string]string{committed: target, copying: source}, basePaths)
+
+	all, err = c.session.Store.All()
+	require.NoError(t, err)
+	for _, r := range all {
+		require.Equal(t, basePaths[r.InfoHash], r.BasePath, "recovered base path is saved")
+	}
+
+	moves, err := c.session.Store.Moves()
+	require.NoError(t, err)
+	require.Empty(t, moves)
+}
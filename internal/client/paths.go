@@ -0,0 +1,206 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+
+	"neptune/internal/config"
+	"neptune/internal/download"
+	"neptune/internal/meta"
+	"neptune/internal/metainfo"
+	"neptune/internal/session"
+)
+
+var ErrPathConflict = errors.New("file is owned by another torrent")
+
+// PathOwner is a torrent file stored at an absolute path.
+type PathOwner struct {
+	Path     string `json:"path"`
+	InfoHash string `json:"info_hash"`
+	File     int    `json:"file"`
+}
+
+// PathOwners returns the torrents owning the file at path, or the files under
+// it when path is a directory.
+func (c *Client) PathOwners(path string) ([]PathOwner, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	owners := c.session.Paths.Lookup(path)
+	r := make([]PathOwner, len(owners))
+	for i, o := range owners {
+		r[i] = PathOwner{Path: o.Path, InfoHash: o.InfoHash.Hex(), File: o.File}
+	}
+	return r, nil
+}
+
+type pathConflict struct {
+	path  string
+	owner session.PathOwner
+	file  int
+}
+
+func (p pathConflict) error() error {
+	return fmt.Errorf("%w: %q is used by torrent %s", ErrPathConflict, p.path, p.owner.InfoHash)
+}
+
+// sameDataFunc reports whether file of the torrent being checked is known to
+// hold the same bytes as ownerFile of owner.
+type sameDataFunc func(owner *Download, ownerFile, file int) bool
+
+// pathConflicts returns the paths of torrent ih that another torrent already
+// owns. With the allow-identical policy, files known to hold the same data as
+// their owner's are no conflict. Caller must hold c.m.
+func (c *Client) pathConflicts(ih metainfo.Hash, paths map[string]int, same sameDataFunc) []pathConflict {
+	policy, _ := config.ParsePathConflictPolicy(c.session.Config.App.PathConflict)
+
+	var conflicts []pathConflict
+	for path, file := range paths {
+		for _, owner := range c.session.Paths.FileOwners(path) {
+			if owner.InfoHash == ih {
+				continue
+			}
+			if policy == config.PathConflictAllowIdentical {
+				if d, ok := c.downloadMap[owner.InfoHash]; ok && same(d, owner.File, file) {
+					continue
+				}
+			}
+			conflicts = append(conflicts, pathConflict{owner: owner, path: path, file: file})
+			break
+		}
+	}
+	slices.SortFunc(conflicts, func(a, b pathConflict) int { return strings.Compare(a.path, b.path) })
+	return conflicts
+}
+
+// resolveAddConflicts handles the files of a torrent being added that another
+// torrent already owns, renaming them with a numbered suffix under the rename
+// policy. sharedFiles were verified to hold the data of the existing file and
+// are never written, the allow-identical policy takes them as identical.
+// Caller must hold c.m.
+func (c *Client) resolveAddConflicts(info *meta.Info, sharedFiles []int, basePaths ...string) error {
+	conflicts := c.pathConflicts(info.Hash, download.OwnedPaths(info, basePaths...), func(owner *Download, ownerFile, file int) bool {
+		return slices.Contains(sharedFiles, file) || owner.SameFileData(ownerFile, info, file)
+	})
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	policy, _ := config.ParsePathConflictPolicy(c.session.Config.App.PathConflict)
+	if policy != config.PathConflictRename {
+		return conflicts[0].error()
+	}
+
+	renamed := make(map[int]bool, len(conflicts))
+	for _, conflict := range conflicts {
+		if renamed[conflict.file] {
+			continue
+		}
+		renamed[conflict.file] = true
+
+		f := &info.Files[conflict.file]
+		// a remapped file is existing data, it can not be renamed
+		if filepath.IsAbs(f.Path) {
+			return conflict.error()
+		}
+		path := c.freePath(info, f.Path, basePaths)
+		log.Info().Stringer("info_hash", info.Hash).Str("path", f.Path).Str("renamed", path).
+			Stringer("owner", conflict.owner.InfoHash).Msg("file is used by another torrent, renaming it")
+		f.SetPath(path)
+	}
+	return nil
+}
+
+// freePath returns path with the first numbered suffix, "name (1).ext", that
+// no torrent owns under any of basePaths and no other file of info uses.
+func (c *Client) freePath(info *meta.Info, path string, basePaths []string) string {
+	ext := filepath.Ext(path)
+	stem := strings.TrimSuffix(path, ext)
+
+	used := make(map[string]struct{}, len(info.Files))
+	for _, f := range info.Files {
+		used[f.Path] = struct{}{}
+	}
+
+	for n := 1; ; n++ {
+		candidate := fmt.Sprintf("%s (%d)%s", stem, n, ext)
+		if _, ok := used[candidate]; ok {
+			continue
+		}
+		free := true
+		for _, basePath := range basePaths {
+			if basePath == "" {
+				continue
+			}
+			abs, err := filepath.Abs(basePath)
+			if err != nil {
+				continue
+			}
+			if len(c.session.Paths.FileOwners(filepath.Join(abs, candidate))) != 0 {
+				free = false
+				break
+			}
+		}
+		if free {
+			return candidate
+		}
+	}
+}
+
+// checkAddableLocked fails when torrent ih is loaded or being added. Caller
+// must hold c.m.
+func (c *Client) checkAddableLocked(ih metainfo.Hash) error {
+	if _, ok := c.downloadMap[ih]; ok {
+		return fmt.Errorf("torrent %s exists", ih)
+	}
+	if _, ok := c.adding[ih]; ok {
+		return fmt.Errorf("torrent %s is being added", ih)
+	}
+	return nil
+}
+
+// reserveAddLocked claims ih and the files of a torrent being added, so a
+// concurrent add of the same torrent, or of another one with the same paths,
+// fails its checks while the torrent is set up without holding c.m. The claim
+// ends with release. Caller must hold c.m.
+func (c *Client) reserveAddLocked(ih metainfo.Hash, files map[string]int) (release func()) {
+	c.adding[ih] = struct{}{}
+	unreserve := c.session.Paths.Reserve(ih, files)
+	return func() {
+		c.m.Lock()
+		delete(c.adding, ih)
+		c.m.Unlock()
+		unreserve()
+	}
+}
+
+// reserveLocation returns torrent ih, or an error when another torrent owns a
+// file it would have under basePath. Data that already exists can not be
+// renamed, so the rename policy rejects too. The files under basePath are
+// reserved for ih until release is called, so a concurrent move or
+// set_location of another torrent to the same paths fails its check.
+func (c *Client) reserveLocation(ih metainfo.Hash, basePath string) (d *Download, release func(), err error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	d, ok := c.downloadMap[ih]
+	if !ok {
+		return nil, nil, download.ErrTorrentNotFound
+	}
+	files := d.FilesAt(basePath)
+	conflicts := c.pathConflicts(ih, files, func(owner *Download, ownerFile, file int) bool {
+		return owner.SameFileAs(ownerFile, d, file)
+	})
+	if len(conflicts) != 0 {
+		return nil, nil, conflicts[0].error()
+	}
+	return d, c.session.Paths.Reserve(ih, files), nil
+}
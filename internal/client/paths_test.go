@@ -0,0 +1,184 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package client
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trim21/go-bencode"
+
+	"neptune/internal/config"
+	"neptune/internal/meta"
+	"neptune/internal/metainfo"
+)
+
+// addPathTestTorrent adds a single file torrent named name to dir. Torrents
+// with the same fill byte hold the same data, source only changes the info
+// hash.
+func addPathTestTorrent(t *testing.T, c *Client, dir, name, source string, fill byte) (metainfo.Hash, error) {
+	t.Helper()
+	pieceLength := int64(16 * 1024)
+	digest := sha1.Sum(bytes.Repeat([]byte{fill}, int(pieceLength)))
+	infoBytes, err := bencode.Marshal(metainfo.Info{
+		Name:        name,
+		Source:      source,
+		Pieces:      digest[:],
+		PieceLength: pieceLength,
+		Length:      pieceLength,
+	})
+	require.NoError(t, err)
+
+	m := &metainfo.MetaInfo{InfoBytes: infoBytes}
+	info, err := meta.FromTorrent(*m)
+	require.NoError(t, err)
+	raw, err := bencode.Marshal(m)
+	require.NoError(t, err)
+
+	return info.Hash, c.AddTorrent(raw, m, info, dir, AddTorrentOptions{})
+}
+
+func newPathTestClient(t *testing.T, policy string) *Client {
+	t.Helper()
+	resetMetrics()
+	c := New(config.Config{App: config.Application{
+		P2PPort:                randomPort(t),
+		MaxHTTPParallel:        4,
+		GlobalConnectionLimit:  100,
+		TorrentConnectionLimit: 20,
+		PathConflict:           policy,
+		DownloadDir:            t.TempDir(),
+	}}, t.TempDir(), false)
+	t.Cleanup(c.Shutdown)
+	return c
+}
+
+func TestAddTorrentRejectsPathConflict(t *testing.T) {
+	c := newPathTestClient(t, "")
+	dir := t.TempDir()
+
+	a, err := addPathTestTorrent(t, c, dir, "movie.mkv", "", 1)
+	require.NoError(t, err)
+
+	rejected, err := addPathTestTorrent(t, c, dir, "movie.mkv", "repack", 2)
+	require.ErrorIs(t, err, ErrPathConflict)
+	h := rejected.Hex()
+	require.NoFileExists(t, filepath.Join(c.session.TorrentPath, h[:2], h[2:4], h+".torrent"))
+
+	owners, err := c.PathOwners(dir)
+	require.NoError(t, err)
+	require.Equal(t, []PathOwner{{Path: filepath.Join(dir, "movie.mkv"), InfoHash: a.Hex()}}, owners)
+
+	// another directory is fine, and removing the owner frees the path
+	_, err = addPathTestTorrent(t, c, t.TempDir(), "movie.mkv", "repack", 2)
+	require.NoError(t, err)
+	require.NoError(t, c.RemoveTorrent(a, false))
+	_, err = addPathTestTorrent(t, c, dir, "movie.mkv", "other", 3)
+	require.NoError(t, err)
+}
+
+func TestAddTorrentAllowsIdenticalData(t *testing.T) {
+	c := newPathTestClient(t, "allow-identical")
+	dir := t.TempDir()
+
+	a, err := addPathTestTorrent(t, c, dir, "movie.mkv", "", 1)
+	require.NoError(t, err)
+	b, err := addPathTestTorrent(t, c, dir, "movie.mkv", "cross-seed", 1)
+	require.NoError(t, err)
+	_, err = addPathTestTorrent(t, c, dir, "movie.mkv", "repack", 2)
+	require.ErrorIs(t, err, ErrPathConflict)
+
+	owners, err := c.PathOwners(filepath.Join(dir, "movie.mkv"))
+	require.NoError(t, err)
+	require.Len(t, owners, 2)
+	require.ElementsMatch(t, []string{a.Hex(), b.Hex()}, []string{owners[0].InfoHash, owners[1].InfoHash})
+}
+
+func TestAddTorrentRenamesConflictingFiles(t *testing.T) {
+	c := newPathTestClient(t, "rename")
+	dir := t.TempDir()
+
+	_, err := addPathTestTorrent(t, c, dir, "movie.mkv", "", 1)
+	require.NoError(t, err)
+	b, err := addPathTestTorrent(t, c, dir, "movie.mkv", "repack", 2)
+	require.NoError(t, err)
+	d, err := addPathTestTorrent(t, c, dir, "movie.mkv", "other", 3)
+	require.NoError(t, err)
+
+	owners, err := c.PathOwners(filepath.Join(dir, "movie (1).mkv"))
+	require.NoError(t, err)
+	require.Equal(t, []PathOwner{{Path: filepath.Join(dir, "movie (1).mkv"), InfoHash: b.Hex()}}, owners)
+	owners, err = c.PathOwners(filepath.Join(dir, "movie (2).mkv"))
+	require.NoError(t, err)
+	require.Equal(t, []PathOwner{{Path: filepath.Join(dir, "movie (2).mkv"), InfoHash: d.Hex()}}, owners)
+}
+
+func TestSetLocationRejectsPathConflict(t *testing.T) {
+	c := newPathTestClient(t, "rename")
+	dir := t.TempDir()
+	other := t.TempDir()
+
+	_, err := addPathTestTorrent(t, c, dir, "movie.mkv", "", 1)
+	require.NoError(t, err)
+	b, err := addPathTestTorrent(t, c, other, "movie.mkv", "repack", 2)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, c.SetLocation(b, dir, false), ErrPathConflict)
+	require.ErrorIs(t, c.ScheduleMove(b, dir), ErrPathConflict)
+
+	owners, err := c.PathOwners(other)
+	require.NoError(t, err)
+	require.Equal(t, []PathOwner{{Path: filepath.Join(other, "movie.mkv"), InfoHash: b.Hex()}}, owners)
+}
+
+func TestLocationReservedUntilDone(t *testing.T) {
+	c := newPathTestClient(t, "")
+	target := t.TempDir()
+
+	a, err := addPathTestTorrent(t, c, t.TempDir(), "movie.mkv", "", 1)
+	require.NoError(t, err)
+	b, err := addPathTestTorrent(t, c, t.TempDir(), "movie.mkv", "repack", 2)
+	require.NoError(t, err)
+
+	// a move of a to target is running
+	_, release, err := c.reserveLocation(a, target)
+	require.NoError(t, err)
+	require.ErrorIs(t, c.SetLocation(b, target, false), ErrPathConflict)
+	require.ErrorIs(t, c.ScheduleMove(b, target), ErrPathConflict)
+
+	release()
+	_, release, err = c.reserveLocation(b, target)
+	require.NoError(t, err)
+	require.ErrorIs(t, c.ScheduleMove(a, target), ErrPathConflict)
+	release()
+}
+
+func TestAddReservedUntilAdded(t *testing.T) {
+	c := newPathTestClient(t, "")
+	dir := t.TempDir()
+
+	// the torrent file of a is being written
+	a := metainfo.Hash{1}
+	c.m.Lock()
+	release := c.reserveAddLocked(a, map[string]int{filepath.Join(dir, "movie.mkv"): 0})
+	c.m.Unlock()
+	c.m.RLock()
+	require.ErrorContains(t, c.checkAddableLocked(a), "is being added")
+	c.m.RUnlock()
+
+	_, err := addPathTestTorrent(t, c, dir, "movie.mkv", "repack", 2)
+	require.ErrorIs(t, err, ErrPathConflict)
+
+	release()
+	c.m.RLock()
+	require.NoError(t, c.checkAddableLocked(a))
+	c.m.RUnlock()
+	_, err = addPathTestTorrent(t, c, dir, "movie.mkv", "repack", 2)
+	require.NoError(t, err)
+}
@@ -62,7 +62,8 @@ func (c *Client) rebalanceQueue() {
 
 	var candidates []queueCandidate
 	for _, d := range c.downloads {
-		if !d.IsDownloading() {
+		// torrents paused for low disk space wait for the disk space watchdog
+		if !d.IsDownloading() || d.DiskSpacePaused() {
 			continue
 		}
 		candidates = append(candidates, queueCandidate{d: d, weight: d.QueueWeight()})
@@ -0,0 +1,44 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package client
+
+import (
+	"path/filepath"
+
+	"neptune/internal/download"
+	"neptune/internal/metainfo"
+)
+
+// RenameFile changes the path of a file in a torrent, renaming it on disk
+// when it exists. A relative path must stay inside the base path; an absolute
+// path points the file at existing data elsewhere, such as a media library.
+func (c *Client) RenameFile(ih metainfo.Hash, index int, path string) error {
+	c.m.RLock()
+	d, ok := c.downloadMap[ih]
+	c.m.RUnlock()
+	if !ok {
+		return download.ErrTorrentNotFound
+	}
+	if !filepath.IsAbs(path) {
+		if err := validateTorrentPath(d.BasePath(), path); err != nil {
+			return err
+		}
+	}
+	return d.RenameFile(index, path)
+}
+
+// RenameFolder moves every file under folder to newFolder, both relative to
+// the base path of the torrent.
+func (c *Client) RenameFolder(ih metainfo.Hash, folder, newFolder string) error {
+	c.m.RLock()
+	d, ok := c.downloadMap[ih]
+	c.m.RUnlock()
+	if !ok {
+		return download.ErrTorrentNotFound
+	}
+	if err := validateTorrentPath(d.BasePath(), newFolder); err != nil {
+		return err
+	}
+	return d.RenameFolder(folder, newFolder)
+}
@@ -0,0 +1,21 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package client
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTorrentPath(t *testing.T) {
+	base := t.TempDir()
+	for _, p := range []string{"a", filepath.Join("a", "b"), filepath.Join("a", "..", "b")} {
+		require.NoError(t, validateTorrentPath(base, p), p)
+	}
+	for _, p := range []string{"", ".", "..", filepath.Join("..", "a"), filepath.Join("a", "..", "..", "b"), base} {
+		require.Error(t, validateTorrentPath(base, p), p)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package client
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"neptune/internal/download"
+	"neptune/internal/pkg/disk_io"
+	"neptune/internal/pkg/ratelimit"
+)
+
+const scrubPollInterval = time.Minute
+
+// scrubReadCost is the tokens one file read takes from the reads limiter.
+// ratelimit counts bytes and allows a burst of at least 128KiB, scaling reads
+// up keeps the burst to a few reads.
+const scrubReadCost = 1 << 20
+
+// errScrubBudgetSpent stops the running scrubs once the daily byte cap is
+// used up, they continue the next day.
+var errScrubBudgetSpent = errors.New("daily scrub budget is spent")
+
+// scrubber runs at most one background scrub per device, under the scrub
+// budget of the device and the daily byte cap.
+type scrubber struct {
+	dayStart    time.Time
+	devices     map[disk_io.DeviceID]*scrubDevice
+	maxPerDay   int64
+	bytesPerDay int64
+	mu          sync.Mutex
+}
+
+func newScrubber(maxPerDay int64) *scrubber {
+	return &scrubber{
+		devices:   make(map[disk_io.DeviceID]*scrubDevice),
+		maxPerDay: maxPerDay,
+	}
+}
+
+// scrubDevice paces the scrub of one device. It implements
+// download.ScrubLimiter.
+type scrubDevice struct {
+	s     *scrubber
+	bytes *ratelimit.Limiter
+	reads *ratelimit.Limiter
+	busy  bool
+}
+
+func (dev *scrubDevice) Wait(ctx context.Context, n int64, reads int) error {
+	if !dev.s.spend(time.Now(), n) {
+		return errScrubBudgetSpent
+	}
+	if err := dev.reads.Wait(ctx, reads*scrubReadCost); err != nil {
+		return err
+	}
+	return dev.bytes.Wait(ctx, int(n))
+}
+
+// acquire reserves the device for a scrub, false if one is running there.
+func (s *scrubber) acquire(id disk_io.DeviceID, class disk_io.DeviceClass) (*scrubDevice, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	dev, ok := s.devices[id]
+	if !ok {
+		budget := disk_io.ScrubBudgetFor(class)
+		dev = &scrubDevice{
+			s:     s,
+			bytes: ratelimit.New(budget.BytesPerSecond),
+			reads: ratelimit.New(budget.ReadsPerSecond * scrubReadCost),
+		}
+		s.devices[id] = dev
+	}
+	if dev.busy {
+		return nil, false
+	}
+	dev.busy = true
+	return dev, true
+}
+
+func (s *scrubber) release(dev *scrubDevice) {
+	s.mu.Lock()
+	dev.busy = false
+	s.mu.Unlock()
+}
+
+// spend takes n bytes from the daily cap, false when they do not fit.
+func (s *scrubber) spend(now time.Time, n int64) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resetDayLocked(now)
+	if s.maxPerDay > 0 && s.bytesPerDay+n > s.maxPerDay {
+		return false
+	}
+	s.bytesPerDay += n
+	return true
+}
+
+// spent reports whether the daily cap is used up.
+func (s *scrubber) spent(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resetDayLocked(now)
+	return s.maxPerDay > 0 && s.bytesPerDay >= s.maxPerDay
+}
+
+func (s *scrubber) resetDayLocked(now time.Time) {
+	if now.Sub(s.dayStart) >= 24*time.Hour {
+		s.dayStart = now
+		s.bytesPerDay = 0
+	}
+}
+
+// startScrubber periodically re-reads the data of seeding torrents to find
+// pieces that went bad on disk. It does nothing when scrubbing is disabled.
+func (c *Client) startScrubber() {
+	cfg := c.session.Config.App.Scrub
+	if cfg.IntervalDays == 0 {
+		return
+	}
+
+	s := newScrubber(cfg.MaxBytesPerDay)
+	interval := time.Duration(cfg.IntervalDays) * 24 * time.Hour
+
+	ticker := time.NewTicker(scrubPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.session.Ctx.Done():
+			return
+		case <-ticker.C:
+			c.scheduleScrubs(s, time.Now(), interval, cfg.Tags)
+		}
+	}
+}
+
+// scheduleScrubs starts a scrub of the torrent verified longest ago on each
+// device that is not scrubbing yet.
+func (c *Client) scheduleScrubs(s *scrubber, now time.Time, interval time.Duration, tags []string) {
+	if s.spent(now) {
+		return
+	}
+
+	c.m.RLock()
+	downloads := slices.Clone(c.downloads)
+	c.m.RUnlock()
+
+	downloads = slices.DeleteFunc(downloads, func(d *download.Download) bool {
+		if len(tags) != 0 && !d.HasAnyTag(tags) {
+			return true
+		}
+		return !d.ScrubDue(now, interval)
+	})
+	slices.SortFunc(downloads, func(a, b *download.Download) int {
+		return a.LastVerified().Compare(b.LastVerified())
+	})
+
+	for _, d := range downloads {
+		id, class := c.session.IOContext.DeviceForPath(d.BasePath())
+		dev, ok := s.acquire(id, class)
+		if !ok {
+			continue
+		}
+		go func() {
+			defer s.release(dev)
+			err := d.Scrub(dev)
+			if err != nil && !errors.Is(err, errScrubBudgetSpent) && !errors.Is(err, context.Canceled) {
+				log.Err(err).Stringer("info_hash", d.InfoHash()).Msg("scrub failed")
+			}
+		}()
+	}
+}
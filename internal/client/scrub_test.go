@@ -0,0 +1,56 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/pkg/disk_io"
+)
+
+func TestScrubberDailyBudget(t *testing.T) {
+	s := newScrubber(100)
+	now := time.Now()
+
+	require.True(t, s.spend(now, 60))
+	require.False(t, s.spend(now, 60))
+	require.False(t, s.spent(now))
+	require.True(t, s.spend(now, 40))
+	require.True(t, s.spent(now))
+
+	tomorrow := now.Add(24 * time.Hour)
+	require.False(t, s.spent(tomorrow))
+	require.True(t, s.spend(tomorrow, 100))
+}
+
+func TestScrubberOneScrubPerDevice(t *testing.T) {
+	s := newScrubber(0)
+	hdd := disk_io.DeviceID{Major: 8, Minor: 1}
+	ssd := disk_io.DeviceID{Major: 259, Minor: 1}
+
+	dev, ok := s.acquire(hdd, disk_io.DeviceHDD)
+	require.True(t, ok)
+	_, ok = s.acquire(hdd, disk_io.DeviceHDD)
+	require.False(t, ok)
+	_, ok = s.acquire(ssd, disk_io.DeviceSSD)
+	require.True(t, ok)
+
+	s.release(dev)
+	again, ok := s.acquire(hdd, disk_io.DeviceHDD)
+	require.True(t, ok)
+	require.Same(t, dev, again, "the device keeps its rate limiters")
+}
+
+func TestScrubDeviceStopsWhenBudgetSpent(t *testing.T) {
+	s := newScrubber(1 << 20)
+	dev, ok := s.acquire(disk_io.DeviceID{}, disk_io.DeviceSSD)
+	require.True(t, ok)
+
+	require.NoError(t, dev.Wait(context.Background(), 1<<20, 1))
+	require.ErrorIs(t, dev.Wait(context.Background(), 1, 1), errScrubBudgetSpent)
+}
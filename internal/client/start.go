@@ -15,6 +15,7 @@ import (
 	"github.com/trim21/errgo"
 
 	"neptune/internal/mse"
+	"neptune/internal/pkg/empty"
 	"neptune/internal/pkg/global"
 	"neptune/internal/pkg/global/tasks"
 	"neptune/internal/proto"
@@ -33,6 +34,10 @@ func (c *Client) Start() error {
 	// pool exhaustion → evict from the busiest torrent).
 	go c.startGlobalTurnover()
 
+	go c.startDiskSpaceWatchdog()
+	go c.startMountWatchdog()
+	go c.startScrubber()
+
 	go func() {
 		for {
 			time.Sleep(time.Minute * 5)
@@ -85,6 +90,9 @@ func (c *Client) Start() error {
 	if err != nil {
 		return err
 	}
+	if err := c.recoverMoves(all); err != nil {
+		return err
+	}
 
 	totalDownloads := len(all)
 	for i := range all {
@@ -93,6 +101,13 @@ func (c *Client) Start() error {
 		}
 	}
 
+	if err := c.restoreCheckQueue(); err != nil {
+		return err
+	}
+	c.checkQueueStop = make(chan empty.Empty)
+	c.checkQueueDone = make(chan empty.Empty)
+	go c.persistCheckQueue()
+
 	// Trigger initial queue rebalance after all resumes are loaded.
 	c.triggerQueueRebalance()
 	return nil
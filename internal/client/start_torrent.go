@@ -28,6 +28,10 @@ func (c *Client) StartTorrent(h metainfo.Hash) error {
 		return fmt.Errorf("torrent %s is not in a startable state, current state: %s, err: %q", h, d.GetState().String(), d.ErrorMsg())
 	}
 
+	if err := c.checkStartSpace(d); err != nil {
+		return err
+	}
+
 	if err := d.Start(); err != nil {
 		return err
 	}
@@ -15,6 +15,8 @@ import (
 func (c *Client) Shutdown() {
 	log.Info().Msg("core shutting down...")
 
+	c.stopCheckQueue()
+
 	c.m.RLock()
 	defer c.m.RUnlock()
 
@@ -34,6 +36,7 @@ func (c *Client) Shutdown() {
 	wg.Wait()
 
 	c.session.Cancel()
+	c.session.HashCheck.Close()
 	c.session.IOContext.Close()
 	c.session.Store.Close()
 }
@@ -4,7 +4,9 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -34,6 +36,63 @@ func ParseCryptoMode(s string) (CryptoMode, error) {
 	}
 }
 
+// MissingDataPolicy is what a torrent does when data it already verified
+// goes missing or gets truncated on disk.
+type MissingDataPolicy uint8
+
+const (
+	MissingDataRedownload MissingDataPolicy = iota // download the lost pieces again (default)
+	MissingDataError                               // stop with an error until the data is restored
+)
+
+// ParseMissingDataPolicy converts a config string to MissingDataPolicy.
+func ParseMissingDataPolicy(s string) (MissingDataPolicy, error) {
+	switch s {
+	case "", "redownload":
+		return MissingDataRedownload, nil
+	case "error":
+		return MissingDataError, nil
+	default:
+		return 0, fmt.Errorf("invalid missing data policy %q: must be 'redownload' or 'error'", s)
+	}
+}
+
+// PathConflictPolicy is what happens when a torrent would use a file that
+// another torrent already owns.
+type PathConflictPolicy uint8
+
+const (
+	PathConflictReject         PathConflictPolicy = iota // refuse the torrent or the change (default)
+	PathConflictRename                                   // add the torrent with a numbered suffix on the file
+	PathConflictAllowIdentical                           // share the file if both torrents hash it the same
+)
+
+// ParsePathConflictPolicy converts a config string to PathConflictPolicy.
+func ParsePathConflictPolicy(s string) (PathConflictPolicy, error) {
+	switch s {
+	case "", "reject":
+		return PathConflictReject, nil
+	case "rename":
+		return PathConflictRename, nil
+	case "allow-identical":
+		return PathConflictAllowIdentical, nil
+	default:
+		return 0, fmt.Errorf("invalid path conflict policy %q: must be 'reject', 'rename', or 'allow-identical'", s)
+	}
+}
+
+// ValidatePartSuffix checks that s can be appended to file names: it must be
+// non-empty and must not contain a path separator.
+func ValidatePartSuffix(s string) error {
+	if s == "" {
+		return errors.New("part suffix must not be empty")
+	}
+	if strings.ContainsAny(s, `/\`) {
+		return fmt.Errorf("part suffix %q must not contain a path separator", s)
+	}
+	return nil
+}
+
 // HookConfig holds shell commands to run on download events.
 // Commands run via /bin/sh -c with environment variables:
 //
@@ -49,25 +108,68 @@ type HookConfig struct {
 	Timeout             time.Duration `toml:"timeout"`
 }
 
+// ScrubConfig controls background scrubbing, which re-reads the data of
+// seeding torrents to find pieces that went bad on disk.
+type ScrubConfig struct {
+	// Tags limits scrubbing to torrents with one of these tags, empty scrubs
+	// all of them.
+	Tags []string `toml:"tags"`
+	// MaxBytesPerDay caps how much data all scrubs read per day, 0 means no
+	// cap besides the per-device rate.
+	MaxBytesPerDay int64 `toml:"max-bytes-per-day"`
+	// IntervalDays is how often each torrent is scrubbed, 0 disables
+	// scrubbing.
+	IntervalDays uint16 `toml:"interval-days"`
+}
+
+// FetchConfig controls how torrent.add_url downloads torrent files.
+type FetchConfig struct {
+	// Domains holds the headers and cookies sent to a domain and its
+	// subdomains. The longest matching domain is used.
+	Domains map[string]FetchDomain `toml:"domains"`
+	// Timeout covers the whole download including redirects, 0 means 30s.
+	Timeout time.Duration `toml:"timeout"`
+	// MaxRedirects is how many redirects are followed, 0 means 10.
+	MaxRedirects int `toml:"max-redirects"`
+}
+
+// FetchDomain is what torrent.add_url sends to one domain, for example a
+// private tracker passkey header or a login cookie.
+type FetchDomain struct {
+	Headers map[string]string `toml:"headers"`
+	Cookies map[string]string `toml:"cookies"`
+}
+
 type Application struct {
-	DownloadDir                string     `toml:"download-dir"`
-	PiecePickStrategy          string     `toml:"piece-pick-strategy"`
-	Crypto                     string     `toml:"crypto"`
-	Hook                       HookConfig `toml:"hook"`
-	SlowDownloadSpeedThreshold int64      `toml:"slow-download-speed-threshold"`
-	GlobalUploadSpeedLimit     int64      `toml:"global-upload-speed-limit"`
-	MaxRequestBodySize         int64      `toml:"max-rpc-request-body-size"`
-	MaxHTTPParallel            int        `toml:"max-http-parallel"`
-	GlobalDownloadSpeedLimit   int64      `toml:"global-download-speed-limit"`
-	P2PPort                    uint16     `toml:"p2p-port"`
-	GlobalConnectionLimit      uint16     `toml:"global-connections-limit"`
-	TorrentConnectionLimit     uint16     `toml:"torrent-connection-limit"`
-	ConnectionSpeed            uint16     `toml:"connection-speed"`
-	DownloadSlots              uint16     `toml:"download-slots"`
-	GlobalUploadSlots          uint16     `toml:"global-upload-slots"`
-	NumWant                    uint16     `toml:"num-want"`
-	Fallocate                  bool       `toml:"fallocate"`
-	RecheckOnComplete          bool       `toml:"recheck-on-complete"`
+	DownloadDir                string      `toml:"download-dir"`
+	IncompleteDir              string      `toml:"incomplete-dir"`
+	PiecePickStrategy          string      `toml:"piece-pick-strategy"`
+	Crypto                     string      `toml:"crypto"`
+	PartSuffix                 string      `toml:"part-suffix"`
+	MissingData                string      `toml:"missing-data"`
+	PathConflict               string      `toml:"path-conflict"`
+	Hook                       HookConfig  `toml:"hook"`
+	Fetch                      FetchConfig `toml:"fetch"`
+	Scrub                      ScrubConfig `toml:"scrub"`
+	SlowDownloadSpeedThreshold int64       `toml:"slow-download-speed-threshold"`
+	GlobalUploadSpeedLimit     int64       `toml:"global-upload-speed-limit"`
+	MaxRequestBodySize         int64       `toml:"max-rpc-request-body-size"`
+	MaxHTTPParallel            int         `toml:"max-http-parallel"`
+	HashCheckWorkers           int         `toml:"hash-check-workers"`
+	ChecksPerDevice            int         `toml:"checks-per-device"`
+	RecheckSpeedLimit          int64       `toml:"recheck-speed-limit"`
+	MinFreeSpace               int64       `toml:"min-free-space"`
+	GlobalDownloadSpeedLimit   int64       `toml:"global-download-speed-limit"`
+	P2PPort                    uint16      `toml:"p2p-port"`
+	GlobalConnectionLimit      uint16      `toml:"global-connections-limit"`
+	TorrentConnectionLimit     uint16      `toml:"torrent-connection-limit"`
+	ConnectionSpeed            uint16      `toml:"connection-speed"`
+	DownloadSlots              uint16      `toml:"download-slots"`
+	GlobalUploadSlots          uint16      `toml:"global-upload-slots"`
+	NumWant                    uint16      `toml:"num-want"`
+	Fallocate                  bool        `toml:"fallocate"`
+	PartFiles                  bool        `toml:"part-files"`
+	RecheckOnComplete          bool        `toml:"recheck-on-complete"`
 }
 
 type Config struct {
@@ -27,6 +27,8 @@ func DefaultConfig() Config {
 			TorrentConnectionLimit: 50,
 			ConnectionSpeed:        30,
 			MaxRequestBodySize:     50 << 20,
+			PartSuffix:             ".part",
+			MinFreeSpace:           1 << 30,
 		},
 	}
 }
@@ -165,6 +167,10 @@ var configFields = map[string]configField{
 		setter: func(a *Application, v lua.LValue) error { a.DownloadDir = lua.LVAsString(v); return nil },
 		getter: func(a *Application) lua.LValue { return lua.LString(a.DownloadDir) },
 	},
+	"application.incomplete-dir": {
+		setter: func(a *Application, v lua.LValue) error { a.IncompleteDir = lua.LVAsString(v); return nil },
+		getter: func(a *Application) lua.LValue { return lua.LString(a.IncompleteDir) },
+	},
 	"application.max-http-parallel": {
 		setter: func(a *Application, v lua.LValue) error {
 			n, err := toGoInt(v)
@@ -286,10 +292,69 @@ var configFields = map[string]configField{
 		},
 		getter: func(a *Application) lua.LValue { return lua.LNumber(a.GlobalUploadSpeedLimit) },
 	},
+	"application.hash-check-workers": {
+		setter: func(a *Application, v lua.LValue) error {
+			n, err := toGoInt(v)
+			if err != nil {
+				return err
+			}
+			a.HashCheckWorkers = n
+			return nil
+		},
+		getter: func(a *Application) lua.LValue { return lua.LNumber(a.HashCheckWorkers) },
+	},
+	"application.checks-per-device": {
+		setter: func(a *Application, v lua.LValue) error {
+			n, err := toGoInt(v)
+			if err != nil {
+				return err
+			}
+			a.ChecksPerDevice = n
+			return nil
+		},
+		getter: func(a *Application) lua.LValue { return lua.LNumber(a.ChecksPerDevice) },
+	},
+	"application.recheck-speed-limit": {
+		setter: func(a *Application, v lua.LValue) error {
+			n, err := toGoInt64(v)
+			if err != nil {
+				return err
+			}
+			a.RecheckSpeedLimit = n
+			return nil
+		},
+		getter: func(a *Application) lua.LValue { return lua.LNumber(a.RecheckSpeedLimit) },
+	},
+	"application.min-free-space": {
+		setter: func(a *Application, v lua.LValue) error {
+			n, err := toGoInt64(v)
+			if err != nil {
+				return err
+			}
+			a.MinFreeSpace = n
+			return nil
+		},
+		getter: func(a *Application) lua.LValue { return lua.LNumber(a.MinFreeSpace) },
+	},
 	"application.fallocate": {
 		setter: func(a *Application, v lua.LValue) error { a.Fallocate = lua.LVAsBool(v); return nil },
 		getter: func(a *Application) lua.LValue { return lua.LBool(a.Fallocate) },
 	},
+	"application.part-files": {
+		setter: func(a *Application, v lua.LValue) error { a.PartFiles = lua.LVAsBool(v); return nil },
+		getter: func(a *Application) lua.LValue { return lua.LBool(a.PartFiles) },
+	},
+	"application.part-suffix": {
+		setter: func(a *Application, v lua.LValue) error {
+			s := lua.LVAsString(v)
+			if err := ValidatePartSuffix(s); err != nil {
+				return err
+			}
+			a.PartSuffix = s
+			return nil
+		},
+		getter: func(a *Application) lua.LValue { return lua.LString(a.PartSuffix) },
+	},
 	"application.max-rpc-request-body-size": {
 		setter: func(a *Application, v lua.LValue) error {
 			n, err := toGoInt64(v)
@@ -323,6 +388,130 @@ var configFields = map[string]configField{
 		},
 		getter: func(a *Application) lua.LValue { return lua.LString(a.Crypto) },
 	},
+	"application.missing-data": {
+		setter: func(a *Application, v lua.LValue) error {
+			s := lua.LVAsString(v)
+			if _, err := ParseMissingDataPolicy(s); err != nil {
+				return err
+			}
+			a.MissingData = s
+			return nil
+		},
+		getter: func(a *Application) lua.LValue { return lua.LString(a.MissingData) },
+	},
+	"application.path-conflict": {
+		setter: func(a *Application, v lua.LValue) error {
+			s := lua.LVAsString(v)
+			if _, err := ParsePathConflictPolicy(s); err != nil {
+				return err
+			}
+			a.PathConflict = s
+			return nil
+		},
+		getter: func(a *Application) lua.LValue { return lua.LString(a.PathConflict) },
+	},
+	"application.scrub.interval-days": {
+		setter: func(a *Application, v lua.LValue) error {
+			n, err := toGoUint16(v)
+			if err != nil {
+				return err
+			}
+			a.Scrub.IntervalDays = n
+			return nil
+		},
+		getter: func(a *Application) lua.LValue { return lua.LNumber(a.Scrub.IntervalDays) },
+	},
+	"application.scrub.max-bytes-per-day": {
+		setter: func(a *Application, v lua.LValue) error {
+			n, err := toGoInt64(v)
+			if err != nil {
+				return err
+			}
+			a.Scrub.MaxBytesPerDay = n
+			return nil
+		},
+		getter: func(a *Application) lua.LValue { return lua.LNumber(a.Scrub.MaxBytesPerDay) },
+	},
+	"application.scrub.tags": {
+		setter: func(a *Application, v lua.LValue) error {
+			tags, err := toGoStrings(v)
+			if err != nil {
+				return err
+			}
+			a.Scrub.Tags = tags
+			return nil
+		},
+		getter: func(a *Application) lua.LValue {
+			t := &lua.LTable{}
+			for _, tag := range a.Scrub.Tags {
+				t.Append(lua.LString(tag))
+			}
+			return t
+		},
+	},
+	"application.fetch.timeout": {
+		setter: func(a *Application, v lua.LValue) error {
+			d, err := time.ParseDuration(lua.LVAsString(v))
+			if err != nil {
+				return fmt.Errorf("invalid duration: %w", err)
+			}
+			a.Fetch.Timeout = d
+			return nil
+		},
+		getter: func(a *Application) lua.LValue { return lua.LString(a.Fetch.Timeout.String()) },
+	},
+	"application.fetch.max-redirects": {
+		setter: func(a *Application, v lua.LValue) error {
+			n, err := toGoInt(v)
+			if err != nil {
+				return err
+			}
+			a.Fetch.MaxRedirects = n
+			return nil
+		},
+		getter: func(a *Application) lua.LValue { return lua.LNumber(a.Fetch.MaxRedirects) },
+	},
+	"application.fetch.domains": {
+		setter: func(a *Application, v lua.LValue) error {
+			t, ok := v.(*lua.LTable)
+			if !ok {
+				return fmt.Errorf("expected table, got %s", v.Type())
+			}
+			domains := make(map[string]FetchDomain)
+			var err error
+			t.ForEach(func(k, v lua.LValue) {
+				if err != nil {
+					return
+				}
+				domain, ok := k.(lua.LString)
+				if !ok {
+					err = fmt.Errorf("expected domain string key, got %s", k.Type())
+					return
+				}
+				d, derr := toFetchDomain(v)
+				if derr != nil {
+					err = fmt.Errorf("domain %q: %w", string(domain), derr)
+					return
+				}
+				domains[string(domain)] = d
+			})
+			if err != nil {
+				return err
+			}
+			a.Fetch.Domains = domains
+			return nil
+		},
+		getter: func(a *Application) lua.LValue {
+			t := &lua.LTable{}
+			for domain, d := range a.Fetch.Domains {
+				dt := &lua.LTable{}
+				dt.RawSetString("headers", fromGoStringMap(d.Headers))
+				dt.RawSetString("cookies", fromGoStringMap(d.Cookies))
+				t.RawSetString(domain, dt)
+			}
+			return t
+		},
+	},
 	"application.hook.on-download-started": {
 		setter: func(a *Application, v lua.LValue) error { a.Hook.OnDownloadStarted = lua.LVAsString(v); return nil },
 		getter: func(a *Application) lua.LValue { return lua.LString(a.Hook.OnDownloadStarted) },
@@ -449,6 +638,70 @@ func toGoUint16(v lua.LValue) (uint16, error) {
 	return uint16(n), nil
 }
 
+func toGoStrings(v lua.LValue) ([]string, error) {
+	t, ok := v.(*lua.LTable)
+	if !ok {
+		return nil, fmt.Errorf("expected table, got %s", v.Type())
+	}
+	out := make([]string, 0, t.Len())
+	for i := 1; i <= t.Len(); i++ {
+		s, ok := t.RawGetInt(i).(lua.LString)
+		if !ok {
+			return nil, fmt.Errorf("expected string at index %d, got %s", i, t.RawGetInt(i).Type())
+		}
+		out = append(out, string(s))
+	}
+	return out, nil
+}
+
+func toGoStringMap(v lua.LValue) (map[string]string, error) {
+	t, ok := v.(*lua.LTable)
+	if !ok {
+		return nil, fmt.Errorf("expected table, got %s", v.Type())
+	}
+	out := make(map[string]string)
+	var err error
+	t.ForEach(func(k, v lua.LValue) {
+		ks, kok := k.(lua.LString)
+		vs, vok := v.(lua.LString)
+		if (!kok || !vok) && err == nil {
+			err = fmt.Errorf("expected string keys and values, got %s = %s", k.Type(), v.Type())
+			return
+		}
+		out[string(ks)] = string(vs)
+	})
+	return out, err
+}
+
+func fromGoStringMap(m map[string]string) *lua.LTable {
+	t := &lua.LTable{}
+	for k, v := range m {
+		t.RawSetString(k, lua.LString(v))
+	}
+	return t
+}
+
+// toFetchDomain converts a {headers = {...}, cookies = {...}} table.
+func toFetchDomain(v lua.LValue) (FetchDomain, error) {
+	t, ok := v.(*lua.LTable)
+	if !ok {
+		return FetchDomain{}, fmt.Errorf("expected table, got %s", v.Type())
+	}
+	var d FetchDomain
+	var err error
+	if h := t.RawGetString("headers"); h != lua.LNil {
+		if d.Headers, err = toGoStringMap(h); err != nil {
+			return FetchDomain{}, fmt.Errorf("headers: %w", err)
+		}
+	}
+	if c := t.RawGetString("cookies"); c != lua.LNil {
+		if d.Cookies, err = toGoStringMap(c); err != nil {
+			return FetchDomain{}, fmt.Errorf("cookies: %w", err)
+		}
+	}
+	return d, nil
+}
+
 func toGoInt64(v lua.LValue) (int64, error) {
 	switch v.Type() {
 	case lua.LTNumber:
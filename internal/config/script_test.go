@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -161,3 +162,121 @@ func TestLoadFromLua_SyntaxError(t *testing.T) {
 	require.Error(t, err)
 	assert.Contains(t, err.Error(), "execute config script")
 }
+
+func TestLoadFromLua_PartSuffix(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "config.lua")
+	require.NoError(t, os.WriteFile(script, []byte(`
+		neptune.set("application.part-files", true)
+		neptune.set("application.part-suffix", ".!nt")
+	`), 0644))
+
+	cfg, err := LoadFromLua(script)
+	require.NoError(t, err)
+	assert.True(t, cfg.App.PartFiles)
+	assert.Equal(t, ".!nt", cfg.App.PartSuffix)
+
+	require.NoError(t, os.WriteFile(script, []byte(`
+		neptune.set("application.part-suffix", "/part")
+	`), 0644))
+	_, err = LoadFromLua(script)
+	require.Error(t, err)
+}
+
+func TestLoadFromLua_MissingData(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "config.lua")
+	require.NoError(t, os.WriteFile(script, []byte(`
+		neptune.set("application.missing-data", "error")
+	`), 0644))
+
+	cfg, err := LoadFromLua(script)
+	require.NoError(t, err)
+	assert.Equal(t, "error", cfg.App.MissingData)
+
+	require.NoError(t, os.WriteFile(script, []byte(`
+		neptune.set("application.missing-data", "ignore")
+	`), 0644))
+	_, err = LoadFromLua(script)
+	require.Error(t, err)
+}
+
+func TestLoadFromLua_PathConflict(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "config.lua")
+	require.NoError(t, os.WriteFile(script, []byte(`
+		neptune.set("application.path-conflict", "allow-identical")
+	`), 0644))
+
+	cfg, err := LoadFromLua(script)
+	require.NoError(t, err)
+	assert.Equal(t, "allow-identical", cfg.App.PathConflict)
+
+	require.NoError(t, os.WriteFile(script, []byte(`
+		neptune.set("application.path-conflict", "overwrite")
+	`), 0644))
+	_, err = LoadFromLua(script)
+	require.Error(t, err)
+}
+
+func TestLoadFromLua_Scrub(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "config.lua")
+	require.NoError(t, os.WriteFile(script, []byte(`
+		neptune.set("application.scrub.interval-days", 30)
+		neptune.set("application.scrub.max-bytes-per-day", 1073741824)
+		neptune.set("application.scrub.tags", {"archive", "keep"})
+		local tags = neptune.get("application.scrub.tags")
+		neptune.set("application.scrub.tags", {tags[2], tags[1]})
+	`), 0644))
+
+	cfg, err := LoadFromLua(script)
+	require.NoError(t, err)
+	assert.Equal(t, uint16(30), cfg.App.Scrub.IntervalDays)
+	assert.Equal(t, int64(1<<30), cfg.App.Scrub.MaxBytesPerDay)
+	assert.Equal(t, []string{"keep", "archive"}, cfg.App.Scrub.Tags)
+
+	require.NoError(t, os.WriteFile(script, []byte(`
+		neptune.set("application.scrub.tags", "archive")
+	`), 0644))
+	_, err = LoadFromLua(script)
+	require.Error(t, err)
+}
+
+func TestLoadFromLua_Fetch(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "config.lua")
+	require.NoError(t, os.WriteFile(script, []byte(`
+		neptune.set("application.fetch.timeout", "1m")
+		neptune.set("application.fetch.max-redirects", 3)
+		neptune.set("application.fetch.domains", {
+			["tracker.example"] = {
+				headers = {["X-Passkey"] = "secret"},
+				cookies = {uid = "1", pass = "abc"},
+			},
+		})
+		local domains = neptune.get("application.fetch.domains")
+		domains["other.example"] = {cookies = {session = "x"}}
+		neptune.set("application.fetch.domains", domains)
+	`), 0644))
+
+	cfg, err := LoadFromLua(script)
+	require.NoError(t, err)
+	assert.Equal(t, time.Minute, cfg.App.Fetch.Timeout)
+	assert.Equal(t, 3, cfg.App.Fetch.MaxRedirects)
+	assert.Equal(t, map[string]FetchDomain{
+		"tracker.example": {
+			Headers: map[string]string{"X-Passkey": "secret"},
+			Cookies: map[string]string{"uid": "1", "pass": "abc"},
+		},
+		"other.example": {
+			Cookies: map[string]string{"session": "x"},
+		},
+	}, cfg.App.Fetch.Domains)
+
+	require.NoError(t, os.WriteFile(script, []byte(`
+		neptune.set("application.fetch.domains", {["tracker.example"] = {headers = {1}}})
+	`), 0644))
+	_, err = LoadFromLua(script)
+	require.Error(t, err)
+}
@@ -8,6 +8,7 @@ package dht
 import (
 	"net"
 	"net/netip"
+	"slices"
 	"sync"
 )
 
@@ -29,5 +30,8 @@ func (d *DHT) AddPeer(p netip.AddrPort) {
 	d.peersMutex.Lock()
 	defer d.peersMutex.Unlock()
 
+	if slices.Contains(d.peers, p) {
+		return
+	}
 	d.peers = append(d.peers, p)
 }
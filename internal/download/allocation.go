@@ -0,0 +1,207 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package download
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/dustin/go-humanize"
+	"github.com/trim21/errgo"
+
+	"neptune/internal/config"
+	"neptune/internal/meta"
+	"neptune/internal/piece_store"
+	"neptune/internal/pkg/bm"
+	"neptune/internal/pkg/diskfree"
+	"neptune/internal/pkg/fallocate"
+)
+
+// Allocation is how the files of a torrent get their disk space.
+type Allocation uint8
+
+const (
+	// AllocSparse creates files as they are written and lets the file system
+	// allocate space on demand.
+	AllocSparse Allocation = iota
+	// AllocFull reserves the space of every selected file with fallocate
+	// before the download starts.
+	AllocFull
+	// AllocZeroFill writes zeros to every selected file before the download
+	// starts, for file systems without fallocate.
+	AllocZeroFill
+)
+
+func (a Allocation) String() string {
+	switch a {
+	case AllocSparse:
+		return "sparse"
+	case AllocFull:
+		return "full"
+	case AllocZeroFill:
+		return "zero-fill"
+	}
+	return fmt.Sprintf("Allocation(%d)", a)
+}
+
+// AllocationFromString parses an allocation mode name as returned by String.
+func AllocationFromString(s string) (Allocation, error) {
+	switch s {
+	case "sparse":
+		return AllocSparse, nil
+	case "full":
+		return AllocFull, nil
+	case "zero-fill":
+		return AllocZeroFill, nil
+	}
+	return 0, fmt.Errorf("invalid allocation %q: must be 'sparse', 'full' or 'zero-fill'", s)
+}
+
+// AllocationStatus is the progress of allocating the files of a torrent
+// before its hash check.
+type AllocationStatus struct {
+	BytesDone  int64
+	BytesTotal int64
+}
+
+const zeroFillBufferSize = 1 << 20
+
+type allocationFile struct {
+	path   string
+	size   int64
+	length int64
+}
+
+// Allocation returns how the files of the torrent get their disk space.
+func (d *Download) Allocation() Allocation {
+	return d.allocation
+}
+
+// RequiredSpace returns the bytes the selected files of a torrent not added
+// yet still need under basePath, on top of the data already there.
+func RequiredSpace(app config.Application, info *meta.Info, basePath string, selectedFiles []int) (int64, error) {
+	selected, err := newSelectedFilesSet(len(info.Files), selectedFiles)
+	if err != nil {
+		return 0, err
+	}
+	parts := piece_store.NewPartFiles(info, basePath, partSuffix(app))
+	_, need, err := missingFiles(info, basePath, parts, selected)
+	return need, err
+}
+
+// RequiredSpace returns the bytes the selected files still need on disk.
+func (d *Download) RequiredSpace() (int64, error) {
+	info := d.fileInfo()
+	_, need, err := missingFiles(&info, d.BasePath(), d.parts, d.selectedFilesSet)
+	return need, err
+}
+
+// missingFiles returns the selected files smaller than their length, and the
+// bytes they need to reach it.
+func missingFiles(info *meta.Info, basePath string, parts *piece_store.PartFiles, selected *bm.Bitmap) ([]allocationFile, int64, error) {
+	var files []allocationFile
+	var need int64
+	for i, f := range info.Files {
+		if !selected.Contains(uint32(i)) {
+			continue
+		}
+		path := parts.Path(i, f.FullPath(basePath))
+		var size int64
+		if stat, err := os.Stat(path); err == nil {
+			size = stat.Size()
+		} else if !os.IsNotExist(err) {
+			return nil, 0, err
+		}
+		if size >= f.Length {
+			continue
+		}
+		files = append(files, allocationFile{path: path, size: size, length: f.Length})
+		need += f.Length - size
+	}
+	return files, need, nil
+}
+
+// allocateFiles gives every selected file its full size up front, unless the
+// allocation is sparse. It fails before touching any file if the disk does
+// not have the space the files still need.
+func (d *Download) allocateFiles() error {
+	if d.allocation == AllocSparse {
+		return nil
+	}
+
+	basePath := d.BasePath()
+	if err := os.MkdirAll(basePath, os.ModePerm); err != nil {
+		return err
+	}
+
+	info := d.fileInfo()
+	files, need, err := missingFiles(&info, basePath, d.parts, d.selectedFilesSet)
+	if err != nil {
+		return err
+	}
+	if need == 0 {
+		return nil
+	}
+
+	available, err := diskfree.Available(basePath)
+	if err != nil {
+		return errgo.Wrap(err, "failed to get free disk space")
+	}
+	if uint64(need) > available {
+		return fmt.Errorf("not enough disk space in %q: files need %s, %s available",
+			basePath, humanize.IBytes(uint64(need)), humanize.IBytes(available))
+	}
+
+	d.log.Info().Stringer("allocation", d.allocation).Int64("bytes", need).Msg("allocating files")
+	status := AllocationStatus{BytesTotal: need}
+	d.reportAllocation(status)
+	defer d.allocStatus.Store(nil)
+
+	for _, f := range files {
+		if err := d.allocateFile(f, &status); err != nil {
+			return errgo.Wrap(err, fmt.Sprintf("failed to allocate %q", f.path))
+		}
+	}
+	return nil
+}
+
+func (d *Download) allocateFile(file allocationFile, status *AllocationStatus) error {
+	if err := os.MkdirAll(filepath.Dir(file.path), os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file.path, os.O_RDWR|os.O_CREATE, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if d.allocation == AllocFull {
+		if err := fallocate.Fallocate(f, 0, file.length); err != nil {
+			return err
+		}
+		status.BytesDone += file.length - file.size
+		d.reportAllocation(*status)
+		return nil
+	}
+
+	buf := make([]byte, min(zeroFillBufferSize, file.length-file.size))
+	for off := file.size; off < file.length; {
+		if err := d.ctx.Err(); err != nil {
+			return err
+		}
+		n := min(int64(len(buf)), file.length-off)
+		if _, err := f.WriteAt(buf[:n], off); err != nil {
+			return err
+		}
+		off += n
+		status.BytesDone += n
+		d.reportAllocation(*status)
+	}
+	return nil
+}
+
+func (d *Download) reportAllocation(status AllocationStatus) {
+	d.allocStatus.Store(&status)
+}
@@ -0,0 +1,63 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package download
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/session/store"
+)
+
+func TestAllocationFromString(t *testing.T) {
+	for _, a := range []Allocation{AllocSparse, AllocFull, AllocZeroFill} {
+		got, err := AllocationFromString(a.String())
+		require.NoError(t, err)
+		require.Equal(t, a, got)
+	}
+
+	_, err := AllocationFromString("compact")
+	require.Error(t, err)
+}
+
+func TestAllocateFilesZeroFill(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	r := f.resumeData(t, store.ResumeStopped)
+	r.Allocation = uint8(AllocZeroFill)
+	d := f.load(t, r)
+	require.Equal(t, AllocZeroFill, d.Info(nil).Allocation)
+
+	require.NoError(t, d.allocateFiles())
+
+	stat, err := os.Stat(filepath.Join(f.basePath, f.info.Files[0].Path))
+	require.NoError(t, err)
+	require.Equal(t, f.info.TotalLength, stat.Size())
+	require.Nil(t, d.allocStatus.Load(), "progress is cleared when allocation is done")
+}
+
+func TestAllocateFilesNotEnoughSpace(t *testing.T) {
+	f := newResumeTestFixture(t, 1)
+	r := f.resumeData(t, store.ResumeStopped)
+	r.Allocation = uint8(AllocFull)
+	d := f.load(t, r)
+	d.info.Files[0].Length = 1 << 62
+
+	err := d.allocateFiles()
+	require.ErrorContains(t, err, "not enough disk space")
+	require.NoFileExists(t, filepath.Join(f.basePath, f.info.Files[0].Path))
+}
+
+func TestAllocateFilesSparse(t *testing.T) {
+	f := newResumeTestFixture(t, 1)
+	d := f.load(t, f.resumeData(t, store.ResumeStopped))
+	require.Equal(t, AllocSparse, d.allocation)
+
+	require.NoError(t, d.allocateFiles())
+	require.NoFileExists(t, filepath.Join(f.basePath, f.info.Files[0].Path))
+}
@@ -0,0 +1,203 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package download
+
+import (
+	"errors"
+	"fmt"
+
+	"neptune/internal/hashcheck"
+	"neptune/internal/pkg/bm"
+)
+
+var errNoPiecesToCheck = errors.New("no pieces to check")
+
+// checkSnapshot is the state a hash check replaced. Canceling the check puts
+// it back, and resume records saved while checking persist it instead of the
+// partial result.
+type checkSnapshot struct {
+	bitmap *bm.Bitmap
+	pieces *bm.Bitmap // the pieces being checked, nil for all of them
+	state  State
+}
+
+// queueCheck queues a hash check of pieces, all of them when nil, for a
+// download that just left state from for Checking. The check is queued
+// synchronously so rechecks keep the order they were requested in.
+func (d *Download) queueCheck(from State, pieces *bm.Bitmap) (*hashcheck.Check, error) {
+	// Stored first, the check queue is persisted with CheckPieces as soon as
+	// the check is queued.
+	d.checkRestore.Store(&checkSnapshot{bitmap: d.completedBm.Clone(), pieces: pieces, state: from})
+	check, err := d.session.HashCheck.Enqueue(d.info.Hash, d.BasePath())
+	if err != nil {
+		d.checkRestore.Store(nil)
+		d.finishCheck(from)
+		return nil, err
+	}
+	return check, nil
+}
+
+// CheckPieces returns the bitfield of the pieces a queued or running hash
+// check verifies, nil when it checks all of them or there is none.
+func (d *Download) CheckPieces() []byte {
+	r := d.checkRestore.Load()
+	if r == nil || r.pieces == nil {
+		return nil
+	}
+	return r.pieces.Bitfield()
+}
+
+// PieceRange is an inclusive range of piece indices.
+type PieceRange struct {
+	First uint32
+	Last  uint32
+}
+
+// RecheckPieces re-verifies the pieces of files and the pieces in ranges
+// through the check queue. The result is merged into the bitfield, other
+// pieces are left as they are.
+func (d *Download) RecheckPieces(files []int, ranges []PieceRange) error {
+	pieces := bm.New(d.info.NumPieces)
+	for _, index := range files {
+		if index < 0 || index >= len(d.info.Files) {
+			return fmt.Errorf("invalid file index %d", index)
+		}
+		start, end := d.info.FilePieces(index)
+		for i := start; i < end; i++ {
+			pieces.Set(i)
+		}
+	}
+	for _, r := range ranges {
+		if r.First > r.Last || r.Last >= d.info.NumPieces {
+			return fmt.Errorf("invalid piece range %d-%d, the torrent has %d pieces", r.First, r.Last, d.info.NumPieces)
+		}
+		for i := r.First; i <= r.Last; i++ {
+			pieces.Set(i)
+		}
+	}
+	if pieces.Count() == 0 {
+		return errNoPiecesToCheck
+	}
+	return d.asyncCheckPieces(pieces)
+}
+
+// RestoreCheck queues a hash check saved in the check queue before a
+// restart, of the pieces in the bitfield or all of them when it is nil.
+func (d *Download) RestoreCheck(pieces []byte) error {
+	if pieces == nil {
+		return d.AsyncCheck()
+	}
+	if len(pieces) != int(d.bitfieldSize) {
+		return fmt.Errorf("bitfield of queued check has %d bytes, want %d", len(pieces), d.bitfieldSize)
+	}
+	return d.asyncCheckPieces(bm.FromBitfields(pieces, d.info.NumPieces))
+}
+
+// finishCheck leaves Checking for a state validTransition does not allow from
+// Checking, such as Stopped after a canceled check.
+func (d *Download) finishCheck(state State) {
+	d.transitionMu.Lock()
+	if State(d.state.Load()) == Checking {
+		d.commitStateTransition(Checking, state)
+	}
+	d.transitionMu.Unlock()
+	d.stateCond.Broadcast()
+}
+
+// restoreCanceledCheck puts back the bitfield and state saved by queueCheck.
+// A canceled completion recheck finishes the download unverified, as if
+// recheck-on-complete was off.
+func (d *Download) restoreCanceledCheck(afterSeeding func()) {
+	r := d.checkRestore.Load()
+	if r == nil {
+		d.finishCheck(Stopped)
+		return
+	}
+
+	d.restoreCheckBitmap(r)
+	d.pieceDownloadRate.Reset()
+
+	state := r.state
+	if afterSeeding != nil && state == Downloading && d.isComplete() {
+		state = Seeding
+	}
+	if !d.isComplete() {
+		d.completedOnce.Store(false)
+	}
+
+	d.finishCheck(state)
+	if state == Seeding && afterSeeding != nil {
+		afterSeeding()
+	}
+	d.log.Info().Stringer("state", state).Msg("hash check canceled")
+	d.saveResume()
+}
+
+// restoreCheckBitmap puts back the bitfield saved by queueCheck.
+func (d *Download) restoreCheckBitmap(r *checkSnapshot) {
+	d.completedBm.Clear()
+	d.completedBm.OR(r.bitmap)
+	d.setMissingFromWantedSync()
+	d.completed.Store(d.computeCompletedUnsafe())
+	d.initializePiecePicker()
+	d.restoreWrittenBlocks()
+}
+
+// asyncCheckPieces re-verifies only the given pieces through the check queue.
+// They are unmarked until verified again; the rest of the bitfield is kept.
+func (d *Download) asyncCheckPieces(pieces *bm.Bitmap) error {
+	if err := d.checkMount(); err != nil {
+		return err
+	}
+
+	transition, err := d.transition(Checking)
+	if err != nil {
+		return err
+	}
+
+	check, err := d.queueCheck(transition.from, pieces)
+	if err != nil {
+		return err
+	}
+
+	pieces.Range(d.completedBm.Unset)
+	d.setMissingFromWantedSync()
+	d.completed.Store(d.computeCompletedUnsafe())
+	d.stateCond.Broadcast()
+
+	d.runHashCheck(check, pieces, nil)
+	return nil
+}
+
+// restoreWrittenBlocks marks blocks already written for missing pieces as
+// done in a new picker, so they are not requested again. A missing piece with
+// every block written never passed its hash check and is downloaded again.
+func (d *Download) restoreWrittenBlocks() {
+	picker := d.picker.Load()
+	if picker == nil {
+		return
+	}
+
+	var blocks []uint32
+	d.missingBm.Range(func(index uint32) {
+		start := index * d.normalChunkLen
+		n := uint32(d.info.PieceBlockCount(index))
+		blocks = blocks[:0]
+		for i := range n {
+			if d.writtenBlocks.Contains(start + i) {
+				blocks = append(blocks, i)
+			}
+		}
+
+		switch len(blocks) {
+		case 0:
+		case int(n):
+			for i := range n {
+				d.writtenBlocks.Unset(start + i)
+			}
+		default:
+			picker.RestoreBlocks(index, blocks)
+		}
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package download
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+)
+
+// CompletePath returns the base path the data moves to when the download
+// completes, empty when it stays where it is.
+func (d *Download) CompletePath() string {
+	d.s.mu.RLock()
+	defer d.s.mu.RUnlock()
+	return d.s.completePath
+}
+
+// pendingCompletePath returns the complete path if the data is not there yet.
+func (d *Download) pendingCompletePath() string {
+	d.s.mu.RLock()
+	defer d.s.mu.RUnlock()
+	return completePathPending(d.s.basePath, d.s.completePath)
+}
+
+// completePathPending returns the absolute complete path if the data at
+// basePath is not there yet, or empty.
+func completePathPending(basePath, completePath string) string {
+	if completePath == "" {
+		return ""
+	}
+	target, err := filepath.Abs(completePath)
+	if err != nil {
+		return completePath
+	}
+	if source, err := filepath.Abs(basePath); err == nil && source == target {
+		return ""
+	}
+	return target
+}
+
+// runCompletedHook runs the completed hook once the data is in its final
+// location. A download with a pending complete path is moved there first. If
+// the move fails, the torrent keeps seeding from where it is and the error is
+// reported as its message.
+func (d *Download) runCompletedHook() {
+	target := d.pendingCompletePath()
+	if target == "" {
+		d.fireCompletedHook()
+		return
+	}
+
+	go func() {
+		d.moveToCompletePath(target)
+		d.fireCompletedHook()
+	}()
+}
+
+// moveToCompletePath moves the data to target, the pending complete path. A
+// failure is saved with the resume data, the move is tried again when the
+// download is loaded or started.
+func (d *Download) moveToCompletePath(target string) {
+	m, err := d.beginMove(target)
+	if err != nil {
+		d.moveStatus.Store(&MoveStatus{Target: target, Error: err.Error()})
+	} else {
+		err = d.runMove(m)
+	}
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			d.log.Err(err).Str("target", target).Msg("failed to move completed download")
+		}
+		d.saveResume()
+	}
+}
+
+// failedCompleteMove returns the status of the last move to the pending
+// complete path if it failed, or nil.
+func (d *Download) failedCompleteMove() *MoveStatus {
+	s := d.moveStatus.Load()
+	if s == nil || s.Error == "" || s.Target != d.pendingCompletePath() {
+		return nil
+	}
+	return s
+}
+
+// retryCompleteMove moves a seeding download to its complete path again if the
+// last move there failed. The completed hook already ran, it does not run
+// again.
+func (d *Download) retryCompleteMove() {
+	s := d.failedCompleteMove()
+	if s == nil || d.GetState() != Seeding {
+		return
+	}
+	d.log.Info().Str("target", s.Target).Str("error", s.Error).Msg("retrying move to complete path")
+	go d.moveToCompletePath(s.Target)
+}
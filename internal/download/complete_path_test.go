@@ -0,0 +1,88 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package download
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/session/store"
+)
+
+func TestCompletedDownloadMovesToCompletePath(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	f.writeDataFile(t)
+	hookOut := filepath.Join(t.TempDir(), "hook")
+	f.sess.Config.App.Hook.OnDownloadCompleted = `printf %s "$NEPTUNE_SAVE_PATH" > "` + hookOut + `"`
+
+	target := filepath.Join(t.TempDir(), "complete")
+	r := f.resumeData(t, store.ResumeActive, 0, 1)
+	r.CompletePath = target
+	d := f.load(t, r)
+	require.Equal(t, target, d.CompletePath())
+
+	d.runCompletedHook()
+	require.Eventually(t, func() bool {
+		data, err := os.ReadFile(hookOut)
+		return err == nil && string(data) == target
+	}, 5*time.Second, 10*time.Millisecond, "the hook runs with the final path")
+
+	require.Equal(t, target, d.BasePath())
+	require.Empty(t, d.CompletePath())
+	require.Equal(t, Seeding, d.GetState())
+	require.FileExists(t, filepath.Join(target, f.info.Files[0].Path))
+	require.Empty(t, d.resumeRecord().CompletePath)
+	require.Empty(t, d.ErrorMsg())
+}
+
+func TestFailedCompletionMoveKeepsSeeding(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	f.writeDataFile(t)
+
+	target := filepath.Join(t.TempDir(), "complete")
+	require.NoError(t, os.MkdirAll(target, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(target, f.info.Files[0].Path), nil, 0o644))
+	r := f.resumeData(t, store.ResumeActive, 0, 1)
+	r.CompletePath = target
+	d := f.load(t, r)
+
+	d.runCompletedHook()
+	require.Eventually(t, func() bool {
+		s := d.MoveStatus()
+		return s != nil && s.Error != "" && d.GetState() == Seeding
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Equal(t, f.basePath, d.BasePath())
+	require.Equal(t, target, d.CompletePath(), "the move is tried again on the next completion")
+	require.Contains(t, d.ErrorMsg(), "failed to move data to "+target)
+	require.Contains(t, d.resumeRecord().CompleteMoveError, "exists")
+}
+
+func TestFailedCompletionMoveRetriedOnLoad(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	f.writeDataFile(t)
+	hookOut := filepath.Join(t.TempDir(), "hook")
+	f.sess.Config.App.Hook.OnDownloadCompleted = `touch "` + hookOut + `"`
+
+	target := filepath.Join(t.TempDir(), "complete")
+	r := f.resumeData(t, store.ResumeActive, 0, 1)
+	r.CompletePath = target
+	r.CompleteMoveError = "disk full"
+	d := f.load(t, r)
+
+	require.Eventually(t, func() bool {
+		return d.BasePath() == target && d.GetState() == Seeding
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Empty(t, d.CompletePath())
+	require.Empty(t, d.resumeRecord().CompleteMoveError)
+	require.Empty(t, d.ErrorMsg())
+	require.FileExists(t, filepath.Join(target, f.info.Files[0].Path))
+	require.NoFileExists(t, hookOut, "the completed hook already ran before the failed move")
+}
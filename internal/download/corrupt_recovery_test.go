@@ -105,6 +105,8 @@ func newTestEnv(t *testing.T, numPieces, blocksPerPiece uint32, failPieces []uin
 
 	d.completedBm = completedBm
 	d.missingBm = missingBm
+	d.writtenBlocks = bm.NewNilSafeLockFreeBitmap(info.TotalBlockCount())
+	d.sharedFiles = bm.NewLockFreeBitmap(uint32(len(info.Files)))
 	d.wantedBm = wantedBm
 	d.peerList = newPeerList(d)
 	d.picker.Store(NewPiecePicker(info, missingBm, nil, nil, NewRequestGate(&d.state, uint32(Downloading))))
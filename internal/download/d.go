@@ -24,6 +24,7 @@ import (
 	"neptune/internal/pkg/ratelimit"
 	"neptune/internal/proto"
 	"neptune/internal/session"
+	"neptune/internal/session/store"
 )
 
 const defaultBlockSize = meta.DefaultBlockSize
@@ -198,35 +199,44 @@ type Download struct {
 	log                    zerolog.Logger
 	AddAt                  time.Time
 	ctx                    context.Context
-	store                  piece_store.PieceStore        // Never nil.
-	picker                 atomic.Pointer[PiecePicker]   // nil during initial checking or when the download is complete
-	session                *session.Session              // Never nil.
-	pieceDownloadRate      *flowrate.Monitor             // Never nil.
-	ioDownloadRate         *flowrate.Monitor             // Never nil.
-	pieceUploadRate        *flowrate.Monitor             // Never nil.
-	resChan                chan chunkSubmit              // Never nil.
-	uploadLimiter          *ratelimit.Limiter            // Never nil.
-	peersCh                chan []tracker.DiscoveredPeer // Never nil.
-	peerList               *peerList                     // Never nil.
-	stateCond              *gsync.Cond                   // Never nil.
-	connectSignal          chan struct{}                 // Never nil in real downloads.
-	downloadLimiter        *ratelimit.Limiter            // Never nil.
-	err                    atomic.Pointer[error]         // nil unless download enters Error state
-	cancel                 context.CancelFunc            // Never nil after New().
-	scheduleResponseSignal chan empty.Empty              // Never nil.
-	tracker                *tracker.Trackers             // Never nil.
-	completedBm            *bm.Bitmap                    // Never nil.
-	missingBm              *bm.LockFreeBitmap            // Never nil.
-	wantedBm               *bm.Bitmap                    // Never nil.
-	selectedFilesSet       *bm.Bitmap                    // Never nil.
-	corruptedPieces        map[uint32]int                // Never nil.
-	moveCancel             context.CancelFunc            // nil unless a move operation is in progress
+	store                  piece_store.PieceStore           // Never nil.
+	picker                 atomic.Pointer[PiecePicker]      // nil during initial checking or when the download is complete
+	session                *session.Session                 // Never nil.
+	pieceDownloadRate      *flowrate.Monitor                // Never nil.
+	ioDownloadRate         *flowrate.Monitor                // Never nil.
+	pieceUploadRate        *flowrate.Monitor                // Never nil.
+	resChan                chan chunkSubmit                 // Never nil.
+	uploadLimiter          *ratelimit.Limiter               // Never nil.
+	peersCh                chan []tracker.DiscoveredPeer    // Never nil.
+	peerList               *peerList                        // Never nil.
+	stateCond              *gsync.Cond                      // Never nil.
+	connectSignal          chan struct{}                    // Never nil in real downloads.
+	missingDataSignal      chan struct{}                    // Never nil in real downloads.
+	downloadLimiter        *ratelimit.Limiter               // Never nil.
+	err                    atomic.Pointer[error]            // nil unless download enters Error state
+	cancel                 context.CancelFunc               // Never nil after New().
+	scheduleResponseSignal chan empty.Empty                 // Never nil.
+	tracker                *tracker.Trackers                // Never nil.
+	completedBm            *bm.Bitmap                       // Never nil.
+	missingBm              *bm.LockFreeBitmap               // Never nil.
+	writtenBlocks          *bm.NilSafeLockFreeBitmap        // Never nil. Blocks handed to the store, by global block index.
+	wantedBm               *bm.Bitmap                       // Never nil.
+	selectedFilesSet       *bm.Bitmap                       // Never nil.
+	sharedFiles            *bm.LockFreeBitmap               // Never nil. Files the store only reads, see InitState.SharedFiles.
+	corruptedPieces        map[uint32]int                   // Never nil.
+	moveCancel             context.CancelFunc               // nil unless a move operation is in progress
+	moveStatus             atomic.Pointer[MoveStatus]       // nil unless a move is in progress or the last one failed
+	allocStatus            atomic.Pointer[AllocationStatus] // nil unless files are being allocated
+	checkRestore           atomic.Pointer[checkSnapshot]    // nil unless a hash check is queued or running
+	seedMode               atomic.Pointer[seedMode]         // nil unless pieces trusted by skip_hash_check are not verified yet
+	parts                  *piece_store.PartFiles           // nil unless part files are enabled
 	s                      downloadState
 	info                   meta.Info
 	backgroundWg           sync.WaitGroup
 	unchokeSlotIdx         int
 	wastedDupe             atomic.Int64
 	completedAt            atomic.Int64
+	verifiedAt             atomic.Int64 // unix nanoseconds all data was last hash checked, 0 when never
 	state                  atomic.Uint32
 	downloaded             atomic.Int64
 	pendingBytes           atomic.Int64
@@ -249,12 +259,18 @@ type Download struct {
 	unchokeCycleOffset int
 	queueWeight        atomic.Int64
 	completedOnce      atomic.Bool
+	scrubbing          atomic.Bool
+	scrubCursor        atomic.Uint32 // next piece of an unfinished scrub, 0 when none is in progress
+	scrubFailed        atomic.Uint32 // pieces the current or last scrub found corrupt
+	diskSpacePaused    atomic.Bool
+	dhtNodesAdded      atomic.Bool
 	moveCancelMu       sync.RWMutex
 	transitionMu       sync.Mutex
 	corruptedPiecesMu  sync.Mutex
 	normalChunkLen     uint32
 	bitfieldSize       uint32
 	peerID             proto.PeerID
+	allocation         Allocation
 	private            bool
 }
 
@@ -271,8 +287,20 @@ type downloadState struct {
 	custom      map[string]string
 	basePath    string
 	downloadDir string
-	tags        []string
-	mu          sync.RWMutex
+	// completePath is where the data moves when the download completes,
+	// empty when it stays in basePath.
+	completePath string
+	// mountPoint is the mount basePath was on when the data was saved there,
+	// empty when unknown. Writes fail while basePath is on another one.
+	mountPoint string
+	tags       []string
+	// fileStats is the size and mtime of each file at the last save, used to
+	// find files changed behind our back. Kept while Stopped so StartTorrent
+	// compares against the state at stop time.
+	fileStats []store.FileStat
+	// mu also guards the paths of d.info.Files, which renames change in
+	// place. Readers outside of transitionMu use fileInfo.
+	mu sync.RWMutex
 }
 
 func (d *Download) GetState() State {
@@ -348,6 +376,10 @@ func (d *Download) ErrorMsg() string {
 	if e := d.err.Load(); e != nil {
 		return (*e).Error()
 	}
+	// a failed move to the complete path leaves no one else to report it to
+	if s := d.failedCompleteMove(); s != nil {
+		return fmt.Sprintf("failed to move data to %s: %s", s.Target, s.Error)
+	}
 	return ""
 }
 
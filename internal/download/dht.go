@@ -0,0 +1,58 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package download
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strconv"
+	"time"
+)
+
+const dhtNodeResolveTimeout = 10 * time.Second
+
+// addDHTNodes hands the nodes of the torrent file to the DHT as bootstrap
+// hints. Private torrents never use the DHT.
+func (d *Download) addDHTNodes() {
+	ctx, cancel := context.WithTimeout(d.ctx, dhtNodeResolveTimeout)
+	defer cancel()
+
+	for _, addr := range resolveDHTNodes(ctx, net.DefaultResolver, d.info.Nodes) {
+		d.session.DHT.AddPeer(addr)
+	}
+}
+
+// resolveDHTNodes turns "host:port" nodes into addresses, looking up host
+// names. Nodes that are malformed or don't resolve are skipped.
+func resolveDHTNodes(ctx context.Context, r *net.Resolver, nodes []string) []netip.AddrPort {
+	var addrs []netip.AddrPort
+	for _, node := range nodes {
+		host, portStr, err := net.SplitHostPort(node)
+		if err != nil {
+			continue
+		}
+		port, err := strconv.ParseUint(portStr, 10, 16)
+		if err != nil || port == 0 {
+			continue
+		}
+
+		if ip, err := netip.ParseAddr(host); err == nil {
+			addrs = append(addrs, netip.AddrPortFrom(ip.Unmap(), uint16(port)))
+			continue
+		}
+
+		ips, err := r.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		for _, ip := range ips {
+			addrs = append(addrs, netip.AddrPortFrom(ip.Unmap(), uint16(port)))
+		}
+	}
+	return addrs
+}
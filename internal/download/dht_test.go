@@ -0,0 +1,28 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package download
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDHTNodes(t *testing.T) {
+	addrs := resolveDHTNodes(t.Context(), net.DefaultResolver, []string{
+		"1.2.3.4:6881",
+		"[::1]:80",
+		"udp://tracker.example:80",
+		"5.6.7.8:0",
+		"5.6.7.8",
+	})
+	require.Equal(t, []netip.AddrPort{
+		netip.MustParseAddrPort("1.2.3.4:6881"),
+		netip.MustParseAddrPort("[::1]:80"),
+	}, addrs)
+}
@@ -4,6 +4,7 @@
 package download
 
 import (
+	"errors"
 	"slices"
 	"sync"
 	"time"
@@ -11,7 +12,9 @@ import (
 	"github.com/trim21/errgo"
 
 	"neptune/internal/client/tracker"
+	"neptune/internal/hashcheck"
 	"neptune/internal/meta"
+	"neptune/internal/piece_store"
 	"neptune/internal/pkg/as"
 	"neptune/internal/pkg/bm"
 	"neptune/internal/pkg/empty"
@@ -84,7 +87,7 @@ func (d *Download) backgroundResHandler() {
 	defer d.log.Info().Msg("backgroundResHandler: exiting")
 	var h heap.Heap[responseChunk]
 	pc := &peerContributors{m: make(map[uint32]map[uint64]empty.Empty)}
-	done := bm.NewNilSafeLockFreeBitmap(d.info.TotalBlockCount())
+	done := d.writtenBlocks
 	pending := bm.NewNilSafeLockFreeBitmap(d.info.TotalBlockCount())
 	for {
 		select {
@@ -132,6 +135,10 @@ func drainHeap(d *Download, h *heap.Heap[responseChunk], pc *peerContributors, d
 		flushContiguousFromHeap(d, h, pc, done, pending)
 	}
 	d.log.Info().Int("chunks", heapLen).Msg("drainHeap: done")
+	// Chunks drained after Stop were written after its file stats.
+	if heapLen > 0 && d.HasState(Stopped) {
+		d.refreshFileStats()
+	}
 	// Reset heap to release backing array. download completed → seeding,
 	// this goroutine keeps running so the local var won't be GC'd.
 	*h = heap.Heap[responseChunk]{}
@@ -526,6 +533,7 @@ func (d *Download) checkPiece(pieceIndex uint32, pc *peerContributors, done *bm.
 		d.corruptedPiecesMu.Unlock()
 		d.log.Trace().Msgf("piece %d done", pieceIndex)
 		d.have(pieceIndex)
+		d.finishPartFiles(pieceIndex)
 	}
 
 	// Hash verification removes the piece from downloadingPieces. Wake peers
@@ -661,7 +669,7 @@ func (d *Download) checkDone(done, pending *bm.NilSafeLockFreeBitmap) {
 func (d *Download) finalizeDownloadCompletion() {
 	d.completedAt.Store(time.Now().UnixNano())
 	d.pieceDownloadRate.Reset()
-	d.fireCompletedHook()
+	d.runCompletedHook()
 
 	d.peerList.Range(func(_ uint64, p Peer) bool {
 		if uint32(p.PeerBitmap().Count()) == d.info.NumPieces {
@@ -687,28 +695,48 @@ func (d *Download) finalizeDownloadCompletion() {
 // completes the download (Seeding + announce) when all pass, or goes back to
 // Downloading so corrupt pieces are re-fetched.
 func (d *Download) recheckAfterComplete() {
-	if _, err := d.transition(Checking); err != nil {
+	transition, err := d.transition(Checking)
+	if err != nil {
 		d.completedOnce.Store(false)
 		d.log.Error().Err(err).Msg("failed to start completion recheck")
 		return
 	}
+	check, err := d.queueCheck(transition.from, nil)
+	if err != nil {
+		d.completedOnce.Store(false)
+		d.log.Error().Err(err).Msg("failed to queue completion recheck")
+		return
+	}
 	d.completedBm.Clear()
 	d.setMissingFromWantedSync()
 	d.picker.Load().ResetAll()
 	d.completed.Store(0)
 	d.stateCond.Broadcast()
 
-	d.runHashCheck(d.finalizeDownloadCompletion)
+	d.runHashCheck(check, nil, d.finalizeDownloadCompletion)
 }
 
-// runHashCheck spawns a goroutine that re-hashes all pieces via initCheck.
-// When all pieces pass, afterSeeding is called after transition(Seeding).
-func (d *Download) runHashCheck(afterSeeding func()) {
+// runHashCheck spawns a goroutine that re-hashes pieces via initCheck, all of
+// them when pieces is nil. When all pieces pass, afterSeeding is called after
+// transition(Seeding). A canceled check restores the bitfield and state from
+// before the check.
+func (d *Download) runHashCheck(check *hashcheck.Check, pieces *bm.Bitmap, afterSeeding func()) {
 	go func() {
-		if err := d.initCheck(); err != nil {
+		defer d.checkRestore.Store(nil)
+		if err := d.initCheck(check, pieces); err != nil {
 			if d.ctx.Err() != nil {
 				return
 			}
+			if errors.Is(err, hashcheck.ErrCanceled) {
+				d.restoreCanceledCheck(afterSeeding)
+				return
+			}
+			// A check that found the mount missing verified nothing, the
+			// data is still on the disk that is not mounted.
+			var mountErr *piece_store.MountError
+			if r := d.checkRestore.Load(); r != nil && errors.As(err, &mountErr) {
+				d.restoreCheckBitmap(r)
+			}
 			// completedOnce guards the completion sequence; a failed recheck
 			// must release it so a later completion is not blocked forever.
 			d.completedOnce.Store(false)
@@ -717,8 +745,13 @@ func (d *Download) runHashCheck(afterSeeding func()) {
 			return
 		}
 
+		d.seedModeChecked(pieces)
+		if pieces == nil {
+			d.fullyVerified()
+		}
 		d.completed.Store(d.computeCompletedUnsafe())
 		d.initializePiecePicker()
+		d.restoreWrittenBlocks()
 		allDone := d.isComplete()
 		d.pieceDownloadRate.Reset()
 
@@ -51,6 +51,26 @@ func (s *FailOnceStore) Move(ctx context.Context, target string, report piece_st
 	return s.inner.Move(ctx, target, report)
 }
 
+func (s *FailOnceStore) Relocate(basePath string) {
+	s.inner.Relocate(basePath)
+}
+
+func (s *FailOnceStore) RenameFile(index int, path string) error {
+	return s.inner.RenameFile(index, path)
+}
+
+func (s *FailOnceStore) UnshareFile(index int, path string) (bool, error) {
+	return s.inner.UnshareFile(index, path)
+}
+
+func (s *FailOnceStore) SetFileComplete(index int, complete bool) error {
+	return s.inner.SetFileComplete(index, complete)
+}
+
+func (s *FailOnceStore) SetMountPoint(mount string) {
+	s.inner.SetMountPoint(mount)
+}
+
 // FailNPieceStore wraps a PieceStore and fails the first N pieces
 // on their first verification.
 type FailNPieceStore struct {
@@ -94,3 +114,23 @@ func (s *FailNPieceStore) VerifyPiece(ctx context.Context, pieceIndex uint32, ex
 func (s *FailNPieceStore) Move(ctx context.Context, target string, report piece_store.MoveProgressFunc) error {
 	return s.inner.Move(ctx, target, report)
 }
+
+func (s *FailNPieceStore) Relocate(basePath string) {
+	s.inner.Relocate(basePath)
+}
+
+func (s *FailNPieceStore) RenameFile(index int, path string) error {
+	return s.inner.RenameFile(index, path)
+}
+
+func (s *FailNPieceStore) UnshareFile(index int, path string) (bool, error) {
+	return s.inner.UnshareFile(index, path)
+}
+
+func (s *FailNPieceStore) SetFileComplete(index int, complete bool) error {
+	return s.inner.SetFileComplete(index, complete)
+}
+
+func (s *FailNPieceStore) SetMountPoint(mount string) {
+	s.inner.SetMountPoint(mount)
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package download
+
+import (
+	"os"
+
+	"neptune/internal/meta"
+	"neptune/internal/piece_store"
+	"neptune/internal/pkg/bm"
+	"neptune/internal/session/store"
+)
+
+// statFiles returns the current size and mtime of every file of the torrent.
+func statFiles(info meta.Info, basePath string, parts *piece_store.PartFiles) []store.FileStat {
+	stats := make([]store.FileStat, len(info.Files))
+	for i, f := range info.Files {
+		stat, err := os.Stat(parts.Path(i, f.FullPath(basePath)))
+		if err != nil {
+			stats[i] = store.FileStat{Size: -1}
+			continue
+		}
+		stats[i] = store.FileStat{Size: stat.Size(), ModTime: stat.ModTime().UnixNano()}
+	}
+	return stats
+}
+
+// changedFilePieces returns the pieces of completedBm overlapping a file whose
+// size or mtime differs between saved and current. Without a saved baseline
+// (records from older versions) nothing is reported and the bitfield is
+// trusted as before.
+func changedFilePieces(info meta.Info, completedBm *bm.Bitmap, saved, current []store.FileStat) *bm.Bitmap {
+	changed := bm.New(info.NumPieces)
+	if len(saved) != len(info.Files) || len(current) != len(info.Files) {
+		return changed
+	}
+
+	changedFiles := make(map[int]bool)
+	for i := range info.Files {
+		if saved[i] != current[i] {
+			changedFiles[i] = true
+		}
+	}
+	if len(changedFiles) == 0 {
+		return changed
+	}
+
+	completedBm.Range(func(index uint32) {
+		for chunk := range info.PieceFileChunks(index) {
+			if changedFiles[chunk.FileIndex] {
+				changed.Set(index)
+				return
+			}
+		}
+	})
+	return changed
+}
+
+// changedPieces returns verified pieces whose files changed since the last
+// recorded file stats.
+func (d *Download) changedPieces() *bm.Bitmap {
+	d.s.mu.RLock()
+	saved := d.s.fileStats
+	basePath := d.s.basePath
+	d.s.mu.RUnlock()
+
+	return changedFilePieces(d.info, d.completedBm, saved, statFiles(d.fileInfo(), basePath, d.parts))
+}
+
+// refreshFileStats records the current file stats as the baseline, after
+// writes that happen outside a regular resume save: the chunks drained on
+// Stop and the last writes before Close.
+func (d *Download) refreshFileStats() {
+	stats := statFiles(d.fileInfo(), d.BasePath(), d.parts)
+	d.s.mu.Lock()
+	d.s.fileStats = stats
+	d.s.mu.Unlock()
+
+	if d.session.Store == nil {
+		return
+	}
+	if err := d.session.Store.SaveFileStats(d.info.Hash.Hex(), stats); err != nil {
+		d.log.Err(err).Msg("failed to save file stats")
+	}
+}
+
+// verifyChangedPieces re-verifies pieces found by changedPieces and leaves
+// the rest of the bitfield alone. It reports whether a check was queued.
+func (d *Download) verifyChangedPieces(pieces *bm.Bitmap) bool {
+	if pieces.Count() == 0 {
+		return false
+	}
+
+	d.log.Info().Uint32("pieces", pieces.Count()).Msg("files changed since last save, re-verifying their pieces")
+	if err := d.asyncCheckPieces(pieces); err != nil {
+		d.log.Err(err).Msg("failed to re-verify changed files")
+		return false
+	}
+	return true
+}
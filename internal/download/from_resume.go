@@ -13,6 +13,7 @@ import (
 
 	"neptune/internal/meta"
 	"neptune/internal/metainfo"
+	"neptune/internal/piece_store"
 	"neptune/internal/pkg/bm"
 	"neptune/internal/session"
 	"neptune/internal/session/store"
@@ -55,16 +56,32 @@ func LoadFromResume(sess *session.Session, r store.Resume, trackerStagger time.D
 	if err != nil {
 		return nil, err
 	}
+	// Part files are renamed when they complete, the names on disk tell
+	// which one each file has.
+	parts := piece_store.NewPartFiles(&info, r.BasePath, partSuffix(sess.Config.App))
 	completedBm := bm.FromBitfields(r.Bitfield, info.NumPieces)
-	if _, err := validateResumeBitfield(info, r.BasePath, selectedFilesSet, completedBm); err != nil {
-		return nil, err
+	// With the mount missing the files are not where they were saved, the
+	// bitfield is kept as it is until the mount comes back.
+	mountErr := piece_store.CheckMount(r.BasePath, r.MountPoint)
+	if mountErr == nil {
+		if _, err := validateResumeBitfield(info, r.BasePath, parts, selectedFilesSet, completedBm); err != nil {
+			return nil, err
+		}
 	}
 
 	wantedBm := buildWantedBm(info, selectedFilesSet)
 	complete := wantedBm.WithAndNot(completedBm).Count() == 0
+	var partialPieces []partialPiece
+	if mountErr == nil {
+		partialPieces = restorablePartialPieces(info, r.BasePath, parts, wantedBm.WithAndNot(completedBm), r.PartialPieces)
+	}
 	state := Downloading
 	if r.State == store.ResumeStopped {
+		// Start checks the mount again.
 		state = Stopped
+		mountErr = nil
+	} else if mountErr != nil {
+		state = Error
 	} else if complete {
 		state = Seeding
 	}
@@ -86,14 +103,26 @@ func LoadFromResume(sess *session.Session, r store.Resume, trackerStagger time.D
 		s = 0
 	}
 
+	// Unknown values from newer versions fall back to sparse.
+	allocation := Allocation(r.Allocation)
+	if allocation > AllocZeroFill {
+		allocation = AllocSparse
+	}
+
 	return New(sess, m, info, r.BasePath, r.Tags, r.Custom, r.SelectedFiles, InitState{
 		CompletedPieces:   completedBm,
 		State:             state,
 		PiecePickStrategy: PiecePickStrategy(s),
 		TrackerStagger:    trackerStagger,
+		CompletePath:      r.CompletePath,
+		Allocation:        allocation,
+		SharedFiles:       r.SharedFiles,
 		resume: &resumeInitState{
 			addAt:              r.AddAt.Time,
 			completedAt:        r.CompletedAt.Time,
+			verifiedAt:         r.VerifiedAt.Time,
+			completeMoveError:  r.CompleteMoveError,
+			scrubCursor:        r.ScrubCursor,
 			trackers:           metainfo.AnnounceList(r.Trackers),
 			trackerKey:         r.TrackerKey,
 			downloaded:         r.Downloaded,
@@ -102,6 +131,12 @@ func LoadFromResume(sess *session.Session, r store.Resume, trackerStagger time.D
 			downloadSpeedLimit: r.DownloadSpeedLimit,
 			uploadSpeedLimit:   r.UploadSpeedLimit,
 			queueWeight:        r.QueueWeight,
+			partialPieces:      partialPieces,
+			fileStats:          r.FileStats,
+			mountPoint:         r.MountPoint,
+			mountErr:           mountErr,
+			unverified:         r.Unverified,
+			parts:              parts,
 		},
 	})
 }
@@ -127,13 +162,13 @@ func buildWantedBm(info meta.Info, selectedFilesSet *bm.Bitmap) *bm.Bitmap {
 // validateResumeBitfield checks that pieces marked in completedBm still have
 // their backing files on disk. Pieces whose file data is missing or truncated
 // are cleared from the bitmap. Returns the total byte count of invalidated pieces.
-func validateResumeBitfield(info meta.Info, basePath string, selectedFilesSet *bm.Bitmap, completedBm *bm.Bitmap) (invalidBytes int64, err error) {
+func validateResumeBitfield(info meta.Info, basePath string, parts *piece_store.PartFiles, selectedFilesSet *bm.Bitmap, completedBm *bm.Bitmap) (invalidBytes int64, err error) {
 	fileSizes := make(map[int]int64, len(info.Files)+1)
 	for i, tf := range info.Files {
 		if !selectedFilesSet.Contains(uint32(i)) {
 			continue
 		}
-		p := filepath.Join(basePath, tf.Path)
+		p := parts.Path(i, tf.FullPath(basePath))
 		stat, err := os.Stat(p)
 		if err != nil {
 			if os.IsNotExist(err) {
@@ -161,3 +196,74 @@ func validateResumeBitfield(info meta.Info, basePath string, selectedFilesSet *b
 	}
 	return invalidBytes, nil
 }
+
+// partialPiece is a piece with some blocks already written to disk.
+type partialPiece struct {
+	blocks []uint32
+	index  uint32
+}
+
+// restorablePartialPieces decodes persisted partial pieces of missingBm and
+// keeps the blocks whose file data is still on disk. Entries that are
+// malformed, no longer missing or fully written are dropped; their blocks are
+// simply downloaded again.
+func restorablePartialPieces(info meta.Info, basePath string, parts *piece_store.PartFiles, missingBm *bm.Bitmap, saved []store.PartialPiece) []partialPiece {
+	if len(saved) == 0 {
+		return nil
+	}
+
+	fileSizes := make(map[int]int64)
+	fileSize := func(i int) int64 {
+		if size, ok := fileSizes[i]; ok {
+			return size
+		}
+		var size int64 = -1
+		if stat, err := os.Stat(parts.Path(i, info.Files[i].FullPath(basePath))); err == nil {
+			size = stat.Size()
+		}
+		fileSizes[i] = size
+		return size
+	}
+
+	var r []partialPiece
+	for _, p := range saved {
+		if p.Index >= info.NumPieces || !missingBm.Contains(p.Index) {
+			continue
+		}
+		n := uint32(info.PieceBlockCount(p.Index))
+		if len(p.Blocks) != int((n+7)/8) {
+			continue
+		}
+
+		pieceStart := int64(p.Index) * info.PieceLength
+		pieceEnd := pieceStart + info.PieceLen(p.Index)
+		var blocks []uint32
+		bm.FromBitfields(p.Blocks, n).Range(func(b uint32) {
+			start := pieceStart + int64(b)*meta.DefaultBlockSize
+			end := min(start+meta.DefaultBlockSize, pieceEnd)
+			for chunk := range info.FileChunks(start, end) {
+				if chunk.OffsetOfFile+chunk.Length > fileSize(chunk.FileIndex) {
+					return
+				}
+			}
+			blocks = append(blocks, b)
+		})
+		if len(blocks) == 0 || len(blocks) == int(n) {
+			continue
+		}
+		r = append(r, partialPiece{index: p.Index, blocks: blocks})
+	}
+	return r
+}
+
+// restorePartialPieces marks blocks written before a restart as done, so the
+// picker only requests the rest of their pieces.
+func (d *Download) restorePartialPieces(pieces []partialPiece) {
+	for _, p := range pieces {
+		start := p.index * d.normalChunkLen
+		for _, b := range p.blocks {
+			d.writtenBlocks.Set(start + b)
+		}
+	}
+	d.restoreWrittenBlocks()
+}
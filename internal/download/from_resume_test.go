@@ -195,3 +195,115 @@ func TestLoadFromResumeExcludesValidatedCompletedPieceFromPicker(t *testing.T) {
 		NumBlocks: d.info.PieceBlockCount(0),
 	}))
 }
+
+func TestLoadFromResumeRestoresPartialPieces(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	f.writeDataFile(t)
+	r := f.resumeData(t, store.ResumeActive)
+	blocks := bm.New(4)
+	blocks.Set(0)
+	blocks.Set(2)
+	r.PartialPieces = []store.PartialPiece{{Index: 1, Blocks: blocks.Bitfield()}}
+	d := f.load(t, r)
+
+	require.Equal(t, []uint32{1}, d.picker.Load().DownloadingPieces())
+	require.True(t, d.writtenBlocks.Contains(d.normalChunkLen))
+	require.False(t, d.writtenBlocks.Contains(d.normalChunkLen+1))
+	require.True(t, d.writtenBlocks.Contains(d.normalChunkLen+2))
+	require.Equal(t, r.PartialPieces, d.resumeRecord().PartialPieces)
+
+	peerPieces := bm.NewLockFreeBitmap(d.info.NumPieces)
+	peerPieces.Set(1)
+	claims := d.picker.Load().PickAndClaim(nil, PickRequest{
+		Bitfield:  peerPieces,
+		PeerID:    1,
+		NumBlocks: d.info.PieceBlockCount(1),
+	})
+	var picked []uint32
+	for _, c := range claims {
+		require.Equal(t, uint32(1), c.Block.PieceIndex)
+		picked = append(picked, c.Block.BlockIndex)
+	}
+	require.ElementsMatch(t, []uint32{1, 3}, picked)
+	for _, c := range claims {
+		d.picker.Load().ReleaseClaim(c)
+	}
+}
+
+func TestLoadFromResumeDropsPartialPiecesWithoutData(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	r := f.resumeData(t, store.ResumeActive)
+	blocks := bm.New(4)
+	blocks.Set(0)
+	r.PartialPieces = []store.PartialPiece{{Index: 1, Blocks: blocks.Bitfield()}}
+	d := f.load(t, r)
+
+	require.Empty(t, d.picker.Load().DownloadingPieces())
+	require.False(t, d.writtenBlocks.Contains(d.normalChunkLen))
+}
+
+func TestLoadFromResumeReverifiesChangedFiles(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	f.writeDataFile(t)
+	r := f.resumeData(t, store.ResumeActive, 0, 1)
+	r.FileStats = statFiles(f.info, f.basePath, nil)
+
+	// same size, different content in piece 1 and a new mtime
+	path := filepath.Join(f.basePath, f.info.Files[0].Path)
+	data := make([]byte, f.info.TotalLength)
+	data[f.info.PieceLength] = 1
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Hour)))
+
+	d := f.load(t, r)
+	require.Eventually(t, func() bool { return d.GetState() == Downloading }, 5*time.Second, 10*time.Millisecond)
+	require.True(t, d.completedBm.Contains(0))
+	require.False(t, d.completedBm.Contains(1))
+}
+
+func TestLoadFromResumeTrustsUnchangedFiles(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	f.writeDataFile(t)
+	path := filepath.Join(f.basePath, f.info.Files[0].Path)
+	data := make([]byte, f.info.TotalLength)
+	data[f.info.PieceLength] = 1
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+
+	r := f.resumeData(t, store.ResumeActive, 0, 1)
+	r.FileStats = statFiles(f.info, f.basePath, nil)
+	d := f.load(t, r)
+
+	require.Equal(t, Seeding, d.GetState())
+	require.Equal(t, uint32(2), d.completedBm.Count())
+}
+
+func TestStartReverifiesFilesChangedWhileStopped(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	f.writeDataFile(t)
+	r := f.resumeData(t, store.ResumeStopped, 0, 1)
+	r.FileStats = statFiles(f.info, f.basePath, nil)
+	d := f.load(t, r)
+	require.Equal(t, Stopped, d.GetState())
+
+	path := filepath.Join(f.basePath, f.info.Files[0].Path)
+	data := make([]byte, f.info.TotalLength)
+	data[0] = 1
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Hour)))
+
+	require.NoError(t, d.Start())
+	require.Eventually(t, func() bool { return d.GetState() == Downloading }, 5*time.Second, 10*time.Millisecond)
+	require.False(t, d.completedBm.Contains(0))
+	require.True(t, d.completedBm.Contains(1))
+}
+
+func TestLoadFromResumeKeepsSharedFiles(t *testing.T) {
+	f := newResumeTestFixture(t, 1)
+	f.writeDataFile(t)
+	r := f.resumeData(t, store.ResumeActive, 0)
+	r.SharedFiles = []int{0}
+	d := f.load(t, r)
+
+	require.True(t, d.sharedFiles.Contains(0))
+	require.Equal(t, []int{0}, d.resumeRecord().SharedFiles)
+}
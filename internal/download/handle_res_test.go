@@ -96,6 +96,8 @@ func newTestDownload(t testing.TB, numPieces uint32, blocksPerPiece uint32, newS
 	missingBm.Fill()
 	d.completedBm = completedBm
 	d.missingBm = missingBm
+	d.writtenBlocks = bm.NewNilSafeLockFreeBitmap(info.TotalBlockCount())
+	d.sharedFiles = bm.NewLockFreeBitmap(uint32(len(info.Files)))
 	d.wantedBm = wantedBm
 	d.peerList = newPeerList(d)
 	d.picker.Store(NewPiecePicker(info, missingBm, nil, nil, NewRequestGate(&d.state, uint32(Downloading))))
@@ -14,12 +14,21 @@ import (
 type TorrentInfo struct {
 	Custom               map[string]string
 	TrackerErrors        map[string]string
+	Move                 *MoveStatus
+	Allocating           *AllocationStatus
 	Name                 string
 	Hash                 string
 	Comment              string
+	CreatedBy            string
+	Encoding             string
+	Source               string
 	DownloadDir          string
+	CompletePath         string
 	ErrorMessage         string
 	Tags                 []string
+	WebSeeds             []string
+	HTTPSeeds            []string
+	Nodes                []string
 	UploadTotal          int64
 	AddedAt              int64
 	DownloadTotal        int64
@@ -31,14 +40,19 @@ type TorrentInfo struct {
 	SelectedSize         int64
 	DownloadRate         int64
 	CompletedAt          int64
+	CreationDate         int64
 	ConnectedSeeding     int
 	Corrupted            int64
 	WastedStale          int64
 	WastedDupe           int64
 	TotalSeeding         int
 	TotalDownloading     int
+	Scrub                ScrubStatus
+	UnverifiedPieces     uint32
 	Private              bool
+	DiskSpacePaused      bool
 	State                State
+	Allocation           Allocation
 }
 
 // Info returns a snapshot of the download's state for use by external callers.
@@ -65,7 +79,15 @@ func (d *Download) Info(keys []string) TorrentInfo {
 		Name:                 d.info.Name,
 		State:                State(d.state.Load()),
 		Comment:              d.info.Comment,
+		CreatedBy:            d.info.CreatedBy,
+		CreationDate:         d.info.CreationDate,
+		Encoding:             d.info.Encoding,
+		Source:               d.info.Source,
+		WebSeeds:             d.info.WebSeeds,
+		HTTPSeeds:            d.info.HTTPSeeds,
+		Nodes:                d.info.Nodes,
 		DownloadDir:          d.s.downloadDir,
+		CompletePath:         d.s.completePath,
 		ErrorMessage:         d.ErrorMsg(),
 		TrackerErrors:        d.trackerErrors(),
 		Tags:                 d.s.tags,
@@ -81,6 +103,7 @@ func (d *Download) Info(keys []string) TorrentInfo {
 		AddedAt:              d.AddAt.UnixMilli(),
 		CompletedAt:          d.completedAt.Load() / 1e9,
 		Private:              d.info.Private,
+		DiskSpacePaused:      d.diskSpacePaused.Load(),
 		Corrupted:            d.corrupted.Load(),
 		WastedStale:          d.wastedStale.Load(),
 		WastedDupe:           d.wastedDupe.Load(),
@@ -88,6 +111,11 @@ func (d *Download) Info(keys []string) TorrentInfo {
 		TotalDownloading:     totalDownloading,
 		ConnectedSeeding:     connectedSeeding,
 		ConnectedDownloading: connectedDownloading,
+		UnverifiedPieces:     d.seedMode.Load().count(),
+		Scrub:                d.scrubStatus(),
+		Move:                 d.moveStatus.Load(),
+		Allocation:           d.allocation,
+		Allocating:           d.allocStatus.Load(),
 	}
 }
 
@@ -100,9 +128,10 @@ type FileInfo struct {
 
 // Files returns file-level information for the torrent.
 func (d *Download) Files() []FileInfo {
-	results := make([]FileInfo, len(d.info.Files))
+	files := d.fileInfo().Files
+	results := make([]FileInfo, len(files))
 	var fileStart int64
-	for i, file := range d.info.Files {
+	for i, file := range files {
 		fileEnd := fileStart + file.Length
 		startIndex := as.Uint32(fileStart / d.info.PieceLength)
 		endIndex := as.Uint32((fileEnd + d.info.PieceLength - 1) / d.info.PieceLength)
@@ -5,22 +5,20 @@ package download
 
 import (
 	"context"
-	"crypto/sha1"
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
-	"github.com/docker/go-units"
 	"github.com/dustin/go-humanize"
-	"github.com/juju/ratelimit"
 	"github.com/trim21/errgo"
 
+	"neptune/internal/hashcheck"
 	"neptune/internal/meta"
+	"neptune/internal/piece_store"
 	"neptune/internal/pkg/bm"
-	"neptune/internal/pkg/fadvise"
-	"neptune/internal/pkg/global"
 )
 
 type existingFile struct {
@@ -30,15 +28,16 @@ type existingFile struct {
 
 // CheckExistingFiles pre-allocates files, hash-checks existing data, and
 // returns a bitmap of verified pieces. selected determines which files
-// are considered for the download.
-func CheckExistingFiles(ctx context.Context, info meta.Info, basePath string, selected *bm.Bitmap, fallocate bool) (*bm.Bitmap, error) {
+// are considered for the download. A non-nil only limits the check to those
+// pieces. check must already be admitted.
+func CheckExistingFiles(ctx context.Context, check *hashcheck.Check, info meta.Info, basePath string, parts *piece_store.PartFiles, selected *bm.Bitmap, fallocate bool, only *bm.Bitmap) (*bm.Bitmap, error) {
 	if err := os.MkdirAll(basePath, os.ModePerm); err != nil {
 		return nil, err
 	}
 
 	var efs = make(map[int]*existingFile, len(info.Files)+1)
 	for i, tf := range info.Files {
-		f, e := tryAllocFile(i, filepath.Join(basePath, tf.Path), tf.Length, fallocate, selected.Contains(uint32(i)))
+		f, e := tryAllocFile(i, parts.Path(i, tf.FullPath(basePath)), tf.Length, fallocate, selected.Contains(uint32(i)))
 		if e != nil {
 			return nil, e
 		}
@@ -48,98 +47,53 @@ func CheckExistingFiles(ctx context.Context, info meta.Info, basePath string, se
 	}
 
 	h := buildPieceToCheck(info, efs)
+	if only != nil {
+		h = slices.DeleteFunc(h, func(index uint32) bool { return !only.Contains(index) })
+	}
 	if len(h) == 0 {
 		return bm.New(info.NumPieces), nil
 	}
 
-	completedBm := bm.New(info.NumPieces)
-	sum := sha1.New()
-
-	var w io.Writer = sum
-
-	if global.Dev {
-		bucket := ratelimit.NewBucketWithQuantum(time.Second/10, units.MiB*100, units.MiB*100)
-		w = ratelimit.Writer(sum, bucket)
+	pieces := make([]hashcheck.Piece, len(h))
+	for i, pieceIndex := range h {
+		pieces[i] = hashcheck.Piece{Index: pieceIndex, Length: info.PieceLen(pieceIndex), Hash: info.Pieces[pieceIndex]}
 	}
 
-	var sha1Sum = make([]byte, sha1.Size)
-
-	var buf [256 * 1024]byte
-	var currentFile *os.File
-	var currentFileIndex = -1
-
-	defer func() {
-		if currentFile != nil {
-			currentFile.Close()
-		}
-	}()
-
-	for _, pieceIndex := range h {
-		if ctx.Err() != nil {
-			return nil, ctx.Err()
-		}
-
-		for chunk := range info.PieceFileChunks(pieceIndex) {
-			if chunk.FileIndex != currentFileIndex {
-				if currentFile != nil {
-					currentFile.Close()
-					currentFile = nil
-				}
-				p := filepath.Join(basePath, info.Files[chunk.FileIndex].Path)
-				f, err := os.OpenFile(p, os.O_RDONLY, 0)
-				if err != nil {
-					return nil, errgo.Wrap(err, fmt.Sprintf("failed to open file %q", p))
-				}
-				_ = fadvise.Sequential(f, 0, 0)
-				currentFile = f
-				currentFileIndex = chunk.FileIndex
-			}
+	return check.Run(ctx, info.NumPieces, pieces, func() hashcheck.Reader {
+		return hashcheck.NewFileReader(&info, func(index int) string {
+			return parts.Path(index, info.Files[index].FullPath(basePath))
+		}, nil)
+	})
+}
 
-			remaining := chunk.Length
-			off := chunk.OffsetOfFile
-			for remaining > 0 {
-				if err := ctx.Err(); err != nil {
-					return nil, err
-				}
-
-				toRead := min(remaining, int64(len(buf)))
-				n, err := currentFile.ReadAt(buf[:toRead], off)
-				if n > 0 {
-					if _, werr := w.Write(buf[:n]); werr != nil {
-						return nil, errgo.Wrap(werr, "failed to hash data")
-					}
-					off += int64(n)
-					remaining -= int64(n)
-				}
-				if err != nil {
-					if err == io.EOF && remaining == 0 {
-						break
-					}
-					return nil, errgo.Wrap(err, "failed to read file "+currentFile.Name())
-				}
-			}
-		}
-		sha1Sum = sum.Sum(sha1Sum[:0])
-		if [sha1.Size]byte(sha1Sum[:sha1.Size]) == info.Pieces[pieceIndex] {
-			completedBm.Set(pieceIndex)
-		}
+// initCheck waits for check to be admitted on the torrent's device, then
+// verifies existing data and merges it into completedBm. A nil pieces checks
+// the whole torrent.
+func (d *Download) initCheck(check *hashcheck.Check, pieces *bm.Bitmap) error {
+	defer check.Done()
 
-		sum.Reset()
+	if err := check.Wait(d.ctx); err != nil {
+		return err
+	}
+	// The mount may have gone away while the check was queued.
+	if err := d.checkMount(); err != nil {
+		return err
+	}
+	if err := d.allocateFiles(); err != nil {
+		return err
 	}
 
-	return completedBm, nil
-}
-
-func (d *Download) initCheck() error {
-	completedBm, err := CheckExistingFiles(d.ctx, d.info, d.s.basePath, d.selectedFilesSet, d.session.Config.App.Fallocate)
+	completedBm, err := CheckExistingFiles(d.ctx, check, d.info, d.BasePath(), d.parts, d.selectedFilesSet, d.allocation != AllocSparse, pieces)
 	if err != nil {
 		return err
 	}
 
 	// Merge verified pieces into the download's bitmap.
 	d.completedBm.OR(completedBm)
+	d.releaseSharedFiles()
 	d.setMissingFromWantedSync()
 	d.completed.Store(d.computeCompletedUnsafe())
+	d.syncPartFiles()
 
 	d.pieceDownloadRate.Reset()
 	donePieces := d.completedBm.WithAnd(d.wantedBm).Count()
@@ -215,13 +169,13 @@ func tryAllocFile(index int, path string, size int64, doAlloc bool, selected boo
 }
 
 // verifyFileSizesStandalone checks that all selected files exist with matching sizes.
-func verifyFileSizesStandalone(info meta.Info, basePath string, selected *bm.Bitmap) error {
+func verifyFileSizesStandalone(info meta.Info, basePath string, parts *piece_store.PartFiles, selected *bm.Bitmap) error {
 	for i, tf := range info.Files {
 		if !selected.Contains(uint32(i)) {
 			continue
 		}
 
-		p := filepath.Join(basePath, tf.Path)
+		p := parts.Path(i, tf.FullPath(basePath))
 		stat, err := os.Stat(p)
 		if err != nil {
 			if os.IsNotExist(err) {
@@ -240,7 +194,7 @@ func verifyFileSizesStandalone(info meta.Info, basePath string, selected *bm.Bit
 // verifyFileSizes checks that all selected files exist with matching sizes.
 // No SHA-1 piece verification is performed. Bitmap is not modified.
 func (d *Download) verifyFileSizes() error {
-	return verifyFileSizesStandalone(d.info, d.s.basePath, d.selectedFilesSet)
+	return verifyFileSizesStandalone(d.fileInfo(), d.BasePath(), d.parts, d.selectedFilesSet)
 }
 
 func (d *Download) checkNew(skipHashCheck bool) {
@@ -254,8 +208,29 @@ func (d *Download) checkNew(skipHashCheck bool) {
 		}
 		d.completedBm.Fill()
 		d.missingBm.Clear()
+		d.enterSeedMode(d.completedBm)
+		d.syncPartFiles()
 	} else {
-		if err := d.initCheck(); err != nil {
+		check, err := d.session.HashCheck.Enqueue(d.info.Hash, d.BasePath())
+		if err != nil {
+			d.setError(err)
+			d.log.Err(err).Msg("failed to queue initial hash check")
+			return
+		}
+		// Nothing is verified yet, canceling the initial check stops the
+		// download with an empty bitfield.
+		d.checkRestore.Store(&checkSnapshot{bitmap: bm.New(d.info.NumPieces), state: Stopped})
+		err = d.initCheck(check, nil)
+		d.checkRestore.Store(nil)
+		if errors.Is(err, hashcheck.ErrCanceled) {
+			d.completedBm.Clear()
+			d.setMissingFromWantedSync()
+			d.completed.Store(0)
+			d.finishCheck(Stopped)
+			d.log.Info().Msg("initial hash check canceled")
+			return
+		}
+		if err != nil {
 			d.setError(err)
 			d.log.Err(err).Msg("failed to initCheck torrent data")
 			return
@@ -41,7 +41,9 @@ func TestRunHashCheckFailureReleasesCompletedOnce(t *testing.T) {
 	// CheckExistingFiles (os.MkdirAll("")).
 	d.completedOnce.Store(true)
 
-	d.runHashCheck(nil)
+	check, err := d.session.HashCheck.Enqueue(d.info.Hash, d.s.basePath)
+	require.NoError(t, err)
+	d.runHashCheck(check, nil, nil)
 
 	require.Eventually(t, func() bool {
 		return !d.completedOnce.Load() && d.ErrorMsg() != ""
@@ -0,0 +1,141 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package download
+
+import (
+	"errors"
+	"path/filepath"
+
+	"neptune/internal/pkg/bm"
+)
+
+var (
+	errFilesMoving   = errors.New("torrent is being moved")
+	errFilesChecking = errors.New("torrent is being rechecked")
+)
+
+// lockFiles takes transitionMu, so no move or hash check starts while the
+// caller changes where the files are. It fails while one is running, as it
+// owns the files until it finishes.
+func (d *Download) lockFiles() (State, error) {
+	d.transitionMu.Lock()
+	state := State(d.state.Load())
+	switch state {
+	case Moving:
+		d.transitionMu.Unlock()
+		return state, errFilesMoving
+	case Checking:
+		d.transitionMu.Unlock()
+		return state, errFilesChecking
+	}
+	return state, nil
+}
+
+// SetLocation points the torrent at data the user already moved to basePath
+// itself. No file is touched. With recheck, or when the torrent is in the
+// Error state, the data is hash checked again. Otherwise only file sizes are
+// checked, and completed pieces whose data is missing or truncated at the new
+// location are downloaded again.
+func (d *Download) SetLocation(basePath string, recheck bool) error {
+	basePath, err := filepath.Abs(basePath)
+	if err != nil {
+		return err
+	}
+
+	state, err := d.lockFiles()
+	if err != nil {
+		return err
+	}
+
+	d.store.Relocate(basePath)
+	d.dropPartialPieces()
+	d.s.mu.Lock()
+	d.s.basePath = basePath
+	d.s.downloadDir = basePath
+	d.setMountPointUnsafe(basePath)
+	d.s.mu.Unlock()
+	d.indexPaths()
+	d.log.Info().Str("base_path", basePath).Msg("location changed")
+
+	if recheck || state == Error {
+		d.transitionMu.Unlock()
+		return d.AsyncCheck()
+	}
+
+	completed := d.completedBm.Clone()
+	_, err = validateResumeBitfield(d.info, basePath, d.parts, d.selectedFilesSet, completed)
+	if err == nil {
+		d.dropCompletedPieces(d.completedBm.WithAndNot(completed))
+		d.syncPartFiles()
+	}
+	d.transitionMu.Unlock()
+
+	d.saveResume()
+	return err
+}
+
+// dropPartialPieces forgets the blocks written for pieces that are not
+// complete yet. They were written at the old location, so the rest of such a
+// piece is downloaded again at the new one. Caller must hold transitionMu.
+func (d *Download) dropPartialPieces() {
+	picker := d.picker.Load()
+	pieces := bm.New(d.info.NumPieces)
+	for _, index := range picker.DownloadingPieces() {
+		pieces.Set(index)
+	}
+	d.missingBm.Range(func(index uint32) {
+		start := index * d.normalChunkLen
+		for i := range uint32(d.info.PieceBlockCount(index)) {
+			if d.writtenBlocks.Contains(start + i) {
+				pieces.Set(index)
+				return
+			}
+		}
+	})
+	pieces.AndNot(d.completedBm)
+
+	pieces.Range(func(index uint32) {
+		start := index * d.normalChunkLen
+		for i := range uint32(d.info.PieceBlockCount(index)) {
+			d.writtenBlocks.Unset(start + i)
+		}
+		picker.ResetPiece(index)
+	})
+}
+
+// dropCompletedPieces unmarks completed pieces whose data is gone, so the
+// wanted ones are downloaded again. A seeding torrent goes back to
+// downloading. Caller must hold transitionMu.
+func (d *Download) dropCompletedPieces(pieces *bm.Bitmap) {
+	pieces = pieces.WithAnd(d.completedBm)
+	if pieces.Count() == 0 {
+		return
+	}
+
+	d.completedBm.AndNot(pieces)
+	d.setMissingFromWantedSync()
+	d.completed.Store(d.computeCompletedUnsafe())
+	d.syncPartFiles()
+
+	lost := pieces.WithAnd(d.wantedBm)
+	if lost.Count() == 0 {
+		return
+	}
+	d.completedOnce.Store(false)
+	if picker := d.picker.Load(); picker != nil {
+		lost.Range(picker.ResetPiece)
+	} else {
+		d.initializePiecePicker()
+	}
+
+	// validTransition has no Seeding to Downloading: only completed data that
+	// turns out to be gone makes a seeding torrent incomplete.
+	if State(d.state.Load()) == Seeding {
+		d.commitStateTransition(Seeding, Downloading)
+	}
+
+	d.stateCond.Broadcast()
+	d.signalConnect()
+	d.notifyPeersToRequest()
+}
@@ -0,0 +1,100 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package download
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/pkg/bm"
+	"neptune/internal/session/store"
+)
+
+func TestSetLocationDropsPiecesMissingAtNewLocation(t *testing.T) {
+	f := newResumeTestFixture(t, 3)
+	f.writeDataFile(t)
+	d := f.load(t, f.resumeData(t, store.ResumeActive, 0, 1, 2))
+	require.Equal(t, Seeding, d.GetState())
+
+	// The copy is short: piece 1 is truncated and piece 2 is missing. Piece
+	// 0 does not match its hash, which only shows once it is read again.
+	target := filepath.Join(t.TempDir(), "moved")
+	data := make([]byte, f.info.PieceLength*3/2)
+	data[0] = 1
+	require.NoError(t, os.MkdirAll(target, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(target, f.info.Files[0].Path), data, 0o644))
+
+	require.NoError(t, d.SetLocation(target, false))
+	require.Equal(t, target, d.BasePath())
+	require.Equal(t, []uint32{0}, d.completedBm.ToArray())
+	require.Equal(t, []uint32{1, 2}, d.missingBm.ToArray())
+	require.Equal(t, Downloading, d.GetState())
+	require.NotNil(t, d.picker.Load())
+	require.Equal(t, target, d.resumeRecord().BasePath)
+
+	ok, err := d.store.VerifyPiece(context.Background(), 0, d.info.Pieces[0])
+	require.NoError(t, err)
+	require.False(t, ok, "the store reads the file at the new location")
+}
+
+func TestSetLocationDropsPartialPieces(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	f.writeDataFile(t)
+	r := f.resumeData(t, store.ResumeActive, 0)
+	blocks := bm.New(4)
+	blocks.Set(0)
+	blocks.Set(2)
+	r.PartialPieces = []store.PartialPiece{{Index: 1, Blocks: blocks.Bitfield()}}
+	d := f.load(t, r)
+	require.True(t, d.writtenBlocks.Contains(d.normalChunkLen))
+
+	// The blocks of piece 1 were written at the old location, the whole
+	// piece is downloaded again at the new one.
+	target := filepath.Join(t.TempDir(), "moved")
+	require.NoError(t, os.MkdirAll(target, 0o755))
+	data, err := os.ReadFile(filepath.Join(f.basePath, f.info.Files[0].Path))
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(target, f.info.Files[0].Path), data[:f.info.PieceLength], 0o644))
+
+	require.NoError(t, d.SetLocation(target, false))
+	require.False(t, d.writtenBlocks.Contains(d.normalChunkLen))
+	require.False(t, d.writtenBlocks.Contains(d.normalChunkLen+2))
+	require.Empty(t, d.resumeRecord().PartialPieces)
+
+	peerPieces := bm.NewLockFreeBitmap(d.info.NumPieces)
+	peerPieces.Set(1)
+	claims := d.picker.Load().PickAndClaim(nil, PickRequest{
+		Bitfield:  peerPieces,
+		PeerID:    1,
+		NumBlocks: d.info.PieceBlockCount(1),
+	})
+	var picked []uint32
+	for _, c := range claims {
+		picked = append(picked, c.Block.BlockIndex)
+		d.picker.Load().ReleaseClaim(c)
+	}
+	require.ElementsMatch(t, []uint32{0, 1, 2, 3}, picked)
+}
+
+func TestSetLocationRefusedWhileMovingOrChecking(t *testing.T) {
+	f := newResumeTestFixture(t, 1)
+	f.writeDataFile(t)
+	d := f.load(t, f.resumeData(t, store.ResumeStopped, 0))
+
+	for state, want := range map[State]error{
+		Moving:   errFilesMoving,
+		Checking: errFilesChecking,
+	} {
+		d.state.Store(uint32(state))
+		require.ErrorIs(t, d.SetLocation(t.TempDir(), false), want)
+	}
+	d.state.Store(uint32(Stopped))
+	require.Equal(t, f.basePath, d.BasePath())
+}
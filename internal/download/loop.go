@@ -8,11 +8,22 @@ import (
 )
 
 func (d *Download) Start() error {
+	if err := d.checkMount(); err != nil {
+		return err
+	}
+
+	// Pieces of files changed while stopped are re-verified first; the check
+	// ends in Downloading or Seeding like a manual recheck.
+	if d.HasState(Stopped) && d.verifyChangedPieces(d.changedPieces()) {
+		return nil
+	}
+
 	if d.isComplete() {
 		if _, err := d.transition(Seeding); err != nil {
 			d.log.Error().Err(err).Msg("failed to transition state in Start")
 			return err
 		}
+		d.retryCompleteMove()
 	} else {
 		transition, err := d.transition(Downloading)
 		if err != nil {
@@ -39,6 +50,8 @@ func (d *Download) Stop() error {
 		return err
 	}
 	d.CancelMove()
+	d.diskSpacePaused.Store(false)
+	d.refreshFileStats()
 
 	d.stateCond.Broadcast()
 	return nil
@@ -76,8 +89,49 @@ func (d *Download) PromoteFromQueued() {
 	d.signalConnect()
 }
 
+// PauseForDiskSpace queues a downloading torrent while the filesystem holding
+// its data is low on free space. The queue manager does not promote it until
+// ResumeForDiskSpace. It reports whether the torrent was not paused yet.
+func (d *Download) PauseForDiskSpace() bool {
+	if !d.IsDownloading() || d.diskSpacePaused.Swap(true) {
+		return false
+	}
+	if d.HasState(Downloading) {
+		d.DemoteToQueued()
+	}
+	return true
+}
+
+// ResumeForDiskSpace undoes PauseForDiskSpace once free space is back. It
+// reports whether the torrent was paused.
+func (d *Download) ResumeForDiskSpace() bool {
+	if !d.diskSpacePaused.Swap(false) {
+		return false
+	}
+	if d.HasState(PendingDownloading) {
+		d.PromoteFromQueued()
+	}
+	return true
+}
+
+// DiskSpacePaused reports whether the torrent is queued for low disk space.
+func (d *Download) DiskSpacePaused() bool {
+	return d.diskSpacePaused.Load()
+}
+
 func (d *Download) AsyncCheck() error {
-	if _, err := d.transition(Checking); err != nil {
+	// Checking an empty mount point would drop every completed piece.
+	if err := d.checkMount(); err != nil {
+		return err
+	}
+
+	transition, err := d.transition(Checking)
+	if err != nil {
+		return err
+	}
+
+	check, err := d.queueCheck(transition.from, nil)
+	if err != nil {
 		return err
 	}
 
@@ -86,7 +140,7 @@ func (d *Download) AsyncCheck() error {
 	d.completed.Store(0)
 	d.stateCond.Broadcast()
 
-	d.runHashCheck(nil)
+	d.runHashCheck(check, nil, nil)
 
 	return nil
 }
@@ -117,7 +171,14 @@ func (d *Download) startBackground() {
 	d.goBackground(d.connectLoop)
 	d.goBackground(d.backgroundResHandler)
 	d.goBackground(d.backgroundReqHandler)
+	d.goBackground(d.verifySeedModeLoop)
+	d.goBackground(d.missingDataLoop)
 	d.startPeerIntake()
+	// the nodes are only hints, they are looked up once per download rather
+	// than on every start
+	if !d.private && d.session.DHT != nil && len(d.info.Nodes) != 0 && d.dhtNodesAdded.CompareAndSwap(false, true) {
+		d.goBackground(d.addDHTNodes)
+	}
 
 	// Background housekeeping loop: unchoke recalculation, optimistic unchoke
 	// and peer turnover. Peer connection dispatch runs in its own connectLoop.
@@ -32,6 +32,15 @@ func (d *Download) RemoveTag(tag string) {
 	d.saveResume()
 }
 
+// HasAnyTag reports whether the download has one of tags.
+func (d *Download) HasAnyTag(tags []string) bool {
+	d.s.mu.RLock()
+	defer d.s.mu.RUnlock()
+	return slices.ContainsFunc(d.s.tags, func(tag string) bool {
+		return slices.Contains(tags, tag)
+	})
+}
+
 // SetCustom sets a custom key-value pair and persists.
 func (d *Download) SetCustom(key, value string) {
 	d.s.mu.Lock()
@@ -107,6 +116,11 @@ func (d *Download) InfoHash() metainfo.Hash {
 	return d.info.Hash
 }
 
+// Name returns the torrent name.
+func (d *Download) Name() string {
+	return d.info.Name
+}
+
 // BasePath returns the base filesystem path where data is stored.
 func (d *Download) BasePath() string {
 	d.s.mu.RLock()
@@ -114,11 +128,16 @@ func (d *Download) BasePath() string {
 	return d.s.basePath
 }
 
-// DataFiles returns file paths relative to BasePath for cleanup operations.
+// DataFiles returns file paths relative to BasePath for cleanup operations,
+// with the part suffix of files that are not complete yet. Files remapped to
+// an absolute path belong to the user and are left out.
 func (d *Download) DataFiles() []string {
-	result := make([]string, len(d.info.Files))
-	for i, f := range d.info.Files {
-		result[i] = f.Path
+	files := d.fileInfo().Files
+	result := make([]string, 0, len(files))
+	for i, f := range files {
+		if !filepath.IsAbs(f.Path) {
+			result = append(result, d.parts.Path(i, f.Path))
+		}
 	}
 	return result
 }
@@ -0,0 +1,112 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package download
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"time"
+
+	"neptune/internal/config"
+	"neptune/internal/pkg/bm"
+	"neptune/internal/pkg/global/tasks"
+)
+
+// missingDataCheckInterval is how often the files of a running download are
+// checked for data deleted or truncated behind our back.
+const missingDataCheckInterval = 5 * time.Minute
+
+// errPieceDataMissing is returned for a read of a completed piece whose file
+// is missing or too short.
+var errPieceDataMissing = errors.New("piece data is missing on disk")
+
+// isMissingData reports whether a read failed because the file is gone or
+// shorter than the torrent says.
+func isMissingData(err error) bool {
+	return errors.Is(err, fs.ErrNotExist) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// signalMissingData asks missingDataLoop to check the files now.
+func (d *Download) signalMissingData() {
+	select {
+	case d.missingDataSignal <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Download) missingDataLoop() {
+	ticker := time.NewTicker(missingDataCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-ticker.C:
+		case <-d.missingDataSignal:
+		}
+		if d.IsAlive() {
+			d.checkMissingData()
+		}
+	}
+}
+
+// checkMissingData unmarks the completed pieces whose files are missing or
+// truncated, so they are no longer advertised to peers. It returns the lost
+// pieces, or nil when all data is still there.
+func (d *Download) checkMissingData() *bm.Bitmap {
+	// Every file looks missing where a missing mount used to be, that is
+	// not data loss.
+	if err := d.checkMount(); err != nil {
+		d.setError(err)
+		return nil
+	}
+
+	state, err := d.lockFiles()
+	if err != nil {
+		return nil
+	}
+	if state != Downloading && state != Seeding && state != PendingDownloading {
+		d.transitionMu.Unlock()
+		return nil
+	}
+
+	completed := d.completedBm.Clone()
+	if _, err := validateResumeBitfield(d.info, d.BasePath(), d.parts, d.selectedFilesSet, completed); err != nil {
+		d.transitionMu.Unlock()
+		d.log.Err(err).Msg("failed to check for missing data")
+		return nil
+	}
+	lost := d.completedBm.WithAndNot(completed)
+	if lost.Count() == 0 {
+		d.transitionMu.Unlock()
+		return nil
+	}
+	d.dropCompletedPieces(lost)
+	d.transitionMu.Unlock()
+
+	d.log.Warn().Uint32("pieces", lost.Count()).Msg("data of completed pieces is missing or truncated")
+	d.dontHave(lost)
+
+	policy, _ := config.ParseMissingDataPolicy(d.session.Config.App.MissingData)
+	if policy == config.MissingDataError {
+		d.setError(fmt.Errorf("data of %d completed pieces is missing or truncated on disk, restore it and recheck the torrent", lost.Count()))
+	}
+	d.saveResume()
+	return lost
+}
+
+// dontHave tells the peers that support lt_donthave we lost pieces.
+func (d *Download) dontHave(pieces *bm.Bitmap) {
+	tasks.SubmitNet(func() {
+		d.peerList.Range(func(_ uint64, p Peer) bool {
+			if !p.Closed() {
+				pieces.Range(p.DontHave)
+			}
+			return true
+		})
+	})
+}
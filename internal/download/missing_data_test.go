@@ -0,0 +1,92 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package download
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/proto"
+	"neptune/internal/session/store"
+)
+
+func TestCheckMissingDataRedownloadsLostPieces(t *testing.T) {
+	f := newResumeTestFixture(t, 3)
+	f.writeDataFile(t)
+	d := f.load(t, f.resumeData(t, store.ResumeActive, 0, 1, 2))
+	require.Equal(t, Seeding, d.GetState())
+
+	peer := newMockPeer()
+	require.True(t, d.peerList.Register(peer))
+
+	// piece 1 is truncated and piece 2 is gone
+	path := filepath.Join(f.basePath, f.info.Files[0].Path)
+	require.NoError(t, os.Truncate(path, f.info.PieceLength*3/2))
+
+	lost := d.checkMissingData()
+	require.Equal(t, []uint32{1, 2}, lost.ToArray())
+	require.Equal(t, []uint32{0}, d.completedBm.ToArray())
+	require.Equal(t, Downloading, d.GetState())
+	require.Equal(t, d.completedBm.Bitfield(), d.resumeRecord().Bitfield)
+
+	require.Eventually(t, func() bool {
+		peer.mu.Lock()
+		defer peer.mu.Unlock()
+		return slices.Equal(peer.dontHave, []uint32{1, 2})
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Nil(t, d.checkMissingData(), "nothing else is lost")
+}
+
+func TestCheckMissingDataErrorPolicy(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	f.sess.Config.App.MissingData = "error"
+	f.writeDataFile(t)
+	d := f.load(t, f.resumeData(t, store.ResumeActive, 0, 1))
+	require.Equal(t, Seeding, d.GetState())
+
+	require.NoError(t, os.Remove(filepath.Join(f.basePath, f.info.Files[0].Path)))
+	require.NotNil(t, d.checkMissingData())
+	require.Equal(t, Error, d.GetState())
+	require.ErrorContains(t, *d.err.Load(), "missing or truncated")
+	require.Zero(t, d.completedBm.Count())
+}
+
+func TestReadOfMissingFileDropsPieces(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	f.writeDataFile(t)
+	d := f.load(t, f.resumeData(t, store.ResumeActive, 0, 1))
+	require.Equal(t, Seeding, d.GetState())
+
+	require.NoError(t, os.Remove(filepath.Join(f.basePath, f.info.Files[0].Path)))
+	dst := make([]byte, defaultBlockSize)
+	err := d.readPieceRangeCtx(d.ctx, proto.ChunkRequest{PieceIndex: 0, Length: defaultBlockSize}, dst)
+	require.ErrorIs(t, err, errPieceDataMissing)
+
+	require.Eventually(t, func() bool { return d.GetState() == Downloading }, 5*time.Second, 10*time.Millisecond)
+	require.Zero(t, d.completedBm.Count())
+}
+
+func TestCheckMissingDataKeepsPiecesWhenMountMissing(t *testing.T) {
+	f := newResumeTestFixture(t, 1)
+	f.writeDataFile(t)
+	d := f.load(t, f.resumeData(t, store.ResumeActive, 0))
+	require.Equal(t, Seeding, d.GetState())
+
+	d.s.mu.Lock()
+	d.s.mountPoint = missingMount(t, f.basePath)
+	d.s.mu.Unlock()
+	require.NoError(t, os.Remove(filepath.Join(f.basePath, f.info.Files[0].Path)))
+
+	require.Nil(t, d.checkMissingData())
+	require.True(t, d.MountMissing())
+	require.True(t, d.completedBm.Contains(0))
+}
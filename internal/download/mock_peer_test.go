@@ -52,6 +52,7 @@ type mockPeer struct {
 	queued                 []BlockClaim
 	enqueuedBlocks         []PieceBlock
 	requestsSent           []proto.ChunkRequest
+	dontHave               []uint32
 	info                   meta.Info
 	uploadRate             flowrate.Monitor
 	downloadRate           flowrate.Monitor
@@ -389,6 +390,12 @@ func (m *mockPeer) SendChoke()        {}
 func (m *mockPeer) SendUnchoke()      {}
 func (m *mockPeer) Have(index uint32) {}
 
+func (m *mockPeer) DontHave(index uint32) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.dontHave = append(m.dontHave, index)
+}
+
 // ── Transfer tracking ───────────────────────────────────────────────
 
 func (m *mockPeer) HadTransfer() bool { return m.hadTrans }
@@ -0,0 +1,72 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package download
+
+import (
+	"errors"
+
+	"neptune/internal/piece_store"
+	"neptune/internal/pkg/disk_io"
+)
+
+// checkMount returns a *piece_store.MountError if the base path is no longer
+// on the mount its data was saved on.
+func (d *Download) checkMount() error {
+	d.s.mu.RLock()
+	basePath, mount := d.s.basePath, d.s.mountPoint
+	d.s.mu.RUnlock()
+	return piece_store.CheckMount(basePath, mount)
+}
+
+// setMountPointUnsafe records the mount basePath is on now, for a base path the
+// user chose. Caller must hold s.mu.
+func (d *Download) setMountPointUnsafe(basePath string) {
+	d.s.mountPoint = disk_io.MountPointOf(basePath)
+	d.store.SetMountPoint(d.s.mountPoint)
+}
+
+// MountMissing returns true when the download is in the Error state because
+// its mount is missing.
+func (d *Download) MountMissing() bool {
+	if d.GetState() != Error {
+		return false
+	}
+	err := d.err.Load()
+	var mountErr *piece_store.MountError
+	return err != nil && errors.As(*err, &mountErr)
+}
+
+// RecoverMount re-verifies a download that failed for a missing mount once
+// the mount is back. Only pieces whose files are missing, truncated or
+// changed since the last save are checked again; the check ends in
+// Downloading or Seeding. It returns true if the recovery started.
+func (d *Download) RecoverMount() bool {
+	if !d.MountMissing() || d.checkMount() != nil {
+		return false
+	}
+
+	d.s.mu.RLock()
+	basePath, mount := d.s.basePath, d.s.mountPoint
+	d.s.mu.RUnlock()
+
+	// Drop files opened on the wrong filesystem and find the names the
+	// files have on the mount.
+	d.store.Relocate(basePath)
+	d.store.SetMountPoint(mount)
+
+	completed := d.completedBm.Clone()
+	if _, err := validateResumeBitfield(d.fileInfo(), basePath, d.parts, d.selectedFilesSet, completed); err != nil {
+		d.log.Err(err).Msg("failed to check files after mount came back")
+		return false
+	}
+	pieces := d.completedBm.WithAndNot(completed)
+	pieces.OR(d.changedPieces())
+
+	d.log.Info().Str("mount_point", mount).Uint32("pieces", pieces.Count()).Msg("mount is back, re-verifying changed pieces")
+	if err := d.asyncCheckPieces(pieces); err != nil {
+		d.log.Err(err).Msg("failed to recover after mount came back")
+		return false
+	}
+	return true
+}
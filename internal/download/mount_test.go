@@ -0,0 +1,98 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package download
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/piece_store"
+	"neptune/internal/pkg/disk_io"
+	"neptune/internal/session/store"
+)
+
+// missingMount returns a mount point basePath is not on, as if the disk
+// holding it was not mounted.
+func missingMount(t *testing.T, basePath string) string {
+	t.Helper()
+	if disk_io.MountPointOf(basePath) == "" {
+		t.Skip("mounts are not detected on this system")
+	}
+	return filepath.Join(basePath, "missing-disk")
+}
+
+func TestLoadFromResumeKeepsBitfieldWhenMountMissing(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	r := f.resumeData(t, store.ResumeActive, 0, 1)
+	r.MountPoint = missingMount(t, f.basePath)
+	r.FileStats = []store.FileStat{{Size: f.info.TotalLength, ModTime: 1}}
+	d := f.load(t, r)
+
+	require.Equal(t, Error, d.GetState())
+	require.True(t, d.MountMissing())
+	require.Equal(t, uint32(2), d.completedBm.Count(), "the data is on the missing disk")
+	var mountErr *piece_store.MountError
+	require.True(t, errors.As(*d.err.Load(), &mountErr))
+
+	saved := d.resumeRecord()
+	require.Equal(t, r.MountPoint, saved.MountPoint)
+	require.Equal(t, r.FileStats, saved.FileStats)
+	require.Equal(t, r.Bitfield, saved.Bitfield)
+	require.Equal(t, store.ResumeActive, saved.State)
+
+	require.ErrorAs(t, d.AsyncCheck(), &mountErr)
+	require.False(t, d.RecoverMount(), "the mount is still missing")
+	require.Equal(t, Error, d.GetState())
+}
+
+func TestStartRefusedWhenMountMissing(t *testing.T) {
+	f := newResumeTestFixture(t, 1)
+	r := f.resumeData(t, store.ResumeStopped, 0)
+	r.MountPoint = missingMount(t, f.basePath)
+	d := f.load(t, r)
+	require.Equal(t, Stopped, d.GetState())
+
+	var mountErr *piece_store.MountError
+	require.ErrorAs(t, d.Start(), &mountErr)
+	require.Equal(t, Stopped, d.GetState())
+	require.True(t, d.completedBm.Contains(0))
+}
+
+func TestRecoverMount(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	f.writeDataFile(t)
+	r := f.resumeData(t, store.ResumeActive, 0, 1)
+	r.FileStats = statFiles(f.info, f.basePath, nil)
+	r.MountPoint = missingMount(t, f.basePath)
+	d := f.load(t, r)
+	require.Equal(t, Error, d.GetState())
+
+	// the disk is mounted again
+	d.s.mu.Lock()
+	d.s.mountPoint = disk_io.MountPointOf(f.basePath)
+	d.s.mu.Unlock()
+
+	require.True(t, d.RecoverMount())
+	require.Eventually(t, func() bool { return d.GetState() == Seeding }, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, uint32(2), d.completedBm.Count())
+	require.False(t, d.MountMissing())
+}
+
+func TestSetLocationRecordsMount(t *testing.T) {
+	f := newResumeTestFixture(t, 1)
+	r := f.resumeData(t, store.ResumeStopped)
+	r.MountPoint = missingMount(t, f.basePath)
+	d := f.load(t, r)
+
+	target := t.TempDir()
+	require.NoError(t, d.SetLocation(target, false))
+	require.Equal(t, disk_io.MountPointOf(target), d.resumeRecord().MountPoint)
+	require.NoError(t, d.Start())
+}
@@ -11,20 +11,79 @@ import (
 	"path/filepath"
 
 	"neptune/internal/piece_store"
+	"neptune/internal/session/store"
 )
 
+// MoveStatus is the progress of the current data move, or the result of the
+// last one if it failed.
+type MoveStatus struct {
+	Target     string
+	Error      string
+	BytesDone  int64
+	BytesTotal int64
+	FilesDone  int
+	FilesTotal int
+	Phase      piece_store.MovePhase
+}
+
+// pendingMove is a move that left its original state but did not touch any
+// data yet.
+type pendingMove struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	target string
+	from   State
+}
+
+// RequestMove moves the torrent data to target and returns when it is done.
 func (d *Download) RequestMove(target string) error {
-	transition, err := d.transition(Moving)
+	m, err := d.beginMove(target)
+	if err != nil {
+		return err
+	}
+	return d.runMove(m)
+}
+
+// StartMove moves the torrent data to target in the background. Only errors
+// that keep the move from starting are returned, the result is reported by
+// MoveStatus. done, if not nil, is called once the move ended or failed to
+// start.
+func (d *Download) StartMove(target string, done func()) error {
+	if done == nil {
+		done = func() {}
+	}
+	m, err := d.beginMove(target)
 	if err != nil {
+		done()
 		return err
 	}
+	go func() {
+		defer done()
+		if err := d.runMove(m); err != nil && !errors.Is(err, context.Canceled) {
+			d.log.Err(err).Str("target", m.target).Msg("failed to move torrent data")
+		}
+	}()
+	return nil
+}
+
+// MoveStatus returns the progress of the current move, nil when no move is
+// running and the last one did not fail.
+func (d *Download) MoveStatus() *MoveStatus {
+	return d.moveStatus.Load()
+}
+
+func (d *Download) beginMove(target string) (*pendingMove, error) {
+	transition, err := d.transition(Moving)
+	if err != nil {
+		return nil, err
+	}
 	originalState := transition.from
 	d.stateCond.Broadcast()
 
 	target, err = filepath.Abs(target)
 	if err != nil {
 		d.finishMove(originalState)
-		return err
+		return nil, err
 	}
 
 	ctx, cancel := context.WithCancel(d.ctx)
@@ -35,18 +94,27 @@ func (d *Download) RequestMove(target string) error {
 	if d.GetState() != Moving {
 		cancel()
 	}
+	d.moveStatus.Store(&MoveStatus{Target: target, Phase: piece_store.MoveWaiting})
+	return &pendingMove{ctx: ctx, cancel: cancel, target: target, from: originalState}, nil
+}
+
+func (d *Download) runMove(m *pendingMove) (err error) {
 	finished := false
 	defer func() {
-		cancel()
+		m.cancel()
 		d.pieceDownloadRate.Reset()
 		d.moveCancelMu.Lock()
 		d.moveCancel = nil
 		d.moveCancelMu.Unlock()
 		if !finished {
-			d.finishMove(originalState)
+			d.finishMove(m.from)
 		}
+		d.finishMoveStatus(err)
 	}()
 
+	journal := d.journalMove(m.target)
+	defer d.deleteMoveJournal(journal)
+
 	var copiedBytes int64
 	report := func(progress piece_store.MoveProgress) {
 		delta := progress.BytesCopied - copiedBytes
@@ -54,22 +122,96 @@ func (d *Download) RequestMove(target string) error {
 		if delta > 0 {
 			d.pieceDownloadRate.Update(int(delta))
 		}
+		d.moveStatus.Store(&MoveStatus{
+			Target:     m.target,
+			Phase:      progress.Phase,
+			BytesDone:  progress.BytesDone,
+			BytesTotal: progress.BytesTotal,
+			FilesDone:  progress.FilesDone,
+			FilesTotal: progress.FilesTotal,
+		})
+		if progress.Phase == piece_store.MoveCleaning {
+			d.commitMoveJournal(journal)
+		}
 	}
-	if err := d.store.Move(ctx, target, report); err != nil {
+	if err := d.store.Move(m.ctx, m.target, report); err != nil {
 		return err
 	}
 
 	d.s.mu.Lock()
-	d.s.basePath = target
-	d.s.downloadDir = target
+	d.s.basePath = m.target
+	d.s.downloadDir = m.target
+	d.setMountPointUnsafe(m.target)
+	// A move to the complete path, by the user or on completion, fulfills it.
+	if completePathPending(m.target, d.s.completePath) == "" {
+		d.s.completePath = ""
+	}
 	d.s.mu.Unlock()
-	d.finishMove(originalState)
+	d.indexPaths()
+	d.finishMove(m.from)
 	finished = true
 	d.saveResume()
 
 	return nil
 }
 
+// finishMoveStatus clears the move status, or keeps it with the error of a
+// failed move. A canceled move is not a failure.
+func (d *Download) finishMoveStatus(err error) {
+	status := d.moveStatus.Load()
+	if err == nil || errors.Is(err, context.Canceled) || status == nil {
+		d.moveStatus.Store(nil)
+		return
+	}
+	failed := *status
+	failed.Error = err.Error()
+	d.moveStatus.Store(&failed)
+}
+
+// journalMove records a move to target in the session store before any data
+// is touched. It returns nil when there is nothing to journal.
+func (d *Download) journalMove(target string) *store.MoveJournal {
+	if d.session.Store == nil {
+		return nil
+	}
+	source, err := filepath.Abs(d.BasePath())
+	if err != nil || source == target {
+		return nil
+	}
+
+	journal := &store.MoveJournal{
+		InfoHash: d.info.Hash.Hex(),
+		Source:   source,
+		Target:   target,
+		Files:    d.DataFiles(),
+	}
+	if err := d.session.Store.SaveMove(journal); err != nil {
+		d.log.Err(err).Msg("failed to journal move")
+	}
+	return journal
+}
+
+// commitMoveJournal records that every file reached the target, so a crash
+// from here on finishes the move instead of rolling it back.
+func (d *Download) commitMoveJournal(journal *store.MoveJournal) {
+	if journal == nil || journal.Committed {
+		return
+	}
+	journal.Committed = true
+	if err := d.session.Store.SaveMove(journal); err != nil {
+		d.log.Err(err).Msg("failed to journal move commit")
+	}
+}
+
+func (d *Download) deleteMoveJournal(journal *store.MoveJournal) {
+	if journal == nil {
+		return
+	}
+	if err := d.session.Store.DeleteMove(journal.InfoHash); err != nil {
+		d.log.Err(err).Msg("failed to delete move journal")
+	}
+}
+
 func (d *Download) CancelMove() {
 	d.moveCancelMu.RLock()
 	cancel := d.moveCancel
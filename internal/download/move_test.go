@@ -11,11 +11,14 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"neptune/internal/meta"
 	"neptune/internal/piece_store"
+	"neptune/internal/pkg/bm"
+	"neptune/internal/session/store"
 )
 
 var errMoveTest = errors.New("move failed")
@@ -69,6 +72,93 @@ func TestStopCancelsMoveAndKeepsStoppedState(t *testing.T) {
 	require.Equal(t, Stopped, d.GetState())
 }
 
+// withTestSessionStore gives a test download a session store and the state a
+// resume save needs.
+func withTestSessionStore(t *testing.T, d *Download) *store.Store {
+	t.Helper()
+	st, err := store.Open(t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = st.Close() })
+	d.session.Store = st
+	d.selectedFilesSet = bm.New(uint32(len(d.info.Files)))
+	d.selectedFilesSet.Fill()
+	d.s.basePath = t.TempDir()
+	return st
+}
+
+func TestStartMoveReportsProgressAndFailure(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	d := newTestDownload(t, 1, 1, func(info meta.Info) piece_store.PieceStore {
+		return &moveTestStore{
+			Store: piece_store.NewMemStore(info),
+			move: func(_ context.Context, _ string, report piece_store.MoveProgressFunc) error {
+				report(piece_store.MoveProgress{Phase: piece_store.MoveCopying, BytesDone: 1, BytesTotal: 2})
+				close(started)
+				<-release
+				return errMoveTest
+			},
+		}
+	})
+	st := withTestSessionStore(t, d)
+	target := t.TempDir()
+
+	require.NoError(t, d.StartMove(target, nil))
+	<-started
+	status := d.MoveStatus()
+	require.Equal(t, piece_store.MoveCopying, status.Phase)
+	require.Equal(t, int64(1), status.BytesDone)
+	require.Equal(t, target, status.Target)
+
+	moves, err := st.Moves()
+	require.NoError(t, err)
+	require.Len(t, moves, 1)
+	require.Equal(t, target, moves[0].Target)
+	require.False(t, moves[0].Committed)
+
+	close(release)
+	require.Eventually(t, func() bool { return d.GetState() == Downloading }, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		s := d.MoveStatus()
+		return s != nil && s.Error == errMoveTest.Error()
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Eventually(t, func() bool {
+		moves, err := st.Moves()
+		return err == nil && len(moves) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestRequestMoveCommitsJournal(t *testing.T) {
+	var journal []store.MoveJournal
+	var st *store.Store
+	d := newTestDownload(t, 1, 1, func(info meta.Info) piece_store.PieceStore {
+		return &moveTestStore{
+			Store: piece_store.NewMemStore(info),
+			move: func(_ context.Context, _ string, report piece_store.MoveProgressFunc) error {
+				report(piece_store.MoveProgress{Phase: piece_store.MoveCleaning})
+				var err error
+				journal, err = st.Moves()
+				return err
+			},
+		}
+	})
+	st = withTestSessionStore(t, d)
+	target := t.TempDir()
+
+	require.NoError(t, d.RequestMove(target))
+	require.Len(t, journal, 1)
+	require.True(t, journal[0].Committed, "the journal is committed before sources are removed")
+	require.Nil(t, d.MoveStatus())
+	require.Equal(t, target, d.BasePath())
+
+	moves, err := st.Moves()
+	require.NoError(t, err)
+	require.Empty(t, moves)
+	all, err := st.All()
+	require.NoError(t, err)
+	require.Equal(t, target, all[0].BasePath)
+}
+
 func TestPruneEmptyDirectories_EmptyDir(t *testing.T) {
 	tmp := t.TempDir()
 	dir := filepath.Join(tmp, "empty-dir")
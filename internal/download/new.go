@@ -13,6 +13,7 @@ import (
 	"go.uber.org/atomic"
 
 	"neptune/internal/client/tracker"
+	"neptune/internal/config"
 	"neptune/internal/meta"
 	"neptune/internal/metainfo"
 	"neptune/internal/piece_store"
@@ -23,6 +24,7 @@ import (
 	"neptune/internal/pkg/random"
 	"neptune/internal/pkg/ratelimit"
 	"neptune/internal/session"
+	"neptune/internal/session/store"
 )
 
 // defaultStrategy returns the client-level default piece pick strategy.
@@ -37,25 +39,52 @@ func defaultStrategy(cfg string) PiecePickStrategy {
 
 // InitState describes the validated state used to construct a Download.
 type InitState struct {
-	CompletedPieces   *bm.Bitmap
-	resume            *resumeInitState
+	CompletedPieces *bm.Bitmap
+	resume          *resumeInitState
+	// CompletePath is where the data moves when the download completes,
+	// empty to keep it in the base path.
+	CompletePath string
+	// SharedFiles are files holding data another torrent or the user owns,
+	// such as cross-seeded files remapped or hardlinked to existing data.
+	// They are read but never written, until a hash check finds pieces of
+	// them missing, see releaseSharedFiles.
+	SharedFiles       []int
 	TrackerStagger    time.Duration
 	PiecePickStrategy PiecePickStrategy
 	State             State
+	Allocation        Allocation
 	SkipHashCheck     bool
 }
 
 type resumeInitState struct {
 	addAt              time.Time
 	completedAt        time.Time
+	verifiedAt         time.Time
 	trackerKey         string
 	trackers           metainfo.AnnounceList
+	parts              *piece_store.PartFiles
+	partialPieces      []partialPiece
+	fileStats          []store.FileStat
+	mountPoint         string
+	mountErr           error  // the data is not on mountPoint, start in Error
+	completeMoveError  string // the last move to the complete path failed
+	unverified         []byte
 	downloaded         int64
 	uploaded           int64
 	corrupted          int64
 	downloadSpeedLimit int64
 	uploadSpeedLimit   int64
 	queueWeight        int64
+	scrubCursor        uint32
+}
+
+// partSuffix returns the suffix of files being downloaded, empty when they
+// are written under their name.
+func partSuffix(app config.Application) string {
+	if !app.PartFiles {
+		return ""
+	}
+	return app.PartSuffix
 }
 
 func newSelectedFilesSet(numFiles int, selectedFiles []int) (*bm.Bitmap, error) {
@@ -132,7 +161,19 @@ func newDownload(
 	}
 	normalChunkLen := info.BlocksPerPiece()
 
-	store := piece_store.NewFileStore(info, basePath, sess.FilePool, sess.IOContext, selectedFilesSet, sess.Config.App.Fallocate)
+	var parts *piece_store.PartFiles
+	if init.resume != nil {
+		parts = init.resume.parts
+	} else {
+		parts = piece_store.NewPartFiles(&info, basePath, partSuffix(sess.Config.App))
+	}
+	sharedFiles := bm.NewLockFreeBitmap(uint32(len(info.Files)))
+	for _, idx := range init.SharedFiles {
+		if idx >= 0 && idx < len(info.Files) {
+			sharedFiles.Set(uint32(idx))
+		}
+	}
+	store := piece_store.NewFileStore(info, basePath, sess.FilePool, sess.IOContext, selectedFilesSet, sharedFiles, init.Allocation == AllocFull, parts)
 
 	d := &Download{
 		ctx:    ctx,
@@ -144,12 +185,14 @@ func newDownload(
 		peerID:  NewPeerID(),
 
 		selectedFilesSet: selectedFilesSet,
+		sharedFiles:      sharedFiles,
 
 		s: downloadState{
-			tags:        tags,
-			custom:      custom,
-			basePath:    basePath,
-			downloadDir: basePath,
+			tags:         tags,
+			custom:       custom,
+			basePath:     basePath,
+			downloadDir:  basePath,
+			completePath: init.CompletePath,
 		},
 
 		normalChunkLen: normalChunkLen,
@@ -168,7 +211,9 @@ func newDownload(
 
 		corruptedPieces: make(map[uint32]int),
 
-		store: store,
+		store:      store,
+		parts:      parts,
+		allocation: init.Allocation,
 
 		private: info.Private,
 
@@ -176,6 +221,7 @@ func newDownload(
 
 		scheduleResponseSignal: make(chan empty.Empty, 1),
 		connectSignal:          make(chan struct{}, 1),
+		missingDataSignal:      make(chan struct{}, 1),
 
 		downloadLimiter: ratelimit.New(0),
 		uploadLimiter:   ratelimit.New(0),
@@ -185,6 +231,13 @@ func newDownload(
 
 	d.peerList = newPeerList(d)
 
+	if init.resume != nil && init.resume.mountPoint != "" {
+		d.s.mountPoint = init.resume.mountPoint
+		store.SetMountPoint(d.s.mountPoint)
+	} else {
+		d.setMountPointUnsafe(basePath)
+	}
+
 	d.completedBm = completedBm
 	d.wantedBm = bm.New(info.NumPieces)
 	d.buildWantedBmUnsafe()
@@ -198,6 +251,7 @@ func newDownload(
 		}
 	})
 	d.missingBm = missingBm
+	d.writtenBlocks = bm.NewNilSafeLockFreeBitmap(info.TotalBlockCount())
 
 	if init.PiecePickStrategy > StrategySequential {
 		cancel()
@@ -212,6 +266,10 @@ func newDownload(
 	if restored := init.resume; restored != nil {
 		d.AddAt = restored.addAt
 		d.completedAt.Store(restored.completedAt.UnixNano())
+		if !restored.verifiedAt.IsZero() {
+			d.verifiedAt.Store(restored.verifiedAt.UnixNano())
+		}
+		d.scrubCursor.Store(restored.scrubCursor)
 		d.queueWeight.Store(restored.queueWeight)
 		d.downloaded.Store(restored.downloaded)
 		d.downloadAtStart = restored.downloaded
@@ -220,12 +278,32 @@ func newDownload(
 		d.corrupted.Store(restored.corrupted)
 		d.downloadLimiter.Update(restored.downloadSpeedLimit)
 		d.uploadLimiter.Update(restored.uploadSpeedLimit)
+		d.s.fileStats = restored.fileStats
+		if restored.mountErr != nil {
+			d.err.Store(&restored.mountErr)
+		}
+		if target := completePathPending(d.s.basePath, d.s.completePath); target != "" && restored.completeMoveError != "" {
+			d.moveStatus.Store(&MoveStatus{Target: target, Error: restored.completeMoveError})
+		}
+		if restored.unverified != nil {
+			// A malformed record trusts nothing it cannot account for.
+			unverified := d.completedBm
+			if len(restored.unverified) == int(d.bitfieldSize) {
+				unverified = bm.FromBitfields(restored.unverified, info.NumPieces)
+			}
+			d.enterSeedMode(unverified)
+		}
 		if restored.trackerKey != "" {
 			trackerKey = restored.trackerKey
 		}
 		if len(restored.trackers) > 0 {
 			announceList = restored.trackers
 		}
+	} else if init.State == Seeding {
+		// a new download only starts seeding with data its caller just hashed
+		now := time.Now().UnixNano()
+		d.completedAt.Store(now)
+		d.verifiedAt.Store(now)
 	}
 
 	if err := validateInitState(init.State, d.isComplete(), d.completedBm.Count()); err != nil {
@@ -254,6 +332,7 @@ func newDownload(
 
 	d.stateCond = gsync.NewCond(&gsync.EmptyLock{})
 	d.setAnnounceList(announceList)
+	d.indexPaths()
 
 	if init.State == Checking {
 		d.goBackground(func() {
@@ -264,13 +343,29 @@ func newDownload(
 			// syncTrackerState, which starts the chain for new downloads.
 		})
 	} else {
+		// Files are not renamed on the filesystem that took the place of a
+		// missing mount.
+		if init.State != Error {
+			d.syncPartFiles()
+		}
 		d.initializePiecePicker()
+		// Compare file stats before startRuntime saves new ones. Stopped
+		// downloads are compared on Start instead.
+		changed := bm.New(info.NumPieces)
+		if init.resume != nil {
+			d.restorePartialPieces(init.resume.partialPieces)
+			if d.IsActive() {
+				changed = d.changedPieces()
+			}
+		}
 		d.startRuntime()
 		// Resume restores the final state directly instead of transitioning,
 		// so syncTrackerState never fires here: apply the stagger explicitly.
 		if d.IsActive() {
 			d.tracker.Start(init.TrackerStagger)
 		}
+		d.retryCompleteMove()
+		d.verifyChangedPieces(changed)
 	}
 
 	return d, nil
@@ -290,7 +385,7 @@ func validateInitState(state State, complete bool, completedPieces uint32) error
 		if !complete {
 			return errors.New("seeding download cannot have missing pieces")
 		}
-	case Stopped:
+	case Stopped, Error:
 	default:
 		return fmt.Errorf("invalid initial download state %s", state)
 	}
@@ -348,6 +348,20 @@ func (p *peerImpl) Have(index uint32) {
 	})
 }
 
+// DontHave tells the peer we lost piece index, if it supports lt_donthave
+// (BEP 54). Other peers learn it from the rejected requests.
+func (p *peerImpl) DontHave(index uint32) {
+	id := p.extDontHaveID.Load()
+	if id == 0 {
+		return
+	}
+	p.sendEventX(Event{
+		Event:       proto.Extended,
+		ExtensionID: id,
+		Index:       index,
+	})
+}
+
 func (p *peerImpl) Unchoke() {
 	p.sendEventX(Event{Event: proto.Unchoke})
 }
@@ -306,7 +306,13 @@ func (p *peerImpl) write(e Event) error {
 			return err
 		}
 
-		fallthrough
+		// lt_donthave is the only other extension message we send. The peer
+		// may have remapped or disabled it with a new handshake since the
+		// message was queued, it is dropped then.
+		if id := p.extDontHaveID.Load(); id != 0 && e.ExtensionID == id {
+			return proto.SendDontHave(p.w, e.ExtensionID, e.Index)
+		}
+		return nil
 	case proto.BitCometExtension:
 		panic("unexpected event")
 	}
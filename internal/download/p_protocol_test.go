@@ -85,3 +85,27 @@ func TestDecodeEventsValidMessages(t *testing.T) {
 	require.Equal(t, proto.Request, ev.Event)
 	require.Equal(t, uint32(0x4000), ev.Req.Length)
 }
+
+type fakeWriteConn struct {
+	net.Conn
+}
+
+func (fakeWriteConn) SetWriteDeadline(time.Time) error { return nil }
+
+func TestWriteDropsDontHaveAfterExtensionRemap(t *testing.T) {
+	var buf bytes.Buffer
+	p := &peerImpl{Conn: fakeWriteConn{}, w: bufio.NewWriter(&buf)}
+	p.extDontHaveID.Store(3)
+	e := Event{Event: proto.Extended, ExtensionID: 3, Index: 7}
+
+	// a second handshake remapped lt_donthave after the message was queued
+	p.extDontHaveID.Store(5)
+	require.NoError(t, p.write(e))
+
+	// or disabled it
+	p.extDontHaveID.Store(0)
+	require.NoError(t, p.write(e))
+
+	require.NoError(t, p.w.Flush())
+	require.Zero(t, buf.Len())
+}
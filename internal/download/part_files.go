@@ -0,0 +1,50 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package download
+
+// fileComplete reports whether every piece covering file index is verified.
+func (d *Download) fileComplete(index int) bool {
+	start, end := d.info.FilePieces(index)
+	for i := start; i < end; i++ {
+		if !d.completedBm.Contains(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// finishPartFiles gives the files of a just verified piece their name, once
+// all their pieces are verified.
+func (d *Download) finishPartFiles(pieceIndex uint32) {
+	if d.parts == nil {
+		return
+	}
+	for chunk := range d.info.PieceFileChunks(pieceIndex) {
+		if d.parts.IsPart(chunk.FileIndex) && d.fileComplete(chunk.FileIndex) {
+			d.setFileComplete(chunk.FileIndex, true)
+		}
+	}
+}
+
+// syncPartFiles names every file after the bitfield changed outside of
+// regular downloading: complete files get their name, incomplete files their
+// part name. It also finishes renames a crash or failure left undone.
+func (d *Download) syncPartFiles() {
+	if d.parts == nil {
+		return
+	}
+	for i := range d.info.Files {
+		complete := d.fileComplete(i)
+		if d.parts.IsPart(i) == complete {
+			d.setFileComplete(i, complete)
+		}
+	}
+}
+
+func (d *Download) setFileComplete(index int, complete bool) {
+	if err := d.store.SetFileComplete(index, complete); err != nil {
+		// the file keeps its current name, syncPartFiles tries again later
+		d.log.Warn().Err(err).Int("file", index).Bool("complete", complete).Msg("failed to rename part file")
+	}
+}
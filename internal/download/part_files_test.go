@@ -0,0 +1,75 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package download
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/pkg/bm"
+	"neptune/internal/session/store"
+)
+
+func newPartFilesFixture(t *testing.T, numPieces uint32) (resumeTestFixture, string) {
+	t.Helper()
+	f := newResumeTestFixture(t, numPieces)
+	f.sess.Config.App.PartFiles = true
+	f.sess.Config.App.PartSuffix = ".part"
+
+	path := filepath.Join(f.basePath, f.info.Files[0].Path)
+	require.NoError(t, os.MkdirAll(f.basePath, 0o755))
+	require.NoError(t, os.WriteFile(path+".part", make([]byte, f.info.TotalLength), 0o644))
+	return f, path
+}
+
+func TestPartFileRenamedWhenComplete(t *testing.T) {
+	f, path := newPartFilesFixture(t, 2)
+	d := f.load(t, f.resumeData(t, store.ResumeStopped, 0))
+
+	require.True(t, d.parts.IsPart(0))
+	require.Equal(t, []string{"test.data.part"}, d.DataFiles())
+
+	done := bm.NewNilSafeLockFreeBitmap(d.info.TotalBlockCount())
+	require.NoError(t, d.checkPiece(1, &peerContributors{}, done))
+
+	require.False(t, d.parts.IsPart(0))
+	require.FileExists(t, path)
+	require.NoFileExists(t, path+".part")
+	require.Equal(t, []string{"test.data"}, d.DataFiles())
+
+	buf := make([]byte, defaultBlockSize)
+	_, err := d.store.ReadChunk(t.Context(), 1, 0, buf)
+	require.NoError(t, err, "reads open the file under its new name")
+}
+
+func TestLoadFromResumeFinishesPartFileRename(t *testing.T) {
+	f, path := newPartFilesFixture(t, 2)
+	d := f.load(t, f.resumeData(t, store.ResumeStopped, 0, 1))
+
+	require.False(t, d.parts.IsPart(0))
+	require.FileExists(t, path)
+	require.NoFileExists(t, path+".part")
+}
+
+func TestDroppedPiecesRenameFileBackToPart(t *testing.T) {
+	f, path := newPartFilesFixture(t, 2)
+	require.NoError(t, os.Rename(path+".part", path))
+	d := f.load(t, f.resumeData(t, store.ResumeStopped, 0, 1))
+	require.False(t, d.parts.IsPart(0))
+
+	lost := bm.New(d.info.NumPieces)
+	lost.Set(1)
+	d.transitionMu.Lock()
+	d.dropCompletedPieces(lost)
+	d.transitionMu.Unlock()
+
+	require.True(t, d.parts.IsPart(0))
+	require.FileExists(t, path+".part")
+	require.NoFileExists(t, path)
+}
@@ -0,0 +1,87 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package download
+
+import (
+	"path/filepath"
+
+	"neptune/internal/meta"
+)
+
+// OwnedPaths returns the absolute path of every file of info under each of
+// basePaths, mapped to the file index. Empty base paths are skipped.
+func OwnedPaths(info *meta.Info, basePaths ...string) map[string]int {
+	paths := make(map[string]int, len(info.Files))
+	for _, basePath := range basePaths {
+		if basePath == "" {
+			continue
+		}
+		basePath, err := filepath.Abs(basePath)
+		if err != nil {
+			continue
+		}
+		for i, f := range info.Files {
+			paths[f.FullPath(basePath)] = i
+		}
+	}
+	return paths
+}
+
+// FilesAt returns the absolute paths the files would have under basePath.
+func (d *Download) FilesAt(basePath string) map[string]int {
+	info := d.fileInfo()
+	return OwnedPaths(&info, basePath)
+}
+
+// SameFileData reports whether file index of the download is known to hold
+// the same bytes as file other of info.
+func (d *Download) SameFileData(index int, info *meta.Info, other int) bool {
+	return d.info.SameFileData(index, info, other)
+}
+
+// SameFileAs reports whether file index of the download is known to hold the
+// same bytes as file other of another download.
+func (d *Download) SameFileAs(index int, o *Download, other int) bool {
+	return d.info.SameFileData(index, &o.info, other)
+}
+
+// indexPaths records the files of the download in the session path index,
+// where they are and where they move to on completion.
+func (d *Download) indexPaths() {
+	d.s.mu.RLock()
+	paths := OwnedPaths(&d.info, d.s.basePath, completePathPending(d.s.basePath, d.s.completePath))
+	d.s.mu.RUnlock()
+	d.session.Paths.Set(d.info.Hash, paths)
+}
+
+// CompletedFile is a file of a download whose pieces are all verified.
+type CompletedFile struct {
+	Path   string
+	Length int64
+	Index  int
+}
+
+// CompletedFiles returns the non-empty files whose pieces are all verified,
+// with their absolute path.
+func (d *Download) CompletedFiles() []CompletedFile {
+	basePath := d.BasePath()
+	var files []CompletedFile
+	for i, f := range d.fileInfo().Files {
+		start, end := d.info.FilePieces(i)
+		if start == end {
+			continue
+		}
+		complete := true
+		for piece := start; piece < end; piece++ {
+			if !d.completedBm.Contains(piece) {
+				complete = false
+				break
+			}
+		}
+		if complete {
+			files = append(files, CompletedFile{Path: f.FullPath(basePath), Length: f.Length, Index: i})
+		}
+	}
+	return files
+}
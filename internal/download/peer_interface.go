@@ -77,6 +77,7 @@ type PeerInterface interface {
 	SendChoke()
 	SendUnchoke()
 	Have(index uint32)
+	DontHave(index uint32)
 
 	// ── Transfer tracking ────────────────────────────────────────────
 	HadTransfer() bool
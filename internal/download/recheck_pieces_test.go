@@ -0,0 +1,66 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package download
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/pkg/bm"
+	"neptune/internal/session/store"
+)
+
+func TestRecheckPiecesKeepsOtherPieces(t *testing.T) {
+	f := newResumeTestFixture(t, 3)
+	f.writeDataFile(t)
+	d := f.load(t, f.resumeData(t, store.ResumeActive, 0, 1))
+	require.Equal(t, Downloading, d.GetState())
+
+	// piece 0 is corrupt on disk but not part of the check
+	file, err := os.OpenFile(filepath.Join(f.basePath, f.info.Files[0].Path), os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = file.WriteAt([]byte{1}, 0)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	require.NoError(t, d.RecheckPieces(nil, []PieceRange{{First: 1, Last: 2}}))
+	require.Eventually(t, func() bool { return d.GetState() == Seeding }, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []uint32{0, 1, 2}, d.completedBm.ToArray())
+	require.Zero(t, d.verifiedAt.Load(), "a partial check does not verify all data")
+}
+
+func TestRecheckPiecesRejectsInvalidSelection(t *testing.T) {
+	f := newResumeTestFixture(t, 3)
+	d := f.load(t, f.resumeData(t, store.ResumeActive))
+
+	require.ErrorContains(t, d.RecheckPieces([]int{1}, nil), "invalid file index")
+	require.ErrorContains(t, d.RecheckPieces(nil, []PieceRange{{First: 2, Last: 3}}), "invalid piece range")
+	require.ErrorContains(t, d.RecheckPieces(nil, []PieceRange{{First: 2, Last: 1}}), "invalid piece range")
+	require.ErrorIs(t, d.RecheckPieces(nil, nil), errNoPiecesToCheck)
+	require.Error(t, d.RestoreCheck([]byte{0xff, 0xff}))
+	require.Equal(t, Downloading, d.GetState())
+}
+
+func TestCheckPiecesOfQueuedCheck(t *testing.T) {
+	f := newResumeTestFixture(t, 3)
+	d := f.load(t, f.resumeData(t, store.ResumeActive, 0))
+	require.Nil(t, d.CheckPieces(), "no check is queued")
+
+	_, err := d.queueCheck(Downloading, nil)
+	require.NoError(t, err)
+	require.Nil(t, d.CheckPieces(), "a full check has no piece list")
+
+	pieces := bm.New(d.info.NumPieces)
+	pieces.Set(1)
+	pieces.Set(2)
+	_, err = d.queueCheck(Downloading, pieces)
+	require.NoError(t, err)
+	require.Equal(t, []byte{0b0110_0000}, d.CheckPieces())
+}
@@ -0,0 +1,119 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package download
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"neptune/internal/meta"
+)
+
+type fileRename struct {
+	path  string
+	index int
+}
+
+// fileInfo returns a copy of the torrent info whose file paths stay valid
+// while files are renamed.
+func (d *Download) fileInfo() meta.Info {
+	d.s.mu.RLock()
+	defer d.s.mu.RUnlock()
+	info := d.info
+	info.Files = slices.Clone(info.Files)
+	return info
+}
+
+// RenameFile changes the path of file index. path is relative to the base
+// path, or absolute to use a file outside of it. The file is renamed on disk
+// when it exists.
+func (d *Download) RenameFile(index int, path string) error {
+	if index < 0 || index >= len(d.info.Files) {
+		return fmt.Errorf("file index %d out of range", index)
+	}
+	return d.renameFiles([]fileRename{{index: index, path: filepath.Clean(path)}})
+}
+
+// RenameFolder moves every file under folder to newFolder, both relative to
+// the base path. Files remapped to an absolute path are not part of any
+// folder.
+func (d *Download) RenameFolder(folder, newFolder string) error {
+	prefix := filepath.Clean(folder) + string(filepath.Separator)
+	newFolder = filepath.Clean(newFolder)
+
+	var renames []fileRename
+	for i, f := range d.fileInfo().Files {
+		if filepath.IsAbs(f.Path) {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(f.Path, prefix); ok {
+			renames = append(renames, fileRename{index: i, path: filepath.Join(newFolder, rest)})
+		}
+	}
+	if len(renames) == 0 {
+		return fmt.Errorf("no file in folder %q", folder)
+	}
+	return d.renameFiles(renames)
+}
+
+// renameFiles applies renames in order. If one fails, the files already
+// renamed are put back.
+func (d *Download) renameFiles(renames []fileRename) error {
+	if _, err := d.lockFiles(); err != nil {
+		return err
+	}
+
+	err := d.renameFilesLocked(renames)
+	d.transitionMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	d.indexPaths()
+	d.saveResume()
+	return nil
+}
+
+func (d *Download) renameFilesLocked(renames []fileRename) error {
+	taken := make(map[string]struct{}, len(d.info.Files))
+	for _, f := range d.info.Files {
+		taken[f.Path] = struct{}{}
+	}
+	for _, r := range renames {
+		delete(taken, d.info.Files[r.index].Path)
+	}
+	for _, r := range renames {
+		if _, ok := taken[r.path]; ok {
+			return fmt.Errorf("another file already uses path %q", r.path)
+		}
+		taken[r.path] = struct{}{}
+	}
+
+	// The store keeps its own copy of the paths, d.info.Files is only
+	// updated once every file is renamed.
+	done := make([]fileRename, 0, len(renames))
+	for _, r := range renames {
+		old := d.info.Files[r.index].Path
+		if err := d.store.RenameFile(r.index, r.path); err != nil {
+			for i := len(done) - 1; i >= 0; i-- {
+				if undoErr := d.store.RenameFile(done[i].index, done[i].path); undoErr != nil {
+					d.log.Err(undoErr).Str("path", done[i].path).Msg("failed to undo file rename")
+				}
+			}
+			return err
+		}
+		done = append(done, fileRename{index: r.index, path: old})
+	}
+
+	d.s.mu.Lock()
+	for _, r := range renames {
+		d.info.Files[r.index].SetPath(r.path)
+	}
+	d.s.mu.Unlock()
+
+	d.log.Info().Int("files", len(renames)).Msg("files renamed")
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package download
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/session/store"
+)
+
+func TestRenameFileKeepsDataAndPersistsPath(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	f.writeDataFile(t)
+	d := f.load(t, f.resumeData(t, store.ResumeStopped, 0, 1))
+
+	renamed := filepath.Join("season 1", "episode.data")
+	require.NoError(t, d.RenameFile(0, renamed))
+	require.FileExists(t, filepath.Join(f.basePath, renamed))
+	require.Equal(t, []string{renamed}, d.resumeRecord().FilePaths)
+
+	ok, err := d.store.VerifyPiece(context.Background(), 1, d.info.Pieces[1])
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	require.NoError(t, d.RenameFolder("season 1", "s01"))
+	require.FileExists(t, filepath.Join(f.basePath, "s01", "episode.data"))
+	require.NoDirExists(t, filepath.Join(f.basePath, "season 1"))
+
+	require.Error(t, d.RenameFolder("season 1", "s02"), "the folder is gone")
+	require.Error(t, d.RenameFile(1, "other"), "index out of range")
+
+	d.state.Store(uint32(Checking))
+	require.ErrorIs(t, d.RenameFile(0, "episode.data"), errFilesChecking)
+	d.state.Store(uint32(Stopped))
+}
+
+func TestRenameFileWhileFilesAreListed(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	f.writeDataFile(t)
+	d := f.load(t, f.resumeData(t, store.ResumeStopped, 0, 1))
+
+	// run with -race: the listings read the paths renames change
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 20 {
+			_ = d.RenameFile(0, fmt.Sprintf("episode-%d.data", i))
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			require.Equal(t, []string{"episode-19.data"}, d.DataFiles())
+			require.Equal(t, []string{"episode-19.data"}, d.resumeRecord().FilePaths)
+			return
+		default:
+			_ = d.Files()
+			_ = d.DataFiles()
+			_ = d.CompletedFiles()
+			_ = d.resumeRecord()
+		}
+	}
+}
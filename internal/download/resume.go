@@ -7,6 +7,7 @@ import (
 	"slices"
 	"time"
 
+	"neptune/internal/pkg/bm"
 	"neptune/internal/pkg/timestamp"
 	"neptune/internal/session/store"
 )
@@ -15,14 +16,30 @@ func (d *Download) saveResume() {
 	if d.session.Store == nil {
 		return
 	}
-	if err := d.session.Store.Upsert(d.resumeRecord()); err != nil {
+	r := d.resumeRecord()
+	d.s.mu.Lock()
+	d.s.fileStats = r.FileStats
+	d.s.mu.Unlock()
+	if err := d.session.Store.Upsert(r); err != nil {
 		d.log.Err(err).Msg("failed to save download")
 	}
 }
 
 func (d *Download) resumeRecord() *store.Resume {
 	d.s.mu.RLock()
-	defer d.s.mu.RUnlock()
+	basePath := d.s.basePath
+	completePath := d.s.completePath
+	mountPoint := d.s.mountPoint
+	tags := d.s.tags
+	custom := d.s.custom
+	savedStats := d.s.fileStats
+	filePaths := d.filePathsLocked()
+	d.s.mu.RUnlock()
+
+	var sharedFiles []int
+	d.sharedFiles.Range(func(i uint32) {
+		sharedFiles = append(sharedFiles, int(i))
+	})
 
 	var selectedFiles []int
 	if d.selectedFilesSet.Count() != uint32(len(d.info.Files)) {
@@ -33,27 +50,90 @@ func (d *Download) resumeRecord() *store.Resume {
 		slices.Sort(selectedFiles)
 	}
 
+	state := d.GetState()
+	bitfield := d.completedBm.Bitfield()
+	var partialPieces []store.PartialPiece
+	if state == Checking {
+		// Persist what the check replaced; the check itself is persisted in
+		// the check queue and runs again after a restart.
+		if r := d.checkRestore.Load(); r != nil {
+			state = r.state
+			bitfield = r.bitmap.Bitfield()
+		}
+	} else {
+		partialPieces = d.partialPieces()
+	}
+
+	// A stopped download keeps the stats from when it stopped, so changes
+	// made while stopped are still found by the next Start. So does one whose
+	// mount is missing, until the mount comes back.
+	fileStats := savedStats
+	if (state != Stopped && !d.MountMissing()) || fileStats == nil {
+		fileStats = statFiles(d.fileInfo(), basePath, d.parts)
+	}
+
+	var completeMoveError string
+	if s := d.failedCompleteMove(); s != nil {
+		completeMoveError = s.Error
+	}
+
 	return &store.Resume{
-		BasePath:           d.s.basePath,
+		BasePath:           basePath,
+		CompletePath:       completePath,
+		CompleteMoveError:  completeMoveError,
+		MountPoint:         mountPoint,
+		Allocation:         uint8(d.allocation),
 		Downloaded:         d.downloaded.Load(),
 		Uploaded:           d.uploaded.Load(),
 		Corrupted:          d.corrupted.Load(),
-		Tags:               d.s.tags,
-		Custom:             d.s.custom,
-		State:              normalizeResumeState(d.GetState()),
+		Tags:               tags,
+		Custom:             custom,
+		State:              normalizeResumeState(state),
 		InfoHash:           d.info.Hash.Hex(),
-		Bitfield:           d.completedBm.Bitfield(),
+		Bitfield:           bitfield,
 		AddAt:              timestamp.New(d.AddAt),
 		CompletedAt:        timestamp.New(time.Unix(0, d.completedAt.Load())),
+		VerifiedAt:         timestamp.New(time.Unix(0, d.verifiedAt.Load())),
+		ScrubCursor:        d.scrubCursor.Load(),
 		SelectedFiles:      selectedFiles,
-		FilePaths:          d.filePaths(),
+		FilePaths:          filePaths,
 		DownloadSpeedLimit: d.downloadLimiter.Rate(),
 		UploadSpeedLimit:   d.uploadLimiter.Rate(),
 		Trackers:           d.tracker.URLs(),
 		TrackerKey:         d.tracker.Key,
 		PiecePickStrategy:  uint32(d.GetPiecePickStrategy()),
 		QueueWeight:        int64(d.QueueWeight()),
+		PartialPieces:      partialPieces,
+		FileStats:          fileStats,
+		Unverified:         d.seedMode.Load().bitfield(),
+		SharedFiles:        sharedFiles,
+	}
+}
+
+// partialPieces returns the written blocks of pieces that are still being
+// downloaded. Fully written pieces are left out, they are about to be
+// verified and would otherwise be trusted without a hash check after restart.
+func (d *Download) partialPieces() []store.PartialPiece {
+	var r []store.PartialPiece
+	for _, index := range d.picker.Load().DownloadingPieces() {
+		if d.completedBm.Contains(index) {
+			continue
+		}
+
+		n := uint32(d.info.PieceBlockCount(index))
+		start := index * d.normalChunkLen
+		blocks := bm.New(n)
+		for i := range n {
+			if d.writtenBlocks.Contains(start + i) {
+				blocks.Set(i)
+			}
+		}
+		if c := blocks.Count(); c == 0 || c == n {
+			continue
+		}
+		r = append(r, store.PartialPiece{Index: index, Blocks: blocks.Bitfield()})
 	}
+	return r
 }
 
 func normalizeResumeState(s State) store.ResumeState {
@@ -63,7 +143,8 @@ func normalizeResumeState(s State) store.ResumeState {
 	return store.ResumeActive
 }
 
-func (d *Download) filePaths() []string {
+// filePathsLocked returns the path of every file. Caller must hold d.s.mu.
+func (d *Download) filePathsLocked() []string {
 	paths := make([]string, len(d.info.Files))
 	for i, f := range d.info.Files {
 		paths[i] = f.Path
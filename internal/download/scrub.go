@@ -0,0 +1,157 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package download
+
+import (
+	"context"
+	"time"
+
+	"neptune/internal/pkg/bm"
+)
+
+// ScrubLimiter paces the reads of a background scrub.
+type ScrubLimiter interface {
+	// Wait blocks until a piece of n bytes, read with the given number of
+	// file reads, may be checked. An error stops the scrub for now.
+	Wait(ctx context.Context, n int64, reads int) error
+}
+
+// ScrubStatus is the background scrub state of a download.
+type ScrubStatus struct {
+	// VerifiedAt is the unix time all data was last hash checked, by a scrub
+	// or a full recheck. 0 when never.
+	VerifiedAt int64
+	// PiecesDone is how far the unfinished scrub got, 0 when none is in
+	// progress.
+	PiecesDone   uint32
+	FailedPieces uint32
+	Scrubbing    bool
+}
+
+func (d *Download) scrubStatus() ScrubStatus {
+	return ScrubStatus{
+		VerifiedAt:   d.verifiedAt.Load() / 1e9,
+		PiecesDone:   d.scrubCursor.Load(),
+		FailedPieces: d.scrubFailed.Load(),
+		Scrubbing:    d.scrubbing.Load(),
+	}
+}
+
+// LastVerified returns when all data of the download was last hash checked.
+// A download that was never scrubbed counts from its completion, every piece
+// was checked as it was downloaded.
+func (d *Download) LastVerified() time.Time {
+	if at := d.verifiedAt.Load(); at != 0 {
+		return time.Unix(0, at)
+	}
+	if at := d.completedAt.Load(); at != 0 {
+		return time.Unix(0, at)
+	}
+	return d.AddAt
+}
+
+// ScrubDue reports whether the download should be scrubbed: it is seeding
+// with all of its pieces trusted, and either a scrub was interrupted or the
+// data was last verified at least interval ago.
+func (d *Download) ScrubDue(now time.Time, interval time.Duration) bool {
+	if d.GetState() != Seeding || d.seedMode.Load() != nil || d.scrubbing.Load() {
+		return false
+	}
+	if d.scrubCursor.Load() != 0 {
+		return true
+	}
+	return now.Sub(d.LastVerified()) >= interval
+}
+
+// Scrub hash checks the completed pieces of a seeding download, starting
+// where the last scrub stopped. It returns early without an error when the
+// download stops seeding or a regular hash check is waiting, and the next
+// call picks up from there. Pieces that fail are downloaded again.
+func (d *Download) Scrub(limiter ScrubLimiter) error {
+	if !d.scrubbing.CompareAndSwap(false, true) {
+		return nil
+	}
+	defer d.scrubbing.Store(false)
+
+	start := d.scrubCursor.Load()
+	if start >= d.info.NumPieces {
+		start = 0
+	}
+	if start == 0 {
+		d.scrubFailed.Store(0)
+		d.log.Debug().Msg("scrub started")
+	}
+
+	index := start
+	defer func() {
+		if index != start {
+			d.saveResume()
+		}
+	}()
+
+	for ; index < d.info.NumPieces; index++ {
+		if d.GetState() != Seeding || d.session.HashCheck.Busy() {
+			return nil
+		}
+		if !d.completedBm.Contains(index) {
+			d.scrubCursor.Store(index + 1)
+			continue
+		}
+
+		if err := limiter.Wait(d.ctx, d.info.PieceLen(index), d.pieceReads(index)); err != nil {
+			return err
+		}
+		ok, err := d.store.VerifyPiece(d.ctx, index, d.info.Pieces[index])
+		if err != nil {
+			if d.ctx.Err() != nil {
+				return d.ctx.Err()
+			}
+			if isMissingData(err) {
+				// not bit rot, missingDataLoop unmarks what is gone.
+				d.signalMissingData()
+				return nil
+			}
+			return err
+		}
+		if !ok {
+			d.scrubPieceFailed(index)
+		}
+		d.scrubCursor.Store(index + 1)
+	}
+
+	d.verifiedAt.Store(time.Now().UnixNano())
+	d.scrubCursor.Store(0)
+	d.log.Info().Uint32("failed_pieces", d.scrubFailed.Load()).Msg("scrub finished")
+	return nil
+}
+
+// pieceReads returns how many files a piece spans, each one is a seek.
+func (d *Download) pieceReads(index uint32) int {
+	var n int
+	for range d.info.PieceFileChunks(index) {
+		n++
+	}
+	return n
+}
+
+// scrubPieceFailed unmarks a piece whose data no longer matches its hash, so
+// it is downloaded again.
+func (d *Download) scrubPieceFailed(index uint32) {
+	d.log.Warn().Uint32("piece", index).Msg("piece failed scrub hash check, downloading it again")
+	d.scrubFailed.Add(1)
+
+	pieces := bm.New(d.info.NumPieces)
+	pieces.Set(index)
+	d.transitionMu.Lock()
+	d.dropCompletedPieces(pieces)
+	d.transitionMu.Unlock()
+	d.dontHave(pieces)
+}
+
+// fullyVerified records that a hash check of every piece just passed, which
+// is as good as a scrub.
+func (d *Download) fullyVerified() {
+	d.verifiedAt.Store(time.Now().UnixNano())
+	d.scrubCursor.Store(0)
+}
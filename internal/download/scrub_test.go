@@ -0,0 +1,121 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package download
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/session/store"
+)
+
+// testScrubLimiter records the pieces a scrub reads, and fails once limit of
+// them were allowed when limit is not zero.
+type testScrubLimiter struct {
+	sizes []int64
+	limit int
+}
+
+var errTestScrubLimit = errors.New("scrub limit reached")
+
+func (l *testScrubLimiter) Wait(_ context.Context, n int64, reads int) error {
+	if l.limit != 0 && len(l.sizes) == l.limit {
+		return errTestScrubLimit
+	}
+	if reads != 1 {
+		return errors.New("a single file piece is one read")
+	}
+	l.sizes = append(l.sizes, n)
+	return nil
+}
+
+func TestScrubRecordsVerifiedTime(t *testing.T) {
+	f := newResumeTestFixture(t, 3)
+	f.writeDataFile(t)
+	d := f.load(t, f.resumeData(t, store.ResumeActive, 0, 1, 2))
+	require.Equal(t, Seeding, d.GetState())
+
+	before := time.Now()
+	require.True(t, d.ScrubDue(before, 0))
+
+	var l testScrubLimiter
+	require.NoError(t, d.Scrub(&l))
+	require.Len(t, l.sizes, 3)
+	require.Equal(t, []uint32{0, 1, 2}, d.completedBm.ToArray())
+	require.Equal(t, Seeding, d.GetState())
+
+	require.False(t, d.LastVerified().Before(before))
+	require.False(t, d.ScrubDue(time.Now(), time.Hour))
+	require.Equal(t, ScrubStatus{VerifiedAt: d.LastVerified().Unix()}, d.scrubStatus())
+	require.False(t, d.resumeRecord().VerifiedAt.Before(before))
+}
+
+func TestScrubRedownloadsCorruptPiece(t *testing.T) {
+	f := newResumeTestFixture(t, 3)
+	f.writeDataFile(t)
+	d := f.load(t, f.resumeData(t, store.ResumeActive, 0, 1, 2))
+	require.Equal(t, Seeding, d.GetState())
+
+	peer := newMockPeer()
+	require.True(t, d.peerList.Register(peer))
+
+	file, err := os.OpenFile(filepath.Join(f.basePath, f.info.Files[0].Path), os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = file.WriteAt([]byte{1}, f.info.PieceLength)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	var l testScrubLimiter
+	require.NoError(t, d.Scrub(&l))
+	require.Len(t, l.sizes, 2, "the scrub stops once the torrent is downloading again")
+	require.Equal(t, []uint32{0, 2}, d.completedBm.ToArray())
+	require.Equal(t, Downloading, d.GetState())
+	require.Equal(t, uint32(1), d.scrubStatus().FailedPieces)
+	require.Equal(t, uint32(2), d.resumeRecord().ScrubCursor)
+
+	require.Eventually(t, func() bool {
+		peer.mu.Lock()
+		defer peer.mu.Unlock()
+		return slices.Equal(peer.dontHave, []uint32{1})
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestScrubContinuesWhereItStopped(t *testing.T) {
+	f := newResumeTestFixture(t, 3)
+	f.writeDataFile(t)
+	r := f.resumeData(t, store.ResumeActive, 0, 1, 2)
+	d := f.load(t, r)
+
+	l := testScrubLimiter{limit: 1}
+	require.ErrorIs(t, d.Scrub(&l), errTestScrubLimit)
+	require.Equal(t, uint32(1), d.scrubStatus().PiecesDone)
+	require.True(t, d.ScrubDue(time.Now(), time.Hour), "an unfinished scrub is always due")
+
+	// the cursor survives a restart
+	r.ScrubCursor = d.resumeRecord().ScrubCursor
+	d.Close()
+	d = f.load(t, r)
+
+	l = testScrubLimiter{}
+	require.NoError(t, d.Scrub(&l))
+	require.Len(t, l.sizes, 2)
+	require.Zero(t, d.scrubStatus().PiecesDone)
+}
+
+func TestScrubDueOnlyWhenSeeding(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	f.writeDataFile(t)
+	d := f.load(t, f.resumeData(t, store.ResumeActive, 0))
+	require.Equal(t, Downloading, d.GetState())
+	require.False(t, d.ScrubDue(time.Now(), 0))
+}
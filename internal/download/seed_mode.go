@@ -0,0 +1,232 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package download
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"neptune/internal/pkg/bm"
+)
+
+// errSeedPieceCorrupt is returned for an upload request of a seed mode piece
+// whose data does not match its hash.
+var errSeedPieceCorrupt = errors.New("piece failed seed mode hash check")
+
+// seedModeIdleInterval is how often background verification looks again
+// while the download is inactive or a regular hash check has priority.
+const seedModeIdleInterval = time.Second
+
+// seedMode tracks the pieces of a torrent added with skip_hash_check, which
+// are trusted from their file sizes alone. Each piece is hash checked before
+// it is uploaded for the first time, and background verification checks the
+// rest at low priority.
+type seedMode struct {
+	unverified *bm.Bitmap
+	// verifying has a channel per piece being checked, closed when it is done.
+	verifying map[uint32]chan struct{}
+	// cursor is where background verification looks for the next piece.
+	cursor uint32
+	mu     sync.Mutex
+}
+
+func newSeedMode(unverified *bm.Bitmap) *seedMode {
+	return &seedMode{
+		unverified: unverified,
+		verifying:  make(map[uint32]chan struct{}),
+	}
+}
+
+// next returns the next unverified piece below n nobody is checking yet.
+func (s *seedMode) next(n uint32) (uint32, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for ; s.cursor < n; s.cursor++ {
+		if !s.unverified.Contains(s.cursor) {
+			continue
+		}
+		if _, ok := s.verifying[s.cursor]; ok {
+			continue
+		}
+		return s.cursor, true
+	}
+	return 0, false
+}
+
+// bitfield returns the unverified pieces for the resume record, nil when not
+// in seed mode.
+func (s *seedMode) bitfield() []byte {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.unverified.Bitfield()
+}
+
+func (s *seedMode) count() uint32 {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.unverified.Count()
+}
+
+// enterSeedMode trusts the completed pieces among unverified until they are
+// hash checked.
+func (d *Download) enterSeedMode(unverified *bm.Bitmap) {
+	unverified = unverified.WithAnd(d.completedBm)
+	if unverified.Count() == 0 {
+		return
+	}
+	d.seedMode.Store(newSeedMode(unverified))
+}
+
+// verifySeedPiece hash checks a seed mode piece the first time it is needed.
+// Concurrent callers for the same piece wait for a single check. It returns
+// errSeedPieceCorrupt if the piece failed, now or in an earlier check.
+func (d *Download) verifySeedPiece(ctx context.Context, index uint32) error {
+	sm := d.seedMode.Load()
+	if sm == nil {
+		return nil
+	}
+
+	sm.mu.Lock()
+	for {
+		if !sm.unverified.Contains(index) {
+			sm.mu.Unlock()
+			if !d.completedBm.Contains(index) {
+				return errSeedPieceCorrupt
+			}
+			return nil
+		}
+		wait, ok := sm.verifying[index]
+		if !ok {
+			break
+		}
+		sm.mu.Unlock()
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		sm.mu.Lock()
+	}
+	done := make(chan struct{})
+	sm.verifying[index] = done
+	sm.mu.Unlock()
+
+	ok, err := d.store.VerifyPiece(ctx, index, d.info.Pieces[index])
+
+	sm.mu.Lock()
+	delete(sm.verifying, index)
+	close(done)
+	if err != nil {
+		sm.mu.Unlock()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return err
+	}
+	sm.unverified.Unset(index)
+	if !ok {
+		// Under the seed mode lock, so concurrent failures do not both
+		// create a picker.
+		d.seedPieceFailed(index)
+	}
+	remaining := sm.unverified.Count()
+	sm.mu.Unlock()
+
+	finished := remaining == 0 && d.seedMode.CompareAndSwap(sm, nil)
+	if finished {
+		d.log.Info().Msg("seed mode verification finished")
+	}
+	if finished || !ok {
+		d.saveResume()
+	}
+	if !ok {
+		return errSeedPieceCorrupt
+	}
+	return nil
+}
+
+// seedPieceFailed unmarks a seed mode piece whose data does not match its
+// hash, so it is downloaded again. Caller must hold the seed mode lock.
+func (d *Download) seedPieceFailed(index uint32) {
+	d.log.Warn().Uint32("piece", index).Msg("piece failed seed mode hash check, downloading it again")
+
+	pieces := bm.New(d.info.NumPieces)
+	pieces.Set(index)
+	d.transitionMu.Lock()
+	d.dropCompletedPieces(pieces)
+	d.transitionMu.Unlock()
+}
+
+// seedModeChecked drops the pieces a hash check just verified from seed
+// mode, all of them when pieces is nil.
+func (d *Download) seedModeChecked(pieces *bm.Bitmap) {
+	sm := d.seedMode.Load()
+	if sm == nil {
+		return
+	}
+
+	if pieces != nil {
+		sm.mu.Lock()
+		sm.unverified.AndNot(pieces)
+		remaining := sm.unverified.Count()
+		sm.mu.Unlock()
+		if remaining != 0 {
+			return
+		}
+	}
+	d.seedMode.CompareAndSwap(sm, nil)
+}
+
+// verifySeedModeLoop hash checks the seed mode pieces no peer asked for yet,
+// one at a time. It waits while the download is inactive or any regular hash
+// check is queued or running, and shares the recheck speed limit.
+func (d *Download) verifySeedModeLoop() {
+	idle := time.NewTicker(seedModeIdleInterval)
+	defer idle.Stop()
+
+	for {
+		sm := d.seedMode.Load()
+		if sm == nil {
+			return
+		}
+		if !d.IsActive() || d.session.HashCheck.Busy() {
+			select {
+			case <-d.ctx.Done():
+				return
+			case <-idle.C:
+			}
+			continue
+		}
+
+		index, ok := sm.next(d.info.NumPieces)
+		if !ok {
+			// the remaining pieces are being checked for uploads
+			return
+		}
+		if err := d.session.HashCheck.WaitBandwidth(d.ctx, int(d.info.PieceLen(index))); err != nil {
+			return
+		}
+		err := d.verifySeedPiece(d.ctx, index)
+		if err == nil || errors.Is(err, errSeedPieceCorrupt) {
+			continue
+		}
+		if d.ctx.Err() != nil {
+			return
+		}
+		d.log.Err(err).Uint32("piece", index).Msg("seed mode verification failed")
+		d.setError(err)
+		return
+	}
+}
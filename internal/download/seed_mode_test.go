@@ -0,0 +1,93 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package download
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/pkg/bm"
+	"neptune/internal/session/store"
+)
+
+// writeCorruptPiece rewrites the data file with the right size and a wrong
+// byte in piece index.
+func (f resumeTestFixture) writeCorruptPiece(t *testing.T, index uint32) {
+	t.Helper()
+	path := filepath.Join(f.basePath, f.info.Files[0].Path)
+	data := make([]byte, f.info.TotalLength)
+	data[int64(index)*f.info.PieceLength] = 1
+	require.NoError(t, os.WriteFile(path, data, 0o644))
+}
+
+func TestSkipHashCheckVerifiesPiecesInBackground(t *testing.T) {
+	f := newResumeTestFixture(t, 3)
+	f.writeDataFile(t)
+	f.writeCorruptPiece(t, 1)
+
+	d, err := New(f.sess, f.metainfo, f.info, f.basePath, nil, nil, nil, InitState{
+		State:         Checking,
+		SkipHashCheck: true,
+	})
+	require.NoError(t, err)
+	t.Cleanup(d.Close)
+
+	require.Eventually(t, func() bool {
+		return d.seedMode.Load() == nil && d.GetState() == Downloading
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, []uint32{0, 2}, d.completedBm.ToArray())
+	require.Equal(t, []uint32{1}, d.missingBm.ToArray())
+	require.NotNil(t, d.picker.Load())
+	require.Nil(t, d.resumeRecord().Unverified)
+}
+
+func TestSeedModeVerifiesPieceBeforeUpload(t *testing.T) {
+	f := newResumeTestFixture(t, 2)
+	f.writeDataFile(t)
+	f.writeCorruptPiece(t, 1)
+
+	// Stopped keeps background verification idle.
+	all := bm.New(f.info.NumPieces)
+	all.Fill()
+	r := f.resumeData(t, store.ResumeStopped, 0, 1)
+	r.Unverified = all.Bitfield()
+	d := f.load(t, r)
+	require.Equal(t, uint32(2), d.Info(nil).UnverifiedPieces)
+
+	require.NoError(t, d.verifySeedPiece(context.Background(), 0))
+	require.Equal(t, uint32(1), d.seedMode.Load().count())
+	require.Equal(t, []byte{0b0100_0000}, d.resumeRecord().Unverified)
+
+	require.ErrorIs(t, d.verifySeedPiece(context.Background(), 1), errSeedPieceCorrupt)
+	require.Nil(t, d.seedMode.Load(), "every piece has been checked")
+	require.Equal(t, []uint32{0}, d.completedBm.ToArray())
+	require.Equal(t, []uint32{1}, d.missingBm.ToArray())
+	require.Equal(t, f.info.PieceLength, d.completed.Load(), "the counter follows completedBm")
+	require.Equal(t, Stopped, d.GetState())
+}
+
+func TestLoadFromResumeTrustsOnlyCompletedUnverifiedPieces(t *testing.T) {
+	f := newResumeTestFixture(t, 3)
+	f.writeDataFile(t)
+
+	unverified := bm.New(f.info.NumPieces)
+	unverified.Set(1)
+	unverified.Set(2)
+	r := f.resumeData(t, store.ResumeStopped, 0, 1)
+	r.Unverified = unverified.Bitfield()
+	d := f.load(t, r)
+
+	require.Equal(t, []uint32{1}, d.seedMode.Load().unverified.ToArray())
+
+	r.Unverified = []byte{0xff, 0xff}
+	d = f.load(t, r)
+	require.Equal(t, []uint32{0, 1}, d.seedMode.Load().unverified.ToArray(), "a malformed record trusts no completed piece")
+}
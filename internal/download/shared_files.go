@@ -0,0 +1,93 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package download
+
+import (
+	"path/filepath"
+
+	"neptune/internal/meta"
+	"neptune/internal/metainfo"
+)
+
+// releaseSharedFiles runs after a hash check. A shared file whose pieces
+// verified keeps reading the existing data, it is complete and never written.
+// Any other one gets its own copy at the path the torrent gives it, to
+// download the rest into. Its pieces are dropped when the file system can not
+// clone the data.
+func (d *Download) releaseSharedFiles() {
+	var original []meta.File
+	released := false
+	for _, i := range d.sharedFiles.ToArray() {
+		index := int(i)
+		if d.sharedFileVerified(index) {
+			continue
+		}
+
+		// a remapped file goes back to its own path, a hardlink stays
+		path := d.info.Files[index].Path
+		if filepath.IsAbs(path) {
+			if original == nil {
+				files, err := d.torrentFiles()
+				if err != nil {
+					d.log.Err(err).Msg("failed to load torrent file to release shared files")
+					return
+				}
+				original = files
+			}
+			path = original[index].Path
+		}
+
+		kept, err := d.store.UnshareFile(index, path)
+		if err != nil {
+			d.log.Err(err).Int("file", index).Msg("failed to release shared file")
+			continue
+		}
+		d.s.mu.Lock()
+		d.info.Files[index].SetPath(path)
+		d.s.mu.Unlock()
+		if !kept {
+			start, end := d.info.FilePieces(index)
+			for piece := start; piece < end; piece++ {
+				d.completedBm.Unset(piece)
+			}
+		}
+		released = true
+		d.log.Info().Int("file", index).Bool("cloned", kept).Msg("shared file did not verify, downloading it separately")
+	}
+
+	if released {
+		d.indexPaths()
+		d.saveResume()
+	}
+}
+
+// sharedFileVerified reports whether the pieces holding only data of file
+// index all verified. A file too small to hold a whole piece needs every
+// piece it is part of.
+func (d *Download) sharedFileVerified(index int) bool {
+	start, end := d.info.ContainedPieces(index)
+	if start == end {
+		start, end = d.info.FilePieces(index)
+	}
+	for piece := start; piece < end; piece++ {
+		if !d.completedBm.Contains(piece) {
+			return false
+		}
+	}
+	return true
+}
+
+// torrentFiles returns the files as the torrent file lists them, before any
+// rename or remap.
+func (d *Download) torrentFiles() ([]meta.File, error) {
+	m, err := metainfo.LoadFromFile(d.TorrentFilePath())
+	if err != nil {
+		return nil, err
+	}
+	info, err := meta.FromTorrent(*m)
+	if err != nil {
+		return nil, err
+	}
+	return info.Files, nil
+}
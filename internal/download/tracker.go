@@ -28,6 +28,17 @@ func (d *Download) RemoveTracker(url string) {
 	d.tracker.Remove(url)
 }
 
+// AnnounceList returns the current tracker URLs by tier, without empty tiers.
+func (d *Download) AnnounceList() metainfo.AnnounceList {
+	var list metainfo.AnnounceList
+	for _, tier := range d.tracker.URLs() {
+		if len(tier) != 0 {
+			list = append(list, tier)
+		}
+	}
+	return list
+}
+
 // ReplaceTrackers replaces tracker URLs matching keys with their mapped values.
 func (d *Download) ReplaceTrackers(replacements map[string]string) {
 	d.tracker.Replace(replacements)
@@ -46,6 +57,11 @@ func (d *Download) Close() {
 	d.tracker.Shutdown()
 	d.BackgroundWgWait()
 	d.CloseAllPeers()
+	// Record the stats after the last write, so a clean shutdown is not
+	// mistaken for changed files on the next start.
+	if !d.HasState(Stopped) {
+		d.refreshFileStats()
+	}
 }
 
 func (d *Download) setAnnounceList(list metainfo.AnnounceList) {
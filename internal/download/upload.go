@@ -341,10 +341,14 @@ func (d *Download) processUpload(peer Peer, req proto.ChunkRequest) {
 	res.Data = slices.Grow(res.Data[:0], int(req.Length))[:req.Length]
 	if err := d.readPieceRangeCtx(d.ctx, req, res.Data); err != nil {
 		proto.PiecePool.Put(res)
-		if err == errUploadPaused || err == context.Canceled {
+		if errors.Is(err, errUploadPaused) || errors.Is(err, context.Canceled) {
 			peer.RestorePeerRequest(req)
 			return
 		}
+		if errors.Is(err, errSeedPieceCorrupt) || errors.Is(err, errPieceDataMissing) {
+			peer.CancelPeerRequest(req)
+			return
+		}
 		peer.CancelPeerRequest(req)
 		d.setError(err)
 		peer.Close()
@@ -377,7 +381,18 @@ func (d *Download) readPieceRangeCtx(ctx context.Context, req proto.ChunkRequest
 	if !d.HasState(Downloading | Seeding) {
 		return errUploadPaused
 	}
+	// A piece lost after the peer saw our bitfield is not served.
+	if !d.completedBm.Contains(req.PieceIndex) {
+		return errPieceDataMissing
+	}
+	if err := d.verifySeedPiece(ctx, req.PieceIndex); err != nil {
+		return err
+	}
 
 	_, err := d.store.ReadChunk(ctx, req.PieceIndex, req.Begin, dst)
+	if isMissingData(err) {
+		d.signalMissingData()
+		return errPieceDataMissing
+	}
 	return err
 }
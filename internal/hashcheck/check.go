@@ -0,0 +1,328 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package hashcheck
+
+import (
+	"context"
+	"crypto/sha1"
+	"sync"
+	"time"
+
+	"go.uber.org/atomic"
+	"golang.org/x/sync/semaphore"
+
+	"neptune/internal/metainfo"
+	"neptune/internal/pkg/bm"
+	"neptune/internal/pkg/disk_io"
+	"neptune/internal/pkg/mempool"
+)
+
+const (
+	hddReaders   = 1
+	hddReadAhead = 32 << 20
+
+	ssdReaders   = 4
+	ssdReadAhead = 64 << 20
+)
+
+var bufferPool mempool.Pool
+
+type Status uint8
+
+const (
+	Queued Status = iota
+	Hashing
+)
+
+func (s Status) String() string {
+	if s == Hashing {
+		return "hashing"
+	}
+	return "queued"
+}
+
+// Piece is one piece to verify.
+type Piece struct {
+	Length int64
+	Index  uint32
+	Hash   [sha1.Size]byte
+}
+
+// Reader reads whole pieces from storage. Each read-ahead goroutine of a
+// check gets its own Reader, so implementations may cache open files.
+type Reader interface {
+	ReadPiece(ctx context.Context, index uint32, buf []byte) error
+	Close() error
+}
+
+// Progress is a snapshot of one queued or running check.
+type Progress struct {
+	Queued      time.Time
+	Started     time.Time
+	Device      string
+	DeviceClass string
+	// ETA is the estimated remaining time, or -1 if unknown.
+	ETA        time.Duration
+	BytesDone  int64
+	BytesTotal int64
+	// Rate is the average hashing speed in bytes per second.
+	Rate     int64
+	Position int
+	Status   Status
+	InfoHash metainfo.Hash
+}
+
+// Check is one torrent's pass through the scheduler.
+type Check struct {
+	queued   time.Time
+	ctx      context.Context
+	s        *Scheduler
+	admitted chan struct{}
+	cancel   context.CancelCauseFunc
+	started  atomic.Int64
+	total    atomic.Int64
+	done     atomic.Int64
+	once     sync.Once
+	device   disk_io.DeviceID
+	hash     metainfo.Hash
+	class    disk_io.DeviceClass
+	status   Status
+}
+
+func newCheck(s *Scheduler, hash metainfo.Hash) *Check {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	return &Check{
+		s:        s,
+		ctx:      ctx,
+		cancel:   cancel,
+		hash:     hash,
+		queued:   time.Now(),
+		admitted: make(chan struct{}),
+		status:   Queued,
+	}
+}
+
+// Wait blocks until the check is admitted to its device.
+func (c *Check) Wait(ctx context.Context) error {
+	if c.s == nil {
+		return ctx.Err()
+	}
+	select {
+	case <-c.admitted:
+		return context.Cause(c.ctx)
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.ctx.Done():
+		return context.Cause(c.ctx)
+	case <-c.s.ctx.Done():
+		return ErrClosed
+	}
+}
+
+// Done releases the device slot. It is safe to call more than once.
+func (c *Check) Done() {
+	c.once.Do(func() {
+		c.cancel(nil)
+		if c.s != nil {
+			c.s.release(c)
+		}
+	})
+}
+
+// Run reads and hashes pieces, returning a bitmap of size numPieces with the
+// pieces whose digest matched. Pieces are read in the given order.
+func (c *Check) Run(ctx context.Context, numPieces uint32, pieces []Piece, newReader func() Reader) (*bm.Bitmap, error) {
+	var (
+		mu       sync.Mutex
+		verified = bm.New(numPieces)
+	)
+	err := c.run(ctx, pieces, newReader, func(p Piece, digest [sha1.Size]byte) {
+		if digest == p.Hash {
+			mu.Lock()
+			verified.Set(p.Index)
+			mu.Unlock()
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return verified, nil
+}
+
+// Hash reads and hashes pieces, returning the digests of size numPieces by
+// piece index. The Hash of each piece is ignored. It computes the pieces of a
+// torrent being created.
+func (c *Check) Hash(ctx context.Context, numPieces uint32, pieces []Piece, newReader func() Reader) ([]metainfo.Hash, error) {
+	digests := make([]metainfo.Hash, numPieces)
+	err := c.run(ctx, pieces, newReader, func(p Piece, digest [sha1.Size]byte) {
+		digests[p.Index] = digest
+	})
+	if err != nil {
+		return nil, err
+	}
+	return digests, nil
+}
+
+// run reads and hashes pieces, calling finish from the hash workers with the
+// digest of each.
+func (c *Check) run(ctx context.Context, pieces []Piece, newReader func() Reader, finish func(Piece, [sha1.Size]byte)) error {
+	var total int64
+	for _, p := range pieces {
+		total += p.Length
+	}
+	c.total.Store(total)
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	stop := context.AfterFunc(c.ctx, func() { cancel(context.Cause(c.ctx)) })
+	defer stop()
+
+	readers, readAhead := hddReaders, int64(hddReadAhead)
+	if c.class == disk_io.DeviceSSD {
+		readers, readAhead = ssdReaders, ssdReadAhead
+	}
+	if c.s == nil {
+		readers = 1
+	}
+	// read-ahead budget bounds buffered pieces waiting for a hash worker.
+	budget := semaphore.NewWeighted(readAhead)
+
+	var hashing sync.WaitGroup
+
+	next := make(chan Piece)
+	go func() {
+		defer close(next)
+		for _, p := range pieces {
+			select {
+			case next <- p:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range readers {
+		wg.Go(func() {
+			r := newReader()
+			defer r.Close()
+
+			for p := range next {
+				if err := c.s.waitResumed(ctx); err != nil {
+					return
+				}
+				weight := min(p.Length, readAhead)
+				if err := budget.Acquire(ctx, weight); err != nil {
+					return
+				}
+
+				buf := mempool.GetWithCapFromPool(&bufferPool, int(p.Length))
+				err := c.s.WaitBandwidth(ctx, int(p.Length))
+				if err == nil {
+					err = r.ReadPiece(ctx, p.Index, buf.B)
+				}
+				if err != nil {
+					bufferPool.Put(buf)
+					budget.Release(weight)
+					cancel(err)
+					return
+				}
+
+				hashing.Add(1)
+				job := hashJob{data: buf.B, done: func(digest [sha1.Size]byte) {
+					finish(p, digest)
+					c.done.Add(p.Length)
+					bufferPool.Put(buf)
+					budget.Release(weight)
+					hashing.Done()
+				}}
+				if !c.s.submit(ctx, job) {
+					bufferPool.Put(buf)
+					budget.Release(weight)
+					hashing.Done()
+					if c.s != nil && c.s.ctx.Err() != nil {
+						cancel(ErrClosed)
+					}
+					return
+				}
+			}
+		})
+	}
+
+	wg.Wait()
+	hashing.Wait()
+
+	return context.Cause(ctx)
+}
+
+func (c *Check) progressLocked(now time.Time, position int) Progress {
+	p := Progress{
+		InfoHash:    c.hash,
+		Device:      c.device.String(),
+		DeviceClass: c.class.String(),
+		Status:      c.status,
+		Position:    position,
+		Queued:      c.queued,
+		BytesDone:   c.done.Load(),
+		BytesTotal:  c.total.Load(),
+		ETA:         -1,
+	}
+	if c.device == (disk_io.DeviceID{}) {
+		p.Device = "default"
+	}
+	if c.status != Hashing {
+		return p
+	}
+
+	p.Started = time.Unix(0, c.started.Load())
+	elapsed := now.Sub(p.Started)
+	if elapsed > 0 && p.BytesDone > 0 {
+		p.Rate = int64(float64(p.BytesDone) / elapsed.Seconds())
+		if p.Rate > 0 {
+			p.ETA = time.Duration(float64(p.BytesTotal-p.BytesDone) / float64(p.Rate) * float64(time.Second))
+		}
+	}
+	return p
+}
+
+type hashJob struct {
+	done func([sha1.Size]byte)
+	data []byte
+}
+
+func (s *Scheduler) runWorker() {
+	for {
+		select {
+		case <-s.ctx.Done():
+			return
+		case j := <-s.jobs:
+			j.done(sha1.Sum(j.data))
+		}
+	}
+}
+
+// submit hands a job to the worker pool, or hashes inline on a nil Scheduler.
+func (s *Scheduler) submit(ctx context.Context, j hashJob) bool {
+	if s == nil {
+		j.done(sha1.Sum(j.data))
+		return true
+	}
+	select {
+	case s.jobs <- j:
+		return true
+	case <-ctx.Done():
+		return false
+	case <-s.ctx.Done():
+		return false
+	}
+}
+
+// WaitBandwidth blocks until n bytes may be read under the shared speed
+// limit. It lets verification outside a Check share the limit.
+func (s *Scheduler) WaitBandwidth(ctx context.Context, n int) error {
+	if s == nil {
+		return nil
+	}
+	return s.limiter.Wait(ctx, n)
+}
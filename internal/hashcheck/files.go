@@ -0,0 +1,86 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package hashcheck
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/trim21/errgo"
+
+	"neptune/internal/meta"
+	"neptune/internal/pkg/fadvise"
+	"neptune/internal/pkg/gfs"
+)
+
+// FileReader reads the pieces of a torrent from its files, keeping the last
+// opened file so sequential pieces of one file reuse the same handle.
+type FileReader struct {
+	info *meta.Info
+	// path returns where file index is stored.
+	path func(index int) string
+	// io queues the reads on the disk of the files, nil reads directly.
+	io               *gfs.PathIO
+	currentFile      *os.File
+	currentFileIndex int
+}
+
+// NewFileReader returns a Reader of the pieces of info, opening the file at
+// path(index) for each file. Reads go through pio unless it is nil.
+func NewFileReader(info *meta.Info, path func(index int) string, pio *gfs.PathIO) *FileReader {
+	return &FileReader{info: info, path: path, io: pio, currentFileIndex: -1}
+}
+
+func (r *FileReader) ReadPiece(ctx context.Context, pieceIndex uint32, buf []byte) error {
+	var off int64
+	for chunk := range r.info.PieceFileChunks(pieceIndex) {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if chunk.Length == 0 {
+			continue
+		}
+
+		if chunk.FileIndex != r.currentFileIndex {
+			_ = r.Close()
+			p := r.path(chunk.FileIndex)
+			f, err := os.Open(p)
+			if err != nil {
+				return errgo.Wrap(err, fmt.Sprintf("failed to open file %q", p))
+			}
+			_ = fadvise.Sequential(f, 0, 0)
+			r.currentFile = f
+			r.currentFileIndex = chunk.FileIndex
+		}
+
+		var n int
+		var err error
+		if r.io != nil {
+			n, err = r.io.ReadAtCtx(ctx, r.currentFile, buf[off:off+chunk.Length], chunk.OffsetOfFile)
+		} else {
+			n, err = r.currentFile.ReadAt(buf[off:off+chunk.Length], chunk.OffsetOfFile)
+		}
+		if int64(n) < chunk.Length {
+			// the file is shorter than the torrent says
+			if err == nil || err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return errgo.Wrap(err, "failed to read file "+r.currentFile.Name())
+		}
+		off += chunk.Length
+	}
+	return nil
+}
+
+func (r *FileReader) Close() error {
+	if r.currentFile == nil {
+		return nil
+	}
+	err := r.currentFile.Close()
+	r.currentFile = nil
+	r.currentFileIndex = -1
+	return err
+}
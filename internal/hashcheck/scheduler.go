@@ -0,0 +1,436 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+// Package hashcheck runs piece hash checks for all torrents of a session.
+//
+// Checks are admitted per storage device, so torrents on different disks are
+// verified concurrently while a single disk is not thrashed by several
+// sequential readers. An admitted check reads pieces ahead with a profile
+// chosen by device class and hands them to a shared pool of SHA-1 workers.
+package hashcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"slices"
+	"sync"
+	"time"
+
+	"neptune/internal/metainfo"
+	"neptune/internal/pkg/disk_io"
+	"neptune/internal/pkg/ratelimit"
+)
+
+const (
+	hddChecks = 1
+	ssdChecks = 2
+)
+
+var (
+	// ErrClosed is returned when a check is queued or waits after Close.
+	ErrClosed = errors.New("hash check scheduler closed")
+	// ErrCanceled is returned by Wait and Run after Cancel.
+	ErrCanceled = errors.New("hash check canceled")
+)
+
+type Config struct {
+	// Workers is the number of SHA-1 goroutines, 0 means runtime.NumCPU().
+	Workers int
+	// PerDevice limits concurrent checks on one device, 0 picks a default by
+	// device class.
+	PerDevice int
+	// SpeedLimit caps the bytes per second read by all checks, 0 means unlimited.
+	SpeedLimit int64
+}
+
+// LocateFunc resolves the device a path is stored on.
+type LocateFunc func(path string) (disk_io.DeviceID, disk_io.DeviceClass)
+
+// Scheduler admits hash checks per device and owns the shared hash worker
+// pool and read bandwidth limiter. A nil *Scheduler admits every check
+// immediately and hashes inline, which keeps tests free of session wiring.
+type Scheduler struct {
+	ctx     context.Context
+	locate  LocateFunc
+	limiter *ratelimit.Limiter
+	jobs    chan hashJob
+	devices map[disk_io.DeviceID]*deviceQueue
+	checks  map[metainfo.Hash]*Check
+	// resume is non-nil while checking is paused and closed on Resume.
+	resume    chan struct{}
+	changed   chan struct{}
+	cancel    context.CancelFunc
+	workers   sync.WaitGroup
+	perDevice int
+	mu        sync.Mutex
+	closed    bool
+}
+
+type deviceQueue struct {
+	waiting []*Check
+	running []*Check
+	limit   int
+}
+
+func New(cfg Config, locate LocateFunc) *Scheduler {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Scheduler{
+		ctx:       ctx,
+		cancel:    cancel,
+		locate:    locate,
+		limiter:   ratelimit.New(cfg.SpeedLimit),
+		jobs:      make(chan hashJob),
+		devices:   make(map[disk_io.DeviceID]*deviceQueue),
+		checks:    make(map[metainfo.Hash]*Check),
+		changed:   make(chan struct{}, 1),
+		perDevice: cfg.PerDevice,
+	}
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	for range workers {
+		s.workers.Go(s.runWorker)
+	}
+	return s
+}
+
+// SetSpeedLimit changes the bytes per second shared by all checks, 0 means
+// unlimited.
+func (s *Scheduler) SetSpeedLimit(rate int64) {
+	s.limiter.Update(rate)
+}
+
+func (s *Scheduler) SpeedLimit() int64 {
+	return s.limiter.Rate()
+}
+
+// Close stops the hash workers. Queued checks fail with ErrClosed.
+func (s *Scheduler) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	s.cancel()
+	s.workers.Wait()
+}
+
+// Enqueue registers a check for the torrent stored under path. The check
+// waits in its device's queue until Wait returns; callers must call Done
+// when the check finishes, fails or is abandoned.
+func (s *Scheduler) Enqueue(hash metainfo.Hash, path string) (*Check, error) {
+	if s == nil {
+		c := newCheck(nil, hash)
+		c.status = Hashing
+		c.started.Store(time.Now().UnixNano())
+		close(c.admitted)
+		return c, nil
+	}
+
+	device, class := disk_io.DeviceID{}, disk_io.DeviceHDD
+	if s.locate != nil {
+		device, class = s.locate(path)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, ErrClosed
+	}
+	if _, exists := s.checks[hash]; exists {
+		return nil, fmt.Errorf("torrent %s is already queued for hash check", hash)
+	}
+
+	q, ok := s.devices[device]
+	if !ok {
+		q = &deviceQueue{limit: s.deviceLimit(class)}
+		s.devices[device] = q
+	}
+
+	c := newCheck(s, hash)
+	c.device = device
+	c.class = class
+	s.checks[hash] = c
+	q.waiting = append(q.waiting, c)
+	s.admitLocked(q)
+	s.notifyChanged()
+	return c, nil
+}
+
+func (s *Scheduler) deviceLimit(class disk_io.DeviceClass) int {
+	if s.perDevice > 0 {
+		return s.perDevice
+	}
+	if class == disk_io.DeviceSSD {
+		return ssdChecks
+	}
+	return hddChecks
+}
+
+// admitLocked starts waiting checks while the device has free slots.
+// Caller must hold s.mu.
+func (s *Scheduler) admitLocked(q *deviceQueue) {
+	if s.resume != nil {
+		return
+	}
+	for len(q.running) < q.limit && len(q.waiting) > 0 {
+		c := q.waiting[0]
+		q.waiting = slices.Delete(q.waiting, 0, 1)
+		q.running = append(q.running, c)
+		c.status = Hashing
+		c.started.Store(time.Now().UnixNano())
+		close(c.admitted)
+	}
+}
+
+// release removes c from its device queue and admits the next check.
+func (s *Scheduler) release(c *Check) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.checks[c.hash] == c {
+		delete(s.checks, c.hash)
+	}
+	q := s.devices[c.device]
+	if q == nil {
+		return
+	}
+	q.waiting = slices.DeleteFunc(q.waiting, func(w *Check) bool { return w == c })
+	q.running = slices.DeleteFunc(q.running, func(r *Check) bool { return r == c })
+	s.admitLocked(q)
+	if len(q.waiting) == 0 && len(q.running) == 0 {
+		delete(s.devices, c.device)
+	}
+	s.notifyChanged()
+}
+
+// Cancel aborts the queued or running check for hash. The owner of the check
+// sees ErrCanceled from Wait or Run.
+func (s *Scheduler) Cancel(hash metainfo.Hash) error {
+	if s == nil {
+		return fmt.Errorf("torrent %s has no hash check", hash)
+	}
+
+	s.mu.Lock()
+	c, ok := s.checks[hash]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("torrent %s has no hash check", hash)
+	}
+	c.cancel(ErrCanceled)
+	return nil
+}
+
+// MoveToTop makes a queued check the next one admitted on its device.
+func (s *Scheduler) MoveToTop(hash metainfo.Hash) error {
+	return s.move(hash, func(q *deviceQueue, c *Check) {
+		q.waiting = slices.Insert(q.waiting, 0, c)
+	})
+}
+
+// MoveToBottom makes a queued check the last one admitted on its device.
+func (s *Scheduler) MoveToBottom(hash metainfo.Hash) error {
+	return s.move(hash, func(q *deviceQueue, c *Check) {
+		q.waiting = append(q.waiting, c)
+	})
+}
+
+func (s *Scheduler) move(hash metainfo.Hash, insert func(q *deviceQueue, c *Check)) error {
+	if s == nil {
+		return fmt.Errorf("torrent %s is not queued for hash check", hash)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.checks[hash]
+	if !ok {
+		return fmt.Errorf("torrent %s is not queued for hash check", hash)
+	}
+	if c.status != Queued {
+		return fmt.Errorf("torrent %s is already being hashed", hash)
+	}
+	q := s.devices[c.device]
+	q.waiting = slices.DeleteFunc(q.waiting, func(w *Check) bool { return w == c })
+	insert(q, c)
+	s.notifyChanged()
+	return nil
+}
+
+// Pause stops admitting queued checks and suspends running checks before
+// their next piece read.
+func (s *Scheduler) Pause() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.resume == nil {
+		s.resume = make(chan struct{})
+	}
+}
+
+// Resume undoes Pause.
+func (s *Scheduler) Resume() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.resume == nil {
+		return
+	}
+	close(s.resume)
+	s.resume = nil
+	for _, q := range s.devices {
+		s.admitLocked(q)
+	}
+}
+
+func (s *Scheduler) Paused() bool {
+	if s == nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resume != nil
+}
+
+// waitResumed blocks while checking is paused.
+func (s *Scheduler) waitResumed(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	resume := s.resume
+	s.mu.Unlock()
+	if resume == nil {
+		return nil
+	}
+
+	select {
+	case <-resume:
+		return nil
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	}
+}
+
+// Changed is signaled whenever the set or order of checks changes, so the
+// queue can be persisted.
+func (s *Scheduler) Changed() <-chan struct{} {
+	if s == nil {
+		return nil
+	}
+	return s.changed
+}
+
+func (s *Scheduler) notifyChanged() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+// Hashes returns the torrents with a check, running checks first and then
+// each device queue in order.
+func (s *Scheduler) Hashes() []metainfo.Hash {
+	checks := s.Checks()
+	r := make([]metainfo.Hash, len(checks))
+	for i, c := range checks {
+		r[i] = c.InfoHash
+	}
+	return r
+}
+
+// Checks returns a progress snapshot of every queued or running check,
+// grouped by device with running checks first and waiting checks in queue
+// order.
+func (s *Scheduler) Checks() []Progress {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	devices := make([]disk_io.DeviceID, 0, len(s.devices))
+	for id := range s.devices {
+		devices = append(devices, id)
+	}
+	slices.SortFunc(devices, func(a, b disk_io.DeviceID) int {
+		if a.Major != b.Major {
+			return int(a.Major) - int(b.Major)
+		}
+		return int(a.Minor) - int(b.Minor)
+	})
+
+	now := time.Now()
+	var r = make([]Progress, 0, len(s.checks))
+	for _, id := range devices {
+		q := s.devices[id]
+		for _, c := range q.running {
+			r = append(r, c.progressLocked(now, 0))
+		}
+		for i, c := range q.waiting {
+			r = append(r, c.progressLocked(now, i+1))
+		}
+	}
+	return r
+}
+
+// Get returns the progress of the check for hash, if any.
+func (s *Scheduler) Get(hash metainfo.Hash) (Progress, bool) {
+	if s == nil {
+		return Progress{}, false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.checks[hash]
+	if !ok {
+		return Progress{}, false
+	}
+	position := 0
+	if q := s.devices[c.device]; q != nil {
+		if i := slices.Index(q.waiting, c); i >= 0 {
+			position = i + 1
+		}
+	}
+	return c.progressLocked(time.Now(), position), true
+}
+
+// Busy reports whether any check is queued or running, or checking is
+// paused. Low priority verification waits while it is.
+func (s *Scheduler) Busy() bool {
+	if s == nil {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.checks) > 0 || s.resume != nil
+}
+
+// QueueLen returns the number of checks waiting for a device slot.
+func (s *Scheduler) QueueLen() int {
+	if s == nil {
+		return 0
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var n int
+	for _, q := range s.devices {
+		n += len(q.waiting)
+	}
+	return n
+}
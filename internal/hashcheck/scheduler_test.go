@@ -0,0 +1,203 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package hashcheck
+
+import (
+	"context"
+	"crypto/sha1"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/metainfo"
+	"neptune/internal/pkg/disk_io"
+)
+
+type memReader struct {
+	data        []byte
+	pieceLength int64
+}
+
+func (r memReader) ReadPiece(_ context.Context, index uint32, buf []byte) error {
+	copy(buf, r.data[int64(index)*r.pieceLength:])
+	return nil
+}
+
+func (r memReader) Close() error { return nil }
+
+func testLocate(devices map[string]disk_io.DeviceID) LocateFunc {
+	return func(path string) (disk_io.DeviceID, disk_io.DeviceClass) {
+		return devices[path], disk_io.DeviceHDD
+	}
+}
+
+func TestSchedulerLimitsChecksPerDevice(t *testing.T) {
+	s := New(Config{Workers: 1}, testLocate(map[string]disk_io.DeviceID{
+		"/a": {Major: 8, Minor: 1},
+		"/b": {Major: 8, Minor: 1},
+		"/c": {Major: 8, Minor: 17},
+	}))
+	t.Cleanup(s.Close)
+
+	first, err := s.Enqueue(metainfo.Hash{1}, "/a")
+	require.NoError(t, err)
+	second, err := s.Enqueue(metainfo.Hash{2}, "/b")
+	require.NoError(t, err)
+	other, err := s.Enqueue(metainfo.Hash{3}, "/c")
+	require.NoError(t, err)
+
+	require.NoError(t, first.Wait(context.Background()))
+	require.NoError(t, other.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, second.Wait(ctx), context.DeadlineExceeded)
+
+	p, ok := s.Get(metainfo.Hash{2})
+	require.True(t, ok)
+	require.Equal(t, Queued, p.Status)
+	require.Equal(t, 1, p.Position)
+	require.Equal(t, 1, s.QueueLen())
+
+	first.Done()
+	require.NoError(t, second.Wait(context.Background()))
+	require.Equal(t, 0, s.QueueLen())
+
+	_, err = s.Enqueue(metainfo.Hash{2}, "/b")
+	require.Error(t, err, "a torrent can only be queued once")
+
+	second.Done()
+	other.Done()
+	require.Empty(t, s.Checks())
+}
+
+func TestCheckRunVerifiesPieces(t *testing.T) {
+	const pieceLength = 1024
+	data := make([]byte, pieceLength*4)
+	for i := range data {
+		data[i] = byte(i / 7)
+	}
+
+	pieces := make([]Piece, 4)
+	for i := range pieces {
+		pieces[i] = Piece{
+			Index:  uint32(i),
+			Length: pieceLength,
+			Hash:   sha1.Sum(data[i*pieceLength : (i+1)*pieceLength]),
+		}
+	}
+	pieces[2].Hash = [sha1.Size]byte{}
+
+	for name, s := range map[string]*Scheduler{
+		"scheduler": New(Config{Workers: 2}, nil),
+		"nil":       nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if s != nil {
+				t.Cleanup(s.Close)
+			}
+			c, err := s.Enqueue(metainfo.Hash{1}, "")
+			require.NoError(t, err)
+			defer c.Done()
+			require.NoError(t, c.Wait(context.Background()))
+
+			verified, err := c.Run(context.Background(), 4, pieces, func() Reader {
+				return memReader{data: data, pieceLength: pieceLength}
+			})
+			require.NoError(t, err)
+			require.Equal(t, []uint32{0, 1, 3}, verified.ToArray())
+			require.Equal(t, int64(len(data)), c.done.Load())
+		})
+	}
+}
+
+type failReader struct{ err error }
+
+func (r failReader) ReadPiece(context.Context, uint32, []byte) error { return r.err }
+func (r failReader) Close() error                                    { return nil }
+
+func TestCheckRunReturnsReadError(t *testing.T) {
+	s := New(Config{Workers: 1}, nil)
+	t.Cleanup(s.Close)
+
+	c, err := s.Enqueue(metainfo.Hash{1}, "")
+	require.NoError(t, err)
+	defer c.Done()
+	require.NoError(t, c.Wait(context.Background()))
+
+	readErr := errors.New("boom")
+	_, err = c.Run(context.Background(), 2, []Piece{{Index: 0, Length: 16}, {Index: 1, Length: 16}}, func() Reader {
+		return failReader{err: readErr}
+	})
+	require.ErrorIs(t, err, readErr)
+}
+
+func TestSchedulerMoveQueuedCheck(t *testing.T) {
+	s := New(Config{Workers: 1}, nil)
+	t.Cleanup(s.Close)
+
+	running, err := s.Enqueue(metainfo.Hash{1}, "")
+	require.NoError(t, err)
+	defer running.Done()
+	for _, h := range []metainfo.Hash{{2}, {3}, {4}} {
+		c, err := s.Enqueue(h, "")
+		require.NoError(t, err)
+		defer c.Done()
+	}
+
+	require.Error(t, s.MoveToTop(metainfo.Hash{1}), "a running check can not be moved")
+	require.NoError(t, s.MoveToTop(metainfo.Hash{4}))
+	require.NoError(t, s.MoveToBottom(metainfo.Hash{2}))
+	require.Equal(t, []metainfo.Hash{{1}, {4}, {3}, {2}}, s.Hashes())
+}
+
+func TestSchedulerCancel(t *testing.T) {
+	s := New(Config{Workers: 1}, nil)
+	t.Cleanup(s.Close)
+
+	running, err := s.Enqueue(metainfo.Hash{1}, "")
+	require.NoError(t, err)
+	queued, err := s.Enqueue(metainfo.Hash{2}, "")
+	require.NoError(t, err)
+	require.True(t, s.Busy())
+
+	require.NoError(t, s.Cancel(metainfo.Hash{2}))
+	require.ErrorIs(t, queued.Wait(context.Background()), ErrCanceled)
+	queued.Done()
+
+	require.NoError(t, running.Wait(context.Background()))
+	require.NoError(t, s.Cancel(metainfo.Hash{1}))
+	_, err = running.Run(context.Background(), 1, []Piece{{Index: 0, Length: 16}}, func() Reader {
+		return memReader{data: make([]byte, 16), pieceLength: 16}
+	})
+	require.ErrorIs(t, err, ErrCanceled)
+	running.Done()
+
+	require.Empty(t, s.Checks())
+	require.False(t, s.Busy())
+	require.Error(t, s.Cancel(metainfo.Hash{1}))
+}
+
+func TestSchedulerPause(t *testing.T) {
+	s := New(Config{Workers: 1}, nil)
+	t.Cleanup(s.Close)
+
+	s.Pause()
+	require.True(t, s.Paused())
+	require.True(t, s.Busy(), "paused checking holds back low priority verification")
+
+	c, err := s.Enqueue(metainfo.Hash{1}, "")
+	require.NoError(t, err)
+	defer c.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, c.Wait(ctx), context.DeadlineExceeded)
+
+	s.Resume()
+	require.False(t, s.Paused())
+	require.NoError(t, c.Wait(context.Background()))
+}
@@ -25,12 +25,19 @@ type File struct {
 type Info struct {
 	Name          string
 	Comment       string
+	CreatedBy     string
+	Encoding      string
+	Source        string
 	Pieces        []metainfo.Hash
 	Files         []File
+	WebSeeds      []string
+	HTTPSeeds     []string
+	Nodes         []string
 	fileOffsets   []int64 // cumulative byte offsets, len(Files)+1
 	TotalLength   int64
 	PieceLength   int64
 	LastPieceSize int64
+	CreationDate  int64 // unix seconds, 0 when unknown
 	NumPieces     uint32
 	Hash          metainfo.Hash
 	Private       bool
@@ -80,6 +87,73 @@ func (info *Info) BlocksPerPiece() uint32 {
 	return uint32((info.PieceLength + DefaultBlockSize - 1) / DefaultBlockSize)
 }
 
+// FilePieces returns the range [start, end) of pieces covering file index.
+// It is empty for a zero length file.
+func (info *Info) FilePieces(index int) (start, end uint32) {
+	fileStart := info.fileOffsets[index]
+	fileEnd := info.fileOffsets[index+1]
+	if fileStart == fileEnd {
+		return 0, 0
+	}
+	return uint32(fileStart / info.PieceLength), uint32((fileEnd + info.PieceLength - 1) / info.PieceLength)
+}
+
+// ContainedPieces returns the range [start, end) of pieces holding only data
+// of file index. It is empty when every piece of the file has data of other
+// files too.
+func (info *Info) ContainedPieces(index int) (start, end uint32) {
+	fileStart := info.fileOffsets[index]
+	fileEnd := info.fileOffsets[index+1]
+	start = uint32((fileStart + info.PieceLength - 1) / info.PieceLength)
+	end = uint32(fileEnd / info.PieceLength)
+	if fileEnd == info.TotalLength {
+		end = info.NumPieces
+	}
+	if fileStart == fileEnd || end <= start {
+		return 0, 0
+	}
+	return start, end
+}
+
+// PieceOffsetInFile returns where piece index starts in file index. Only
+// meaningful for the pieces returned by ContainedPieces.
+func (info *Info) PieceOffsetInFile(index int, piece uint32) int64 {
+	return int64(piece)*info.PieceLength - info.fileOffsets[index]
+}
+
+// SameFileData reports whether file index of info and file other of b are
+// known to hold the same bytes: both start on a piece boundary, fill their
+// pieces alone, and the pieces have the same hashes. Files that share a piece
+// with other files can not be compared and are not the same.
+func (info *Info) SameFileData(index int, b *Info, other int) bool {
+	if info.Files[index].Length != b.Files[other].Length || info.PieceLength != b.PieceLength {
+		return false
+	}
+	if info.Files[index].Length == 0 {
+		return true
+	}
+	if !info.ownsPieces(index) || !b.ownsPieces(other) {
+		return false
+	}
+
+	start, end := info.FilePieces(index)
+	otherStart, _ := b.FilePieces(other)
+	for i := range end - start {
+		if info.Pieces[start+i] != b.Pieces[otherStart+i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ownsPieces reports whether no other file has data in the pieces of file
+// index.
+func (info *Info) ownsPieces(index int) bool {
+	fileStart := info.fileOffsets[index]
+	fileEnd := info.fileOffsets[index+1]
+	return fileStart%info.PieceLength == 0 && (fileEnd%info.PieceLength == 0 || fileEnd == info.TotalLength)
+}
+
 // FileChunks returns an iterator over contiguous byte ranges within [start, end).
 // Zero allocations; the FileChunkInfo struct is passed on the stack.
 func (info *Info) FileChunks(start, end int64) iter.Seq[FileChunkInfo] {
@@ -169,6 +243,15 @@ func FromTorrent(m metainfo.MetaInfo) (Info, error) {
 		LastPieceSize: info.TotalLength() - info.PieceLength*int64(info.NumPieces()-1),
 		Files:         files,
 		Comment:       m.Comment,
+		CreatedBy:     string(m.CreatedBy),
+		Encoding:      string(m.Encoding),
+		CreationDate:  int64(m.CreationDate),
+		Source:        info.Source,
+		WebSeeds:      m.UrlList,
+		HTTPSeeds:     m.HTTPSeeds,
+	}
+	for _, n := range m.Nodes {
+		i.Nodes = append(i.Nodes, string(n))
 	}
 
 	if int64(i.NumPieces) != (i.TotalLength+i.PieceLength-1)/i.PieceLength {
@@ -4,6 +4,8 @@
 package meta
 
 import (
+	"bytes"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -59,3 +61,63 @@ func TestFromTorrentAcceptsValidPieceLength(t *testing.T) {
 	require.Equal(t, uint32(1), info.NumPieces)
 	require.Equal(t, int64(100), info.LastPieceSize)
 }
+
+// buildMultiFileInfo parses a torrent with the given file lengths and one
+// piece hash per byte value in pieces.
+func buildMultiFileInfo(t *testing.T, pieceLength int64, pieces []byte, lengths ...int64) Info {
+	t.Helper()
+	files := make([]metainfo.FileInfo, len(lengths))
+	for i, l := range lengths {
+		files[i] = metainfo.FileInfo{Path: []string{fmt.Sprintf("f%d", i)}, Length: l}
+	}
+	hashes := make([]byte, 0, len(pieces)*20)
+	for _, p := range pieces {
+		hashes = append(hashes, bytes.Repeat([]byte{p}, 20)...)
+	}
+	infoBytes, err := bencode.Marshal(metainfo.Info{
+		Name:        "test",
+		PieceLength: pieceLength,
+		Files:       files,
+		Pieces:      hashes,
+	})
+	require.NoError(t, err)
+	info, err := FromTorrent(metainfo.MetaInfo{InfoBytes: infoBytes})
+	require.NoError(t, err)
+	return info
+}
+
+func TestSameFileData(t *testing.T) {
+	t.Parallel()
+
+	// f1 fills pieces 1 and 2 on its own in both torrents.
+	a := buildMultiFileInfo(t, 16, []byte{1, 2, 3, 4}, 16, 32, 10)
+	b := buildMultiFileInfo(t, 16, []byte{9, 2, 3}, 16, 32)
+	require.True(t, a.SameFileData(1, &b, 1))
+	require.False(t, a.SameFileData(0, &b, 0), "piece hashes differ")
+
+	// the last file of a torrent may end in a short piece
+	c := buildMultiFileInfo(t, 16, []byte{2, 3}, 20)
+	d := buildMultiFileInfo(t, 16, []byte{7, 2, 3}, 16, 20)
+	require.True(t, c.SameFileData(0, &d, 1))
+
+	// f1 shares its first piece with f0
+	e := buildMultiFileInfo(t, 16, []byte{1, 2, 3}, 8, 32)
+	f := buildMultiFileInfo(t, 16, []byte{1, 2, 3}, 8, 32)
+	require.False(t, e.SameFileData(1, &f, 1))
+}
+
+func TestContainedPieces(t *testing.T) {
+	t.Parallel()
+
+	info := buildMultiFileInfo(t, 16, []byte{1, 2, 3, 4, 5}, 8, 40, 4, 20)
+
+	start, end := info.ContainedPieces(1) // bytes [8, 48)
+	require.Equal(t, [2]uint32{1, 3}, [2]uint32{start, end})
+	require.Equal(t, int64(8), info.PieceOffsetInFile(1, 1))
+
+	start, end = info.ContainedPieces(2) // bytes [48, 52), shares piece 3
+	require.Equal(t, start, end)
+
+	start, end = info.ContainedPieces(3) // bytes [52, 72), ends the torrent
+	require.Equal(t, [2]uint32{4, 5}, [2]uint32{start, end})
+}
@@ -75,11 +75,25 @@ func RestoreFilePaths(files []File, persistedPaths []string) {
 		if i >= len(files) {
 			break
 		}
-		files[i].Path = p
-		files[i].RawPath = splitFilePath(p)
+		files[i].SetPath(p)
 	}
 }
 
+// SetPath changes where the file is stored and keeps RawPath in sync.
+func (f *File) SetPath(p string) {
+	f.Path = p
+	f.RawPath = splitFilePath(p)
+}
+
+// FullPath returns where the file is stored. Path is relative to basePath,
+// unless the file was remapped to an absolute path outside of it.
+func (f File) FullPath(basePath string) string {
+	if filepath.IsAbs(f.Path) {
+		return f.Path
+	}
+	return filepath.Join(basePath, f.Path)
+}
+
 // splitFilePath splits a file path into its components, handling the root path case.
 func splitFilePath(p string) []string {
 	if p == "" {
@@ -0,0 +1,54 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package metainfo
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/trim21/go-bencode"
+)
+
+// MagnetURI returns a BEP 9 magnet link with the display name and every
+// distinct tracker of list.
+func MagnetURI(ih Hash, name string, list AnnounceList) string {
+	var b strings.Builder
+	b.WriteString("magnet:?xt=urn:btih:")
+	b.WriteString(ih.Hex())
+	if name != "" {
+		b.WriteString("&dn=")
+		b.WriteString(url.QueryEscape(name))
+	}
+	for _, tr := range list.DistinctValues() {
+		b.WriteString("&tr=")
+		b.WriteString(url.QueryEscape(tr))
+	}
+	return b.String()
+}
+
+// SetAnnounceList rewrites the trackers of the bencoded torrent file raw,
+// keeping every other key as it is. The first tracker becomes announce, a
+// list without trackers removes both keys.
+func SetAnnounceList(raw []byte, list AnnounceList) ([]byte, error) {
+	var dict map[string]bencode.RawBytes
+	if err := bencode.Unmarshal(raw, &dict); err != nil {
+		return nil, err
+	}
+
+	delete(dict, "announce")
+	delete(dict, "announce-list")
+	if urls := list.DistinctValues(); len(urls) != 0 {
+		announce, err := bencode.Marshal(urls[0])
+		if err != nil {
+			return nil, err
+		}
+		announceList, err := bencode.Marshal(list)
+		if err != nil {
+			return nil, err
+		}
+		dict["announce"] = announce
+		dict["announce-list"] = announceList
+	}
+	return bencode.Marshal(dict)
+}
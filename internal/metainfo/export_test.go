@@ -0,0 +1,45 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package metainfo
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMagnetURI(t *testing.T) {
+	ih := Hash{0xab, 0xcd}
+	list := AnnounceList{{"https://a.example/announce?k=1"}, {"udp://b.example:80", "https://a.example/announce?k=1"}}
+	require.Equal(t,
+		"magnet:?xt=urn:btih:abcd000000000000000000000000000000000000&dn=a+b%26c"+
+			"&tr=https%3A%2F%2Fa.example%2Fannounce%3Fk%3D1&tr=udp%3A%2F%2Fb.example%3A80",
+		MagnetURI(ih, "a b&c", list))
+	require.Equal(t, "magnet:?xt=urn:btih:abcd000000000000000000000000000000000000", MagnetURI(ih, "", nil))
+}
+
+func TestSetAnnounceList(t *testing.T) {
+	raw, err := os.ReadFile("testdata/archlinux-2011.08.19-netinstall-i686.iso.torrent")
+	require.NoError(t, err)
+	before, err := Load(raw)
+	require.NoError(t, err)
+
+	list := AnnounceList{{"https://a.example/announce"}, {"udp://b.example:80"}}
+	out, err := SetAnnounceList(raw, list)
+	require.NoError(t, err)
+	after, err := Load(out)
+	require.NoError(t, err)
+	require.Equal(t, "https://a.example/announce", after.Announce)
+	require.Equal(t, list, after.AnnounceList)
+	require.Equal(t, before.Comment, after.Comment)
+	require.Equal(t, before.HashInfoBytes(), after.HashInfoBytes())
+
+	out, err = SetAnnounceList(raw, nil)
+	require.NoError(t, err)
+	after, err = Load(out)
+	require.NoError(t, err)
+	require.Empty(t, after.Announce)
+	require.Empty(t, after.AnnounceList)
+}
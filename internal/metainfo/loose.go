@@ -0,0 +1,54 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package metainfo
+
+import (
+	"github.com/trim21/go-bencode"
+)
+
+// LooseString is an informational string key that some torrent creators
+// write with another type. A value of the wrong type decodes as empty instead
+// of failing the whole torrent.
+type LooseString string
+
+func (s *LooseString) UnmarshalBencode(b []byte) error {
+	var v string
+	if err := bencode.Unmarshal(b, &v); err != nil {
+		*s = ""
+		return nil
+	}
+	*s = LooseString(v)
+	return nil
+}
+
+func (s LooseString) MarshalBencode() ([]byte, error) {
+	return bencode.Marshal(string(s))
+}
+
+func (s LooseString) IsZeroBencodeValue() bool {
+	return s == ""
+}
+
+// LooseInt is an informational integer key that some torrent creators write
+// with another type. A value of the wrong type decodes as 0 instead of
+// failing the whole torrent.
+type LooseInt int64
+
+func (i *LooseInt) UnmarshalBencode(b []byte) error {
+	var v int64
+	if err := bencode.Unmarshal(b, &v); err != nil {
+		*i = 0
+		return nil
+	}
+	*i = LooseInt(v)
+	return nil
+}
+
+func (i LooseInt) MarshalBencode() ([]byte, error) {
+	return bencode.Marshal(int64(i))
+}
+
+func (i LooseInt) IsZeroBencodeValue() bool {
+	return i == 0
+}
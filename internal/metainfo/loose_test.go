@@ -0,0 +1,28 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package metainfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/trim21/go-bencode"
+)
+
+func TestLooseKeys(t *testing.T) {
+	m, err := Load([]byte("d10:created by9:mktorrent13:creation datei1700000000e4:infod4:name1:aee"))
+	require.NoError(t, err)
+	require.Equal(t, LooseString("mktorrent"), m.CreatedBy)
+	require.Equal(t, LooseInt(1700000000), m.CreationDate)
+
+	// wrong types are dropped, the torrent still loads
+	m, err = Load([]byte("d10:created byi1e13:creation date10:2024-01-014:infod4:name1:aee"))
+	require.NoError(t, err)
+	require.Empty(t, m.CreatedBy)
+	require.Zero(t, m.CreationDate)
+
+	raw, err := bencode.Marshal(MetaInfo{CreatedBy: "x", CreationDate: 5})
+	require.NoError(t, err)
+	require.Equal(t, "d10:created by1:x13:creation datei5ee", string(raw))
+}
@@ -13,19 +13,22 @@ import (
 )
 
 type MetaInfo struct {
-	Announce string `bencode:"announce,omitempty"` // BEP 3
-	Comment  string `bencode:"comment,omitempty"`
+	Announce  string      `bencode:"announce,omitempty"` // BEP 3
+	Comment   string      `bencode:"comment,omitempty"`
+	CreatedBy LooseString `bencode:"created by,omitempty"`
+	Encoding  LooseString `bencode:"encoding,omitempty"`
 
-	// CreatedBy    string        `bencode:"created by,omitempty"`
-	// Encoding     string        `bencode:"encoding,omitempty"`
-	// UrlList UrlList `bencode:"url-list,omitempty"` // BEP 19 WebSeeds
+	UrlList   UrlList `bencode:"url-list,omitempty"`  // BEP 19 WebSeeds
+	HTTPSeeds UrlList `bencode:"httpseeds,omitempty"` // BEP 17
+	Nodes     Nodes   `bencode:"nodes,omitempty"`     // BEP 5
+
+	InfoBytes    bencode.RawBytes `bencode:"info,omitempty"`          // BEP 3
+	AnnounceList AnnounceList     `bencode:"announce-list,omitempty"` // BEP 12
 
 	// Where's this specified? Mentioned at
 	// https://wiki.theory.org/index.php/BitTorrentSpecification: (optional) the creation time of
 	// the torrent, in standard UNIX epoch format (integer, seconds since 1-Jan-1970 00:00:00 UTC)
-	// CreationDate null.Null[bencode.Total] `bencode:"creation date,omitempty,ignore_unmarshal_type_error"`
-	InfoBytes    bencode.RawBytes `bencode:"info,omitempty"`          // BEP 3
-	AnnounceList AnnounceList     `bencode:"announce-list,omitempty"` // BEP 12
+	CreationDate LooseInt `bencode:"creation date,omitempty"`
 }
 
 // Load a MetaInfo from an io.Reader. Returns a non-nil error in case of failure.
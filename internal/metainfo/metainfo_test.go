@@ -79,3 +79,42 @@ func TestUnmarshalEmptyStringNodes(t *testing.T) {
 	err := bencode.Unmarshal([]byte("d5:nodes0:e"), &mi)
 	require.NoError(t, err)
 }
+
+func TestUrlList(t *testing.T) {
+	mi, err := LoadFromFile("testdata/flat-url-list.torrent")
+	require.NoError(t, err)
+	require.Len(t, mi.UrlList, 1)
+
+	mi, err = LoadFromFile("testdata/SKODAOCTAVIA336x280_archive.torrent")
+	require.NoError(t, err)
+	require.NotEmpty(t, mi.UrlList)
+
+	m, err := Load([]byte("d9:httpseeds5:c.org4:infode8:url-listl5:a.orgi1e5:b.orgee"))
+	require.NoError(t, err)
+	require.Equal(t, UrlList{"a.org", "b.org"}, m.UrlList)
+	require.Equal(t, UrlList{"c.org"}, m.HTTPSeeds)
+
+	m, err = Load([]byte("d4:infode8:url-listi1ee"))
+	require.NoError(t, err)
+	require.Empty(t, m.UrlList)
+}
+
+// https://github.com/anacrolix/torrent/issues/65
+func TestNodes(t *testing.T) {
+	mi, err := LoadFromFile("testdata/issue_65a.torrent")
+	require.NoError(t, err)
+	require.NotEmpty(t, mi.Nodes)
+	require.Equal(t, Node("185.34.3.132:5680"), mi.Nodes[0])
+
+	mi, err = LoadFromFile("testdata/trackerless.torrent")
+	require.NoError(t, err)
+	require.Equal(t, Nodes{"udp://tracker.openbittorrent.com:80", "udp://tracker.openbittorrent.com:80"}, mi.Nodes)
+
+	m, err := Load([]byte("d4:infode5:nodesll7:1.2.3.4i6881eeli1ei2eel0:i1eel3:::1i80eeee"))
+	require.NoError(t, err)
+	require.Equal(t, Nodes{"1.2.3.4:6881", "[::1]:80"}, m.Nodes)
+
+	raw, err := bencode.Marshal(MetaInfo{Nodes: m.Nodes})
+	require.NoError(t, err)
+	require.Equal(t, "d5:nodesll7:1.2.3.4i6881eel3:::1i80eeee", string(raw))
+}
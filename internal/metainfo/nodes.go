@@ -0,0 +1,76 @@
+// Copyright 2024 trim21 <trim21.me@gmail.com>
+// Copyright https://github.com/anacrolix
+// SPDX-License-Identifier: MPL-2.0
+// https://github.com/anacrolix/torrent/blob/v1.56.1/LICENSE
+
+package metainfo
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/trim21/go-bencode"
+)
+
+// Node is a "host:port" DHT node of the BEP 5 nodes key.
+type Node string
+
+// Nodes is the BEP 5 nodes key, a list of [host, port] pairs. Some torrents
+// write "host:port" strings instead, both are accepted and anything else is
+// dropped.
+type Nodes []Node
+
+func (ns *Nodes) UnmarshalBencode(b []byte) error {
+	*ns = nil
+	var l []bencode.RawBytes
+	if err := bencode.Unmarshal(b, &l); err != nil {
+		return nil
+	}
+	for _, raw := range l {
+		if n, ok := parseNode(raw); ok {
+			*ns = append(*ns, n)
+		}
+	}
+	return nil
+}
+
+func parseNode(raw []byte) (Node, bool) {
+	var s string
+	if err := bencode.Unmarshal(raw, &s); err == nil {
+		return Node(s), s != ""
+	}
+
+	var pair []bencode.RawBytes
+	if err := bencode.Unmarshal(raw, &pair); err != nil || len(pair) != 2 {
+		return "", false
+	}
+	var host string
+	var port int64
+	if bencode.Unmarshal(pair[0], &host) != nil || bencode.Unmarshal(pair[1], &port) != nil {
+		return "", false
+	}
+	if host == "" || port <= 0 || port > 65535 {
+		return "", false
+	}
+	return Node(net.JoinHostPort(host, strconv.FormatInt(port, 10))), true
+}
+
+func (ns Nodes) MarshalBencode() ([]byte, error) {
+	l := make([][]any, 0, len(ns))
+	for _, n := range ns {
+		host, port, err := net.SplitHostPort(string(n))
+		if err != nil {
+			continue
+		}
+		p, err := strconv.ParseUint(port, 10, 16)
+		if err != nil {
+			continue
+		}
+		l = append(l, []any{host, int64(p)})
+	}
+	return bencode.Marshal(l)
+}
+
+func (ns Nodes) IsZeroBencodeValue() bool {
+	return len(ns) == 0
+}
@@ -0,0 +1,50 @@
+// Copyright 2024 trim21 <trim21.me@gmail.com>
+// Copyright https://github.com/anacrolix
+// SPDX-License-Identifier: MPL-2.0
+// https://github.com/anacrolix/torrent/blob/v1.56.1/LICENSE
+
+package metainfo
+
+import (
+	"github.com/trim21/go-bencode"
+)
+
+// UrlList is a list of URLs that may also be written as a single string, as
+// BEP 19 url-list and BEP 17 httpseeds are in the wild. Elements of the wrong
+// type are dropped.
+type UrlList []string
+
+func (ul *UrlList) UnmarshalBencode(b []byte) error {
+	*ul = nil
+	if len(b) == 0 {
+		return nil
+	}
+
+	if b[0] != 'l' {
+		var s string
+		if err := bencode.Unmarshal(b, &s); err == nil && s != "" {
+			*ul = UrlList{s}
+		}
+		return nil
+	}
+
+	var l []bencode.RawBytes
+	if err := bencode.Unmarshal(b, &l); err != nil {
+		return nil
+	}
+	for _, raw := range l {
+		var s string
+		if err := bencode.Unmarshal(raw, &s); err == nil && s != "" {
+			*ul = append(*ul, s)
+		}
+	}
+	return nil
+}
+
+func (ul UrlList) MarshalBencode() ([]byte, error) {
+	return bencode.Marshal([]string(ul))
+}
+
+func (ul UrlList) IsZeroBencodeValue() bool {
+	return len(ul) == 0
+}
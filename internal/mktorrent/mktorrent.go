@@ -0,0 +1,302 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+// Package mktorrent creates v1 torrents of local files.
+//
+// A Plan scans the files and fixes the layout of the torrent, Build hashes the
+// pieces through a hash check of the session scheduler, so creating a torrent
+// shares the device slots, speed limit and hash workers of checks.
+package mktorrent
+
+import (
+	"context"
+	"crypto/sha1"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/trim21/go-bencode"
+
+	"neptune/internal/hashcheck"
+	"neptune/internal/meta"
+	"neptune/internal/metainfo"
+	"neptune/internal/pkg/gfs"
+)
+
+const (
+	MinPieceLength = 16 << 10
+	MaxPieceLength = 256 << 20
+
+	// automatic piece lengths stop at 16 MiB, which most clients handle
+	maxAutoPieceLength = 16 << 20
+	// autoPieces is the number of pieces an automatic piece length aims for.
+	autoPieces = 1500
+)
+
+var ErrNoFiles = errors.New("no files to create a torrent of")
+
+// Options describe a torrent to create.
+type Options struct {
+	// Path is the file or directory to create the torrent of.
+	Path      string
+	Source    string
+	Comment   string
+	CreatedBy string
+	// Trackers are announce URLs grouped in tiers.
+	Trackers [][]string
+	// WebSeeds are BEP 19 web seed URLs.
+	WebSeeds []string
+	// Exclude are glob patterns of files and directories to leave out,
+	// matched against both the name and the slash separated path relative to
+	// Path.
+	Exclude []string
+	// PieceLength is a power of two, 0 picks one by the total size.
+	PieceLength int64
+	Private     bool
+}
+
+// Plan is the layout of a torrent to create, before its pieces are hashed.
+type Plan struct {
+	// BasePath is the directory holding the files of the torrent, where it is
+	// added to seed.
+	BasePath string
+	opts     Options
+	dict     metainfo.Info
+	trackers metainfo.AnnounceList
+	// info has the files of the torrent with zeroed piece hashes.
+	info meta.Info
+}
+
+// Torrent is a created torrent.
+type Torrent struct {
+	MetaInfo *metainfo.MetaInfo
+	// Raw is the bencoded torrent file.
+	Raw  []byte
+	Info meta.Info
+}
+
+// NewPlan scans the files of opts.Path and validates opts.
+func NewPlan(opts Options) (*Plan, error) {
+	root, err := filepath.Abs(opts.Path)
+	if err != nil {
+		return nil, err
+	}
+	for _, pattern := range opts.Exclude {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid exclude pattern %q: %w", pattern, err)
+		}
+	}
+	trackers, err := announceList(opts.Trackers)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range opts.WebSeeds {
+		if err := validateURL(u, "http", "https"); err != nil {
+			return nil, fmt.Errorf("invalid web seed: %w", err)
+		}
+	}
+
+	stat, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+
+	dict := metainfo.Info{Name: filepath.Base(root), Source: opts.Source}
+	if opts.Private {
+		dict.Private = &opts.Private
+	}
+	basePath := root
+	if stat.Mode().IsRegular() {
+		basePath = filepath.Dir(root)
+		dict.Length = stat.Size()
+	} else {
+		dict.Files, err = scan(root, opts.Exclude)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	total := dict.TotalLength()
+	if total == 0 {
+		return nil, ErrNoFiles
+	}
+
+	dict.PieceLength = opts.PieceLength
+	if dict.PieceLength == 0 {
+		dict.PieceLength = AutoPieceLength(total)
+	}
+	if err := ValidatePieceLength(dict.PieceLength); err != nil {
+		return nil, err
+	}
+	dict.Pieces = make([]byte, (total+dict.PieceLength-1)/dict.PieceLength*sha1.Size)
+
+	infoBytes, err := bencode.Marshal(dict)
+	if err != nil {
+		return nil, err
+	}
+	info, err := meta.FromTorrent(metainfo.MetaInfo{InfoBytes: infoBytes})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Plan{opts: opts, dict: dict, info: info, trackers: trackers, BasePath: basePath}, nil
+}
+
+// scan returns the regular files under root in path order, leaving out the
+// excluded ones. Symlinks are not followed.
+func scan(root string, exclude []string) ([]metainfo.FileInfo, error) {
+	var files []metainfo.FileInfo
+	err := filepath.WalkDir(root, func(p string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if excluded(rel, exclude) {
+			if e.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !e.Type().IsRegular() {
+			return nil
+		}
+		stat, err := e.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, metainfo.FileInfo{Path: strings.Split(rel, "/"), Length: stat.Size()})
+		return nil
+	})
+	return files, err
+}
+
+func excluded(rel string, patterns []string) bool {
+	name := path.Base(rel)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func announceList(tiers [][]string) (metainfo.AnnounceList, error) {
+	var list metainfo.AnnounceList
+	for _, tier := range tiers {
+		var urls []string
+		for _, u := range tier {
+			if err := validateURL(u, "http", "https", "udp"); err != nil {
+				return nil, fmt.Errorf("invalid tracker: %w", err)
+			}
+			urls = append(urls, u)
+		}
+		if len(urls) != 0 {
+			list = append(list, urls)
+		}
+	}
+	return list, nil
+}
+
+func validateURL(s string, schemes ...string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme && u.Host != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not a %s URL", s, strings.Join(schemes, ", "))
+}
+
+// AutoPieceLength picks a piece length for total bytes of data, doubling from
+// the minimum until there are about autoPieces pieces.
+func AutoPieceLength(total int64) int64 {
+	pieceLength := int64(MinPieceLength)
+	for pieceLength < maxAutoPieceLength && total/pieceLength > autoPieces {
+		pieceLength *= 2
+	}
+	return pieceLength
+}
+
+// ValidatePieceLength checks that n is a power of two within the limits.
+func ValidatePieceLength(n int64) error {
+	if n < MinPieceLength || n > MaxPieceLength || n&(n-1) != 0 {
+		return fmt.Errorf("invalid piece length %d, must be a power of two from 16 KiB to 256 MiB", n)
+	}
+	return nil
+}
+
+// TotalLength returns the bytes of data in the torrent.
+func (p *Plan) TotalLength() int64 {
+	return p.info.TotalLength
+}
+
+// Build hashes the pieces with check, which must be admitted, reading the
+// files through ioc, and encodes the torrent.
+func (p *Plan) Build(ctx context.Context, check *hashcheck.Check, ioc *gfs.IOContext) (*Torrent, error) {
+	pieces := make([]hashcheck.Piece, p.info.NumPieces)
+	for i := range pieces {
+		pieces[i] = hashcheck.Piece{Index: uint32(i), Length: p.info.PieceLen(uint32(i))}
+	}
+	pio := ioc.ForPath(p.BasePath)
+	digests, err := check.Hash(ctx, p.info.NumPieces, pieces, func() hashcheck.Reader {
+		return hashcheck.NewFileReader(&p.info, func(index int) string {
+			return p.info.Files[index].FullPath(p.BasePath)
+		}, pio)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dict := p.dict
+	dict.Pieces = make([]byte, 0, len(digests)*sha1.Size)
+	for _, digest := range digests {
+		dict.Pieces = append(dict.Pieces, digest[:]...)
+	}
+	infoBytes, err := bencode.Marshal(dict)
+	if err != nil {
+		return nil, err
+	}
+
+	m := metainfo.MetaInfo{
+		InfoBytes:    infoBytes,
+		Comment:      p.opts.Comment,
+		CreatedBy:    metainfo.LooseString(p.opts.CreatedBy),
+		CreationDate: metainfo.LooseInt(time.Now().Unix()),
+		UrlList:      p.opts.WebSeeds,
+	}
+	if len(p.trackers) != 0 {
+		m.Announce = p.trackers[0][0]
+		if len(p.trackers) > 1 || len(p.trackers[0]) > 1 {
+			m.AnnounceList = p.trackers
+		}
+	}
+	raw, err := bencode.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := meta.FromTorrent(m)
+	if err != nil {
+		return nil, err
+	}
+	return &Torrent{Raw: raw, MetaInfo: &m, Info: info}, nil
+}
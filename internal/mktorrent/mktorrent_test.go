@@ -0,0 +1,112 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package mktorrent
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"neptune/internal/hashcheck"
+	"neptune/internal/metainfo"
+	"neptune/internal/pkg/gfs"
+)
+
+func build(t *testing.T, opts Options) *Torrent {
+	t.Helper()
+	plan, err := NewPlan(opts)
+	require.NoError(t, err)
+
+	ioc := gfs.NewIOContext()
+	t.Cleanup(ioc.Close)
+	var s *hashcheck.Scheduler
+	check, err := s.Enqueue(metainfo.Hash{}, plan.BasePath)
+	require.NoError(t, err)
+	defer check.Done()
+
+	torrent, err := plan.Build(context.Background(), check, ioc)
+	require.NoError(t, err)
+	return torrent
+}
+
+func TestBuildDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "album")
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "cd1"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".cache"), 0o755))
+	a := bytes.Repeat([]byte{1}, MinPieceLength+100)
+	b := bytes.Repeat([]byte{2}, 300)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cd1", "01.flac"), a, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cover.jpg"), b, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Thumbs.db"), b, 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".cache", "x"), b, 0o644))
+
+	torrent := build(t, Options{
+		Path:     dir,
+		Trackers: [][]string{{"https://a.example/announce"}, {"udp://b.example:80"}},
+		WebSeeds: []string{"https://seed.example/"},
+		Exclude:  []string{"Thumbs.db", ".cache"},
+		Source:   "example",
+		Private:  true,
+	})
+
+	require.Equal(t, "https://a.example/announce", torrent.MetaInfo.Announce)
+	require.Equal(t, metainfo.AnnounceList{{"https://a.example/announce"}, {"udp://b.example:80"}}, torrent.MetaInfo.AnnounceList)
+
+	info, err := torrent.MetaInfo.UnmarshalInfo()
+	require.NoError(t, err)
+	require.Equal(t, "album", info.Name)
+	require.Equal(t, "example", info.Source)
+	require.True(t, *info.Private)
+	require.Equal(t, []metainfo.FileInfo{
+		{Path: []string{"cd1", "01.flac"}, Length: int64(len(a))},
+		{Path: []string{"cover.jpg"}, Length: int64(len(b))},
+	}, info.Files)
+
+	data := append(bytes.Clone(a), b...)
+	first := sha1.Sum(data[:MinPieceLength])
+	last := sha1.Sum(data[MinPieceLength:])
+	require.Equal(t, append(first[:], last[:]...), info.Pieces)
+	require.Equal(t, uint32(2), torrent.Info.NumPieces)
+}
+
+func TestBuildSingleFile(t *testing.T) {
+	dir := t.TempDir()
+	data := bytes.Repeat([]byte{3}, 1000)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "movie.mkv"), data, 0o644))
+
+	plan, err := NewPlan(Options{Path: filepath.Join(dir, "movie.mkv")})
+	require.NoError(t, err)
+	require.Equal(t, dir, plan.BasePath)
+
+	torrent := build(t, Options{Path: filepath.Join(dir, "movie.mkv"), Comment: "hello"})
+	require.Equal(t, "hello", torrent.Info.Comment)
+	require.Equal(t, "movie.mkv", torrent.Info.Files[0].Path)
+	digest := sha1.Sum(data)
+	require.Equal(t, metainfo.Hash(digest), torrent.Info.Pieces[0])
+}
+
+func TestNewPlanRejects(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a"), []byte("a"), 0o644))
+
+	_, err := NewPlan(Options{Path: dir, Exclude: []string{"*"}})
+	require.ErrorIs(t, err, ErrNoFiles)
+	_, err = NewPlan(Options{Path: dir, PieceLength: 3 << 14})
+	require.Error(t, err)
+	_, err = NewPlan(Options{Path: dir, Trackers: [][]string{{"not a url"}}})
+	require.Error(t, err)
+	_, err = NewPlan(Options{Path: dir, Exclude: []string{"["}})
+	require.Error(t, err)
+}
+
+func TestAutoPieceLength(t *testing.T) {
+	require.Equal(t, int64(MinPieceLength), AutoPieceLength(1000))
+	require.Equal(t, int64(4<<20), AutoPieceLength(5<<30))
+	require.Equal(t, int64(maxAutoPieceLength), AutoPieceLength(1<<40))
+}
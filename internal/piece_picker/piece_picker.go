@@ -1219,6 +1219,54 @@ func (pp *PiecePicker) RemoveDownloadingPiece(pieceIndex uint32) {
 	}
 }
 
+// DownloadingPieces returns the indexes of pieces in the downloading set.
+func (pp *PiecePicker) DownloadingPieces() []uint32 {
+	if pp == nil {
+		return nil
+	}
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+
+	r := make([]uint32, len(pp.downloadingPieces))
+	for i, dp := range pp.downloadingPieces {
+		r[i] = dp.index
+	}
+	return r
+}
+
+// RestoreBlocks marks blocks of a piece as responded without a claim, for
+// blocks written to disk before a restart. The piece joins the downloading set
+// so the remaining blocks are picked first, and still has to pass its hash
+// check once they arrive.
+func (pp *PiecePicker) RestoreBlocks(pieceIndex uint32, blocks []uint32) {
+	if pp == nil {
+		return
+	}
+	pp.mu.Lock()
+	defer pp.mu.Unlock()
+
+	idx := pp.blockInfoIdx(pieceIndex)
+	nb := pp.numBlocksInPiece(pieceIndex)
+	var restored uint16
+	for _, b := range blocks {
+		if b >= uint32(nb) || pp.blockInfos.get(idx+int(b)) != blockStateNone {
+			continue
+		}
+		pp.blockInfos.set(idx+int(b), blockStateResponded)
+		pp.freeBlocks--
+		restored++
+	}
+	if restored == 0 {
+		return
+	}
+
+	pp.respondedBlocks[pieceIndex] += restored
+	pp.addDownloadingPieceUnsafe(pieceIndex)
+	pp.findDownloadingPiece(pieceIndex).responded += restored
+	pp.dirty = true
+	pp.partialsDirty = true
+}
+
 // CountBusyBlocks returns the number of busy (requested) blocks in a piece.
 func (pp *PiecePicker) CountBusyBlocks(pieceIndex uint32) int {
 	if pp == nil {
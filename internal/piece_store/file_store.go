@@ -6,13 +6,15 @@ package piece_store
 import (
 	"context"
 	"crypto/sha1"
+	"errors"
 	"io"
+	"io/fs"
 	"os"
-	"path/filepath"
 	"time"
 
 	"neptune/internal/pkg/fadvise"
 	"neptune/internal/pkg/fallocate"
+	"neptune/internal/pkg/filepool"
 	"neptune/internal/pkg/mempool"
 )
 
@@ -21,19 +23,33 @@ const verifyReadSize = 1 << 20
 var verifyBufferPool mempool.Pool
 
 func (s *FileStore) filePath(fileIndex int) string {
-	return filepath.Join(s.basePath, s.info.Files[fileIndex].Path)
+	return s.parts.Path(fileIndex, s.info.Files[fileIndex].FullPath(s.basePath))
 }
 
 func (s *FileStore) WriteChunk(ctx context.Context, pieceIndex uint32, begin uint32, data []byte) error {
 	s.opMu.RLock()
 	defer s.opMu.RUnlock()
 
+	// A missing mount leaves an empty directory, or none at all, where the
+	// data was. Writing there would download the torrent again onto the
+	// wrong disk.
+	if err := s.mount.check(s.basePath); err != nil {
+		return err
+	}
+
 	offset := int64(pieceIndex)*s.info.PieceLength + int64(begin)
 	size := int64(len(data))
 	var off int64
+	shared := false
 	for chunk := range s.info.FileChunks(offset, offset+size) {
-		path := s.filePath(chunk.FileIndex)
-		f, fresh, err := s.fp.Open(path, os.O_RDWR|os.O_CREATE, os.ModePerm, time.Hour)
+		if s.sharedFiles != nil && s.sharedFiles.Contains(uint32(chunk.FileIndex)) {
+			// The bytes belong to another torrent or the user. A piece that
+			// fails its hash check must not be repaired by writing into them.
+			shared = true
+			off += chunk.Length
+			continue
+		}
+		f, fresh, err := s.openForWrite(s.filePath(chunk.FileIndex))
 		if err != nil {
 			return err
 		}
@@ -49,14 +65,39 @@ func (s *FileStore) WriteChunk(ctx context.Context, pieceIndex uint32, begin uin
 		_, err = s.diskIO.WriteAtCtx(ctx, f.File, data[off:off+chunk.Length], chunk.OffsetOfFile)
 		if err != nil {
 			f.Close()
+			s.hashers.invalidate(&s.info, offset, size)
 			return err
 		}
 		f.Release()
 		off += chunk.Length
 	}
+	if shared {
+		// the file may hold other bytes than data, the piece is read back
+		s.hashers.invalidate(&s.info, offset, size)
+		return nil
+	}
+	s.hashers.feed(&s.info, offset, data)
 	return nil
 }
 
+// openForWrite opens the file at path for writing, creating it when it does
+// not exist yet. The mount is checked again before a file is created.
+func (s *FileStore) openForWrite(path string) (*filepool.File, bool, error) {
+	f, fresh, err := s.fp.Open(path, os.O_RDWR, os.ModePerm, time.Hour)
+	if !errors.Is(err, fs.ErrNotExist) {
+		return f, fresh, err
+	}
+	if err := s.mount.recheck(s.basePath); err != nil {
+		return nil, false, err
+	}
+	created, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, os.ModePerm)
+	if err != nil {
+		return nil, false, err
+	}
+	_ = created.Close()
+	return s.fp.Open(path, os.O_RDWR, os.ModePerm, time.Hour)
+}
+
 func (s *FileStore) ReadChunk(ctx context.Context, pieceIndex uint32, begin uint32, data []byte) (int, error) {
 	s.opMu.RLock()
 	defer s.opMu.RUnlock()
@@ -93,11 +134,18 @@ func (s *FileStore) ReadChunk(ctx context.Context, pieceIndex uint32, begin uint
 	return n, nil
 }
 
-// VerifyPiece reads piece data from disk, computes SHA1, and compares.
+// VerifyPiece compares the SHA1 of a piece against expected. If every byte of
+// the piece was written through WriteChunk since it was last verified, the
+// digest computed while writing is used; otherwise the piece is read back
+// from disk.
 func (s *FileStore) VerifyPiece(ctx context.Context, pieceIndex uint32, expected [sha1.Size]byte) (bool, error) {
 	s.opMu.RLock()
 	defer s.opMu.RUnlock()
 
+	if digest, ok := s.hashers.take(pieceIndex, s.info.PieceLen(pieceIndex)); ok {
+		return digest == expected, nil
+	}
+
 	hasher := sha1.New()
 	buf := mempool.GetWithCapFromPool(&verifyBufferPool, verifyReadSize)
 	defer verifyBufferPool.Put(buf)
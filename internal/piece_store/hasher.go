@@ -0,0 +1,178 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package piece_store
+
+import (
+	"crypto/sha1"
+	"hash"
+	"sync"
+
+	"neptune/internal/meta"
+)
+
+const (
+	// maxHashedPieces bounds how many in-flight pieces keep a running hash.
+	// Pieces started beyond this limit are verified from disk.
+	maxHashedPieces = 4096
+
+	// hashReorderWindow is how far (in bytes) a write may land ahead of the
+	// hashed prefix of its piece. Writes inside the window are buffered until
+	// the gap is filled; anything further away drops the piece to disk
+	// verification.
+	hashReorderWindow = 1 << 20
+
+	// maxPendingHashBytes bounds the out-of-order data buffered across all
+	// pieces of a store.
+	maxPendingHashBytes = 32 << 20
+)
+
+// pieceHasher hashes the prefix [0, next) of one piece from the data written
+// by this process. Writes past next are kept in pending until they become
+// contiguous.
+type pieceHasher struct {
+	hash         hash.Hash
+	pending      map[int64][]byte
+	next         int64
+	pendingBytes int64
+}
+
+// writeHashers feeds written blocks into per-piece SHA-1 states so
+// VerifyPiece can skip re-reading data that just went through WriteChunk.
+//
+// Pieces whose data was (partially) on disk before this process wrote to
+// them never hash their full length, and pieces that received overlapping or
+// far out-of-order writes lose their hasher; both fall back to reading from
+// disk.
+type writeHashers struct {
+	pieces       map[uint32]*pieceHasher
+	pendingBytes int64
+	mu           sync.Mutex
+}
+
+// feed records data written at the torrent-global offset. data may span
+// multiple pieces.
+func (w *writeHashers) feed(info *meta.Info, offset int64, data []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for len(data) > 0 {
+		pieceIndex := uint32(offset / info.PieceLength)
+		pieceOff := offset - int64(pieceIndex)*info.PieceLength
+		n := min(int64(len(data)), info.PieceLen(pieceIndex)-pieceOff)
+		w.feedPieceLocked(pieceIndex, pieceOff, data[:n])
+		data = data[n:]
+		offset += n
+	}
+}
+
+func (w *writeHashers) feedPieceLocked(pieceIndex uint32, off int64, data []byte) {
+	h, ok := w.pieces[pieceIndex]
+	if !ok {
+		if len(w.pieces) >= maxHashedPieces {
+			return
+		}
+		if w.pieces == nil {
+			w.pieces = make(map[uint32]*pieceHasher)
+		}
+		h = &pieceHasher{hash: sha1.New()}
+		w.pieces[pieceIndex] = h
+	} else if off == 0 && h.next > 0 {
+		// The piece is being downloaded again after a failed hash check.
+		w.pendingBytes -= h.pendingBytes
+		h.reset()
+	}
+
+	switch {
+	case off == h.next:
+		h.hash.Write(data)
+		h.next += int64(len(data))
+		w.pendingBytes -= h.drain()
+	case off > h.next && off+int64(len(data))-h.next <= hashReorderWindow &&
+		w.pendingBytes+int64(len(data)) <= maxPendingHashBytes:
+		if _, dup := h.pending[off]; dup {
+			w.dropLocked(pieceIndex)
+			return
+		}
+		if h.pending == nil {
+			h.pending = make(map[int64][]byte)
+		}
+		h.pending[off] = append([]byte(nil), data...)
+		h.pendingBytes += int64(len(data))
+		w.pendingBytes += int64(len(data))
+	default:
+		// Overwrite of already hashed bytes, or too far ahead to buffer.
+		w.dropLocked(pieceIndex)
+	}
+}
+
+func (w *writeHashers) dropLocked(pieceIndex uint32) {
+	if h, ok := w.pieces[pieceIndex]; ok {
+		w.pendingBytes -= h.pendingBytes
+		delete(w.pieces, pieceIndex)
+	}
+}
+
+// drain consumes buffered writes that became contiguous with the prefix and
+// returns how many buffered bytes were released.
+func (h *pieceHasher) drain() int64 {
+	var released int64
+	for len(h.pending) > 0 {
+		data, ok := h.pending[h.next]
+		if !ok {
+			break
+		}
+		delete(h.pending, h.next)
+		h.hash.Write(data)
+		h.next += int64(len(data))
+		released += int64(len(data))
+	}
+	h.pendingBytes -= released
+	return released
+}
+
+func (h *pieceHasher) reset() {
+	h.hash.Reset()
+	h.next = 0
+	h.pending = nil
+	h.pendingBytes = 0
+}
+
+// take removes the hasher for pieceIndex and returns its digest if it covers
+// exactly length bytes.
+func (w *writeHashers) take(pieceIndex uint32, length int64) ([sha1.Size]byte, bool) {
+	w.mu.Lock()
+	h, ok := w.pieces[pieceIndex]
+	w.dropLocked(pieceIndex)
+	w.mu.Unlock()
+
+	var digest [sha1.Size]byte
+	if !ok || h.next != length || len(h.pending) != 0 {
+		return digest, false
+	}
+	copy(digest[:], h.hash.Sum(nil))
+	return digest, true
+}
+
+// clear drops every hasher.
+func (w *writeHashers) clear() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.pieces = nil
+	w.pendingBytes = 0
+}
+
+// invalidate drops every hasher overlapping the torrent-global byte range.
+func (w *writeHashers) invalidate(info *meta.Info, offset, size int64) {
+	if size <= 0 {
+		return
+	}
+	first := uint32(offset / info.PieceLength)
+	last := uint32((offset + size - 1) / info.PieceLength)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for pieceIndex := first; pieceIndex <= last; pieceIndex++ {
+		w.dropLocked(pieceIndex)
+	}
+}
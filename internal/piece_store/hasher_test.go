@@ -0,0 +1,151 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package piece_store
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"neptune/internal/meta"
+	"neptune/internal/pkg/bm"
+)
+
+func TestVerifyPieceUsesWriteHash(t *testing.T) {
+	info := moveTestInfo([]meta.File{{Path: "data", Length: 64 * 1024}})
+	base := t.TempDir()
+	store := newMoveTestStore(t, info, base, nil)
+	data := bytes.Repeat([]byte("abcd"), int(info.TotalLength)/4)
+	expected := sha1.Sum(data)
+
+	// blocks 1 and 3 arrive before 0 and 2
+	const block = 16 * 1024
+	for _, begin := range []int{block, 3 * block, 0, 2 * block} {
+		if err := store.WriteChunk(context.Background(), 0, uint32(begin), data[begin:begin+block]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// corrupt the file behind the store's back, the digest must come from
+	// the written blocks rather than a disk read.
+	if err := os.WriteFile(filepath.Join(base, "data"), make([]byte, len(data)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := store.VerifyPiece(context.Background(), 0, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected piece to verify from write hash")
+	}
+
+	// the write hash is consumed by the first verification
+	ok, err = store.VerifyPiece(context.Background(), 0, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected second verification to read corrupted data from disk")
+	}
+}
+
+func TestVerifyPieceFallsBackToDisk(t *testing.T) {
+	info := moveTestInfo([]meta.File{{Path: "data", Length: 4 * hashReorderWindow}})
+	base := t.TempDir()
+	store := newMoveTestStore(t, info, base, nil)
+	data := bytes.Repeat([]byte{7}, int(info.TotalLength))
+	expected := sha1.Sum(data)
+
+	half := len(data) / 2
+	// the second half lands beyond the reorder window and drops the write hash
+	if err := store.WriteChunk(context.Background(), 0, 0, data[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.WriteChunk(context.Background(), 0, uint32(half), data[half:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.WriteChunk(context.Background(), 0, 1, data[1:half]); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := store.hashers.pieces[0]; ok {
+		t.Fatal("expected write hash to be dropped")
+	}
+
+	ok, err := store.VerifyPiece(context.Background(), 0, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected piece to verify from disk")
+	}
+}
+
+func TestRelocateDropsWriteHash(t *testing.T) {
+	info := moveTestInfo([]meta.File{{Path: "data", Length: 64 * 1024}})
+	store := newMoveTestStore(t, info, t.TempDir(), nil)
+	data := bytes.Repeat([]byte("abcd"), int(info.TotalLength)/4)
+	expected := sha1.Sum(data)
+
+	// the first blocks are written at the old location only
+	const block = 16 * 1024
+	if err := store.WriteChunk(context.Background(), 0, 0, data[:3*block]); err != nil {
+		t.Fatal(err)
+	}
+	store.Relocate(t.TempDir())
+	if err := store.WriteChunk(context.Background(), 0, 3*block, data[3*block:]); err != nil {
+		t.Fatal(err)
+	}
+
+	ok, err := store.VerifyPiece(context.Background(), 0, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected piece to be read from the new location and fail")
+	}
+}
+
+func TestWriteChunkSkipsSharedFiles(t *testing.T) {
+	const size = 16 * 1024
+	info := moveTestInfo([]meta.File{{Path: "own", Length: size}, {Path: "shared", Length: size}})
+	base := t.TempDir()
+	data := bytes.Repeat([]byte("abcd"), int(info.TotalLength)/4)
+	sharedPath := filepath.Join(base, "shared")
+	if err := os.WriteFile(sharedPath, data[size:], 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := newMoveTestStore(t, info, base, nil)
+	store.sharedFiles = bm.NewLockFreeBitmap(2)
+	store.sharedFiles.Set(1)
+
+	// the downloaded bytes of the shared file are bad, they must not end up
+	// in it
+	written := bytes.Clone(data)
+	clear(written[size:])
+	if err := store.WriteChunk(context.Background(), 0, 0, written); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := os.ReadFile(sharedPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data[size:]) {
+		t.Fatal("expected the shared file to be left alone")
+	}
+	ok, err := store.VerifyPiece(context.Background(), 0, sha1.Sum(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected the piece to be read back from disk")
+	}
+}
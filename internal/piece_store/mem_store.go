@@ -24,6 +24,7 @@ type MemStore struct {
 
 // NewMemStore creates an in-memory store for testing.
 func NewMemStore(info meta.Info) Store {
+	info.Files = slices.Clone(info.Files)
 	return &MemStore{info: info, data: make(map[int64][]byte)}
 }
 
@@ -82,3 +83,23 @@ func (s *MemStore) Move(ctx context.Context, _ string, report MoveProgressFunc)
 	}
 	return nil
 }
+
+func (s *MemStore) Relocate(string) {}
+
+func (s *MemStore) RenameFile(index int, path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.info.Files[index].SetPath(path)
+	return nil
+}
+
+func (s *MemStore) UnshareFile(index int, path string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.info.Files[index].SetPath(path)
+	return true, nil
+}
+
+func (s *MemStore) SetFileComplete(int, bool) error { return nil }
+
+func (s *MemStore) SetMountPoint(string) {}
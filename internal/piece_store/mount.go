@@ -0,0 +1,92 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package piece_store
+
+import (
+	"fmt"
+	"time"
+
+	"go.uber.org/atomic"
+
+	"neptune/internal/pkg/disk_io"
+)
+
+// mountCheckInterval is how long a write trusts the last mount check, so
+// writes do not read the mount table every time.
+const mountCheckInterval = 10 * time.Second
+
+// MountError is returned when the base path of a torrent resolves to another
+// mount than the one its data was saved on, usually because the disk holding
+// it is not mounted.
+type MountError struct {
+	BasePath string
+	Want     string
+	Got      string
+}
+
+func (e *MountError) Error() string {
+	return fmt.Sprintf("refusing to write to %q: it is on mount %q instead of %q, is the disk mounted?",
+		e.BasePath, e.Got, e.Want)
+}
+
+// CheckMount returns a *MountError if basePath is not on mount. An empty
+// mount, or one that cannot be detected on this system, always passes.
+func CheckMount(basePath string, mount string) error {
+	if mount == "" {
+		return nil
+	}
+	got := disk_io.MountPointOf(basePath)
+	if got == "" || got == mount {
+		return nil
+	}
+	return &MountError{BasePath: basePath, Want: mount, Got: got}
+}
+
+// mountGuard caches the result of CheckMount for mountCheckInterval. Creating
+// a file does not trust the cache, see recheck.
+type mountGuard struct {
+	err     atomic.Pointer[error]
+	mount   atomic.String
+	checked atomic.Int64
+}
+
+func (g *mountGuard) set(mount string) {
+	g.mount.Store(mount)
+	g.checked.Store(0)
+}
+
+func (g *mountGuard) check(basePath string) error {
+	mount := g.mount.Load()
+	if mount == "" {
+		return nil
+	}
+	now := time.Now().UnixNano()
+	if now-g.checked.Load() < int64(mountCheckInterval) {
+		if err := g.err.Load(); err != nil {
+			return *err
+		}
+		return nil
+	}
+	return g.recheck(basePath)
+}
+
+// recheck checks the mount now, ignoring the cached result. A mount that went
+// away since the last check would otherwise get new files until the cache
+// expires.
+func (g *mountGuard) recheck(basePath string) error {
+	mount := g.mount.Load()
+	if mount == "" {
+		return nil
+	}
+	err := CheckMount(basePath, mount)
+	g.err.Store(&err)
+	g.checked.Store(time.Now().UnixNano())
+	return err
+}
+
+// SetMountPoint makes writes fail with a *MountError while the base path is
+// not on mount. An empty mount disables the check.
+func (s *FileStore) SetMountPoint(mount string) {
+	s.mount.set(mount)
+}
@@ -0,0 +1,79 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package piece_store
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"neptune/internal/meta"
+	"neptune/internal/pkg/disk_io"
+)
+
+func TestFileStoreRefusesWriteOnOtherMount(t *testing.T) {
+	info := moveTestInfo([]meta.File{{Path: "data", Length: 4}})
+	base := t.TempDir()
+	mount := disk_io.MountPointOf(base)
+	if mount == "" {
+		t.Skip("mounts are not detected on this system")
+	}
+	store := newMoveTestStore(t, info, base, nil)
+
+	store.SetMountPoint(filepath.Join(base, "missing-disk"))
+	err := store.WriteChunk(context.Background(), 0, 0, []byte("abcd"))
+	var mountErr *MountError
+	if !errors.As(err, &mountErr) {
+		t.Fatalf("WriteChunk = %v, want a MountError", err)
+	}
+	if mountErr.Got != mount {
+		t.Fatalf("Got = %q, want %q", mountErr.Got, mount)
+	}
+	if _, err := os.Stat(filepath.Join(base, "data")); !os.IsNotExist(err) {
+		t.Fatalf("file is created on the wrong mount: %v", err)
+	}
+
+	store.SetMountPoint(mount)
+	if err := store.WriteChunk(context.Background(), 0, 0, []byte("abcd")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileStoreChecksMountBeforeCreatingFile(t *testing.T) {
+	info := moveTestInfo([]meta.File{{Path: "a", Length: 4}, {Path: "b", Length: 4}})
+	base := t.TempDir()
+	mount := disk_io.MountPointOf(base)
+	if mount == "" {
+		t.Skip("mounts are not detected on this system")
+	}
+	store := newMoveTestStore(t, info, base, nil)
+	store.SetMountPoint(mount)
+	if err := store.WriteChunk(context.Background(), 0, 0, []byte("abcd")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the disk goes away while the last check is still cached
+	store.mount.mount.Store(filepath.Join(base, "missing-disk"))
+	if err := store.WriteChunk(context.Background(), 0, 0, []byte("abcd")); err != nil {
+		t.Fatalf("an open file is written until the cache expires: %v", err)
+	}
+	err := store.WriteChunk(context.Background(), 0, 4, []byte("efgh"))
+	var mountErr *MountError
+	if !errors.As(err, &mountErr) {
+		t.Fatalf("WriteChunk = %v, want a MountError", err)
+	}
+	if _, err := os.Stat(filepath.Join(base, "b")); !os.IsNotExist(err) {
+		t.Fatalf("file is created on the wrong mount: %v", err)
+	}
+}
+
+func TestCheckMountUnknown(t *testing.T) {
+	if err := CheckMount(t.TempDir(), ""); err != nil {
+		t.Fatalf("an unknown mount is not checked: %v", err)
+	}
+}
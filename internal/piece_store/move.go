@@ -14,7 +14,9 @@ import (
 
 	"github.com/rs/zerolog/log"
 
+	"neptune/internal/meta"
 	"neptune/internal/pkg/gfs"
+	"neptune/internal/pkg/reflink"
 )
 
 const moveCopyBufferSize = 1 << 20
@@ -151,10 +153,143 @@ func (s *FileStore) Move(ctx context.Context, target string, report MoveProgress
 	return nil
 }
 
+// Relocate switches to basePath without moving any file. Open handles of the
+// old and new location are dropped, so the next read or write opens the file
+// that is on disk now. Running piece hashes are dropped too, they cover blocks
+// written at the old location.
+func (s *FileStore) Relocate(basePath string) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	paths := make([]string, 0, len(s.info.Files)*2)
+	for i, f := range s.info.Files {
+		paths = append(paths, s.filePath(i), s.parts.Path(i, f.FullPath(basePath)))
+	}
+	s.fp.InvalidatePaths(paths)
+
+	s.parts.Detect(&s.info, basePath)
+	s.basePath = basePath
+	s.diskIO = s.ioc.ForPath(basePath)
+	s.fallocatedBm.Clear()
+	s.hashers.clear()
+}
+
+// RenameFile renames file index on disk when it exists. When only the new
+// path exists, the file is adopted as it is, such as a file already in a
+// media library; its pieces are verified by the next hash check.
+func (s *FileStore) RenameFile(index int, path string) error {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	file := &s.info.Files[index]
+	source := s.filePath(index)
+	target := meta.File{Path: path}.FullPath(s.basePath)
+	part := s.parts.IsPart(index) && !filepath.IsAbs(path)
+	if part {
+		target = s.parts.Path(index, target)
+	}
+	if source != target {
+		s.fp.InvalidatePaths([]string{source, target})
+		if err := renameFile(source, target); err != nil {
+			return err
+		}
+		if !filepath.IsAbs(file.Path) {
+			pruneRenamedDirectories(s.basePath, source)
+		}
+	}
+
+	file.SetPath(path)
+	if s.parts != nil {
+		s.parts.set(index, part)
+	}
+	s.fallocatedBm.Unset(uint32(index))
+	return nil
+}
+
+// UnshareFile clones the data file index shares with an existing file to
+// path. A hardlink is replaced by the clone, the existing file is never
+// written.
+func (s *FileStore) UnshareFile(index int, path string) (bool, error) {
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	source := s.filePath(index)
+	target := s.parts.Path(index, meta.File{Path: path}.FullPath(s.basePath))
+	if source != target {
+		if _, err := os.Lstat(target); err == nil {
+			return false, fmt.Errorf("unshare target already exists: %q", target)
+		}
+	}
+	s.fp.InvalidatePaths([]string{source, target})
+
+	tmp := target + ".unshare"
+	_ = os.Remove(tmp)
+	err := os.MkdirAll(filepath.Dir(target), os.ModePerm)
+	if err == nil {
+		err = reflink.Clone(source, tmp)
+	}
+	kept := err == nil
+	if kept {
+		if err := os.Rename(tmp, target); err != nil {
+			_ = os.Remove(tmp)
+			return false, fmt.Errorf("rename %q to %q: %w", tmp, target, err)
+		}
+	} else if source == target {
+		// only the link is removed, the file is downloaded again
+		if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return false, fmt.Errorf("remove hardlink %q: %w", target, err)
+		}
+	}
+
+	s.info.Files[index].SetPath(path)
+	if s.sharedFiles != nil {
+		s.sharedFiles.Unset(uint32(index))
+	}
+	s.fallocatedBm.Unset(uint32(index))
+	return kept, nil
+}
+
+func renameFile(source, target string) error {
+	_, err := os.Lstat(source)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("inspect rename source %q: %w", source, err)
+	}
+
+	if _, err := os.Lstat(target); err == nil {
+		return fmt.Errorf("rename target already exists: %q", target)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("inspect rename target %q: %w", target, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(target), os.ModePerm); err != nil {
+		return fmt.Errorf("create rename target directory for %q: %w", target, err)
+	}
+	if err := os.Rename(source, target); err != nil {
+		return fmt.Errorf("rename %q to %q: %w", source, target, err)
+	}
+	return nil
+}
+
+// pruneRenamedDirectories removes the directories a renamed file left empty,
+// up to but not including basePath.
+func pruneRenamedDirectories(basePath, source string) {
+	for dir := filepath.Dir(source); dir != basePath && pathContains(basePath, dir); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+	}
+}
+
 func (s *FileStore) planMove(sourceBase, targetBase string) ([]moveFile, error) {
 	files := make([]moveFile, 0, len(s.info.Files))
-	for _, torrentFile := range s.info.Files {
-		source := filepath.Join(sourceBase, torrentFile.Path)
+	for i, torrentFile := range s.info.Files {
+		if filepath.IsAbs(torrentFile.Path) {
+			// remapped outside the base path, stays where it is
+			continue
+		}
+		source := s.parts.Path(i, filepath.Join(sourceBase, torrentFile.Path))
 		stat, err := os.Lstat(source)
 		if errors.Is(err, os.ErrNotExist) {
 			continue
@@ -166,7 +301,7 @@ func (s *FileStore) planMove(sourceBase, targetBase string) ([]moveFile, error)
 			return nil, fmt.Errorf("move source is not a regular file: %q", source)
 		}
 
-		target := filepath.Join(targetBase, torrentFile.Path)
+		target := s.parts.Path(i, filepath.Join(targetBase, torrentFile.Path))
 		if _, err := os.Lstat(target); err == nil {
 			return nil, fmt.Errorf("move target already exists: %q", target)
 		} else if !errors.Is(err, os.ErrNotExist) {
@@ -182,8 +317,13 @@ func (s *FileStore) planMove(sourceBase, targetBase string) ([]moveFile, error)
 	return files, nil
 }
 
+// moveTempPrefix is the name prefix of the temporary copy of target.
+func moveTempPrefix(target string) string {
+	return "." + filepath.Base(target) + ".neptune-move-"
+}
+
 func reserveMoveTemp(target string) (string, error) {
-	f, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".neptune-move-")
+	f, err := os.CreateTemp(filepath.Dir(target), moveTempPrefix(target))
 	if err != nil {
 		return "", fmt.Errorf("reserve move target for %q: %w", target, err)
 	}
@@ -273,6 +413,74 @@ func cleanupMoveTargets(files []moveFile) {
 	}
 }
 
+// RecoverMove finishes or undoes a move that was interrupted by a crash.
+// files are paths relative to the base paths. A committed move has every file
+// at target, so sources left behind are removed. Otherwise the sources are
+// still complete and the copies at target are removed; a target file is only
+// removed while its source exists, so the last copy of a file is never lost.
+func RecoverMove(files []string, source, target string, committed bool) error {
+	if source == target {
+		return nil
+	}
+
+	var errs []error
+	moved := make([]moveFile, 0, len(files))
+	for _, name := range files {
+		file := moveFile{source: filepath.Join(source, name), target: filepath.Join(target, name)}
+		moved = append(moved, file)
+		if err := removeMoveTemps(file.target); err != nil {
+			errs = append(errs, err)
+		}
+
+		keep, stale := file.source, file.target
+		if committed {
+			keep, stale = file.target, file.source
+		}
+		if _, err := os.Lstat(keep); err != nil {
+			if !errors.Is(err, os.ErrNotExist) {
+				errs = append(errs, fmt.Errorf("inspect %q: %w", keep, err))
+			}
+			continue
+		}
+		if err := os.Remove(stale); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("remove %q: %w", stale, err))
+		}
+	}
+
+	if committed {
+		pruneMovedDirectories(source, moved)
+	} else {
+		for i := range moved {
+			moved[i].source = moved[i].target
+		}
+		pruneMovedDirectories(target, moved)
+	}
+	return errors.Join(errs...)
+}
+
+// removeMoveTemps removes the temporary copies of target left by a move.
+func removeMoveTemps(target string) error {
+	entries, err := os.ReadDir(filepath.Dir(target))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("list move targets in %q: %w", filepath.Dir(target), err)
+	}
+
+	prefix := moveTempPrefix(target)
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), prefix) {
+			continue
+		}
+		name := filepath.Join(filepath.Dir(target), e.Name())
+		if err := os.Remove(name); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove move temp %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
 func pruneMovedDirectories(sourceBase string, files []moveFile) {
 	for _, file := range files {
 		for dir := filepath.Dir(file.source); pathContains(sourceBase, dir); dir = filepath.Dir(dir) {
@@ -234,7 +234,7 @@ func newMoveTestStore(t *testing.T, info meta.Info, basePath string, selected []
 			selectedFiles.Set(index)
 		}
 	}
-	store := NewFileStore(info, basePath, filepool.New(), ioc, selectedFiles, false)
+	store := NewFileStore(info, basePath, filepool.New(), ioc, selectedFiles, nil, false, nil)
 	t.Cleanup(func() {
 		paths := make([]string, 0, len(store.info.Files))
 		for fileIndex := range store.info.Files {
@@ -244,3 +244,187 @@ func newMoveTestStore(t *testing.T, info meta.Info, basePath string, selected []
 	})
 	return store
 }
+
+func writeMoveTestFiles(t *testing.T, base string, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		path := filepath.Join(base, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(name), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRecoverMoveRollsBackUncommittedMove(t *testing.T) {
+	source := t.TempDir()
+	target := filepath.Join(t.TempDir(), "target")
+	writeMoveTestFiles(t, source, "a/data", "b/data")
+	// a/data was promoted and b/data was still a temporary copy
+	writeMoveTestFiles(t, target, "a/data", "b/.data.neptune-move-123")
+
+	if err := RecoverMove([]string{"a/data", "b/data"}, source, target, false); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"a/data", "b/data"} {
+		if _, err := os.Stat(filepath.Join(source, name)); err != nil {
+			t.Fatalf("source %q was not kept: %v", name, err)
+		}
+	}
+	if _, err := os.Stat(target); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("target was not cleaned up: %v", err)
+	}
+}
+
+func TestRecoverMoveFinishesCommittedMove(t *testing.T) {
+	source := t.TempDir()
+	target := filepath.Join(t.TempDir(), "target")
+	// cleaning removed a/data before the crash
+	writeMoveTestFiles(t, source, "b/data")
+	writeMoveTestFiles(t, target, "a/data", "b/data")
+
+	if err := RecoverMove([]string{"a/data", "b/data", "c/data"}, source, target, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(filepath.Join(source, "b")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("source was not cleaned up: %v", err)
+	}
+	for _, name := range []string{"a/data", "b/data"} {
+		if _, err := os.Stat(filepath.Join(target, name)); err != nil {
+			t.Fatalf("target %q was not kept: %v", name, err)
+		}
+	}
+}
+
+func TestFileStoreRenameFile(t *testing.T) {
+	info := moveTestInfo([]meta.File{
+		{Path: "a/b/data", Length: 8 * 1024},
+		{Path: "c/data", Length: 8 * 1024},
+	})
+	base := t.TempDir()
+	store := newMoveTestStore(t, info, base, []uint32{0})
+	data := bytes.Repeat([]byte("a"), int(info.TotalLength))
+	if err := store.WriteChunk(context.Background(), 0, 0, data); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.RenameFile(0, filepath.Join("renamed", "data")); err != nil {
+		t.Fatal(err)
+	}
+	if store.info.Files[0].Path != filepath.Join("renamed", "data") {
+		t.Fatalf("file path = %q", store.info.Files[0].Path)
+	}
+	if _, err := os.Stat(filepath.Join(base, "a")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("emptied directory was not removed: %v", err)
+	}
+	if _, err := os.Stat(base); err != nil {
+		t.Fatalf("base path was removed: %v", err)
+	}
+
+	read := make([]byte, len(data))
+	if n, err := store.ReadChunk(context.Background(), 0, 0, read); err != nil || n != len(data) || !bytes.Equal(read, data) {
+		t.Fatalf("store did not read renamed data: n=%d err=%v", n, err)
+	}
+
+	if err := store.RenameFile(0, "c/data"); err == nil {
+		t.Fatal("renaming onto an existing file succeeded")
+	}
+	if store.info.Files[0].Path != filepath.Join("renamed", "data") {
+		t.Fatalf("failed rename changed the file path to %q", store.info.Files[0].Path)
+	}
+}
+
+func TestFileStoreUnshareFile(t *testing.T) {
+	info := moveTestInfo([]meta.File{
+		{Path: "linked", Length: 16 * 1024},
+		{Path: "remapped", Length: 16 * 1024},
+	})
+	base := t.TempDir()
+	existing := t.TempDir()
+	linkSource := filepath.Join(existing, "linked")
+	remapSource := filepath.Join(existing, "remapped")
+	data := bytes.Repeat([]byte("e"), 16*1024)
+	for _, path := range []string{linkSource, remapSource} {
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Link(linkSource, filepath.Join(base, "linked")); err != nil {
+		t.Fatal(err)
+	}
+	info.Files[1].SetPath(remapSource)
+
+	store := newMoveTestStore(t, info, base, nil)
+	store.sharedFiles = bm.NewLockFreeBitmap(2)
+	store.sharedFiles.Set(0)
+	store.sharedFiles.Set(1)
+
+	for index, path := range []string{"linked", "remapped"} {
+		kept, err := store.UnshareFile(index, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if store.sharedFiles.Contains(uint32(index)) {
+			t.Fatalf("file %d is still shared", index)
+		}
+		if store.info.Files[index].Path != path {
+			t.Fatalf("file %d path = %q", index, store.info.Files[index].Path)
+		}
+
+		// without reflink support the torrent has no data for the file
+		target := filepath.Join(base, path)
+		stat, err := os.Stat(target)
+		if !kept {
+			if !errors.Is(err, os.ErrNotExist) {
+				t.Fatalf("file %d was not cloned but %q exists: %v", index, target, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		source, err := os.Stat(filepath.Join(existing, path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if os.SameFile(source, stat) {
+			t.Fatalf("file %d still shares %q", index, source.Name())
+		}
+	}
+
+	// the existing files are never touched
+	for _, path := range []string{linkSource, remapSource} {
+		got, err := os.ReadFile(path)
+		if err != nil || !bytes.Equal(got, data) {
+			t.Fatalf("existing file %q changed: %v", path, err)
+		}
+	}
+}
+
+func TestFileStoreRenameFileAdoptsAbsolutePath(t *testing.T) {
+	info := moveTestInfo([]meta.File{{Path: "data", Length: 16 * 1024}})
+	base := t.TempDir()
+	store := newMoveTestStore(t, info, base, nil)
+
+	library := filepath.Join(t.TempDir(), "library.data")
+	data := bytes.Repeat([]byte("l"), int(info.TotalLength))
+	if err := os.WriteFile(library, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.RenameFile(0, library); err != nil {
+		t.Fatal(err)
+	}
+	if store.info.Files[0].FullPath(base) != library {
+		t.Fatalf("file is stored at %q, want %q", store.info.Files[0].FullPath(base), library)
+	}
+
+	read := make([]byte, len(data))
+	if n, err := store.ReadChunk(context.Background(), 0, 0, read); err != nil || n != len(data) || !bytes.Equal(read, data) {
+		t.Fatalf("store did not read the adopted file: n=%d err=%v", n, err)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package piece_store
+
+import (
+	"os"
+	"path/filepath"
+
+	"neptune/internal/meta"
+	"neptune/internal/pkg/bm"
+)
+
+// PartFiles tracks which files are stored under their part name, the file
+// name with a suffix, until every piece covering them is verified. Files
+// remapped to an absolute path belong to the user and always keep their
+// name. A nil PartFiles stores every file under its name.
+type PartFiles struct {
+	part   *bm.LockFreeBitmap
+	suffix string
+}
+
+// NewPartFiles detects which name the files have on disk under basePath. It
+// returns nil for an empty suffix.
+func NewPartFiles(info *meta.Info, basePath string, suffix string) *PartFiles {
+	if suffix == "" {
+		return nil
+	}
+	p := &PartFiles{part: bm.NewLockFreeBitmap(uint32(len(info.Files))), suffix: suffix}
+	p.Detect(info, basePath)
+	return p
+}
+
+// Detect looks up which name the files have on disk under basePath. A file
+// keeps its name only when it exists and its part name does not, so files
+// not on disk yet are created under their part name.
+func (p *PartFiles) Detect(info *meta.Info, basePath string) {
+	if p == nil {
+		return
+	}
+	for i, f := range info.Files {
+		path := f.FullPath(basePath)
+		if filepath.IsAbs(f.Path) || (exists(path) && !exists(path+p.suffix)) {
+			p.part.Unset(uint32(i))
+		} else {
+			p.part.Set(uint32(i))
+		}
+	}
+}
+
+// IsPart reports whether file index is stored under its part name.
+func (p *PartFiles) IsPart(index int) bool {
+	return p != nil && p.part.Contains(uint32(index))
+}
+
+// Path returns the name file index has on disk for its path, relative or
+// absolute.
+func (p *PartFiles) Path(index int, path string) string {
+	if p.IsPart(index) {
+		return path + p.suffix
+	}
+	return path
+}
+
+func (p *PartFiles) set(index int, part bool) {
+	if part {
+		p.part.Set(uint32(index))
+	} else {
+		p.part.Unset(uint32(index))
+	}
+}
+
+func exists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// SetFileComplete renames file index to its name once every piece covering
+// it is verified, and back to its part name when one no longer is. A file
+// that is not on disk only changes the name it is created under.
+func (s *FileStore) SetFileComplete(index int, complete bool) error {
+	if s.parts == nil || filepath.IsAbs(s.info.Files[index].Path) {
+		return nil
+	}
+
+	s.opMu.Lock()
+	defer s.opMu.Unlock()
+
+	if s.parts.IsPart(index) != complete {
+		return nil
+	}
+	source := s.info.Files[index].FullPath(s.basePath)
+	target := source + s.parts.suffix
+	if complete {
+		source, target = target, source
+	}
+	s.fp.InvalidatePaths([]string{source, target})
+	if err := renameFile(source, target); err != nil {
+		return err
+	}
+	s.parts.set(index, !complete)
+	return nil
+}
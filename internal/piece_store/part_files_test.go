@@ -0,0 +1,91 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !release
+
+package piece_store
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"neptune/internal/meta"
+)
+
+func TestPartFilesDetect(t *testing.T) {
+	info := moveTestInfo([]meta.File{
+		{Path: "done", Length: 4},
+		{Path: "partial", Length: 4},
+		{Path: "both", Length: 4},
+		{Path: "new", Length: 4},
+	})
+	base := t.TempDir()
+	writeMoveTestFiles(t, base, "done", "partial.part", "both", "both.part")
+
+	parts := NewPartFiles(&info, base, ".part")
+	for index, want := range []bool{false, true, true, true} {
+		if got := parts.IsPart(index); got != want {
+			t.Fatalf("IsPart(%d) = %v, want %v", index, got, want)
+		}
+	}
+
+	if NewPartFiles(&info, base, "") != nil {
+		t.Fatal("an empty suffix disables part files")
+	}
+}
+
+func TestFileStoreSetFileComplete(t *testing.T) {
+	info := moveTestInfo([]meta.File{{Path: "dir/data", Length: 4}})
+	base := t.TempDir()
+	store := newMoveTestStore(t, info, base, nil)
+	store.parts = NewPartFiles(&store.info, base, ".part")
+	path := filepath.Join(base, "dir", "data")
+
+	data := []byte("abcd")
+	if err := store.WriteChunk(context.Background(), 0, 0, data); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".part"); err != nil {
+		t.Fatalf("new file is not written under its part name: %v", err)
+	}
+
+	if err := store.SetFileComplete(0, true); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".part"); !os.IsNotExist(err) {
+		t.Fatalf("part file still exists: %v", err)
+	}
+	buf := make([]byte, len(data))
+	if _, err := store.ReadChunk(context.Background(), 0, 0, buf); err != nil || !bytes.Equal(buf, data) {
+		t.Fatalf("ReadChunk after completion = %q, %v", buf, err)
+	}
+
+	if err := store.SetFileComplete(0, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + ".part"); err != nil {
+		t.Fatalf("incomplete file is not renamed back to its part name: %v", err)
+	}
+}
+
+func TestFileStorePartFilesMoveAndRename(t *testing.T) {
+	info := moveTestInfo([]meta.File{{Path: "data", Length: 4}})
+	source := t.TempDir()
+	target := filepath.Join(t.TempDir(), "target")
+	writeMoveTestFiles(t, source, "data.part")
+	store := newMoveTestStore(t, info, source, nil)
+	store.parts = NewPartFiles(&store.info, source, ".part")
+
+	if err := store.Move(context.Background(), target, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.RenameFile(0, "renamed"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "renamed.part")); err != nil {
+		t.Fatalf("part file lost its suffix: %v", err)
+	}
+}
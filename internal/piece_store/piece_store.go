@@ -6,6 +6,8 @@ package piece_store
 import (
 	"context"
 	"crypto/sha1"
+	"fmt"
+	"slices"
 	"sync"
 
 	"neptune/internal/meta"
@@ -21,6 +23,23 @@ type Store interface {
 	ReadChunk(ctx context.Context, pieceIndex uint32, begin uint32, data []byte) (int, error)
 	VerifyPiece(ctx context.Context, pieceIndex uint32, expected [sha1.Size]byte) (bool, error)
 	Move(ctx context.Context, target string, report MoveProgressFunc) error
+	// Relocate points the store at data that was moved to basePath outside
+	// of the store. No file is touched.
+	Relocate(basePath string)
+	// RenameFile moves file index to path, relative to the base path unless
+	// absolute, and records the new path in the torrent info.
+	RenameFile(index int, path string) error
+	// UnshareFile gives file index, which only read the data of an existing
+	// file so far, its own data at path and writes it from then on. The data
+	// is a reflink clone of the existing file, or nothing when the file
+	// system can not clone it. It reports whether the data was kept.
+	UnshareFile(index int, path string) (bool, error)
+	// SetFileComplete switches file index between its name and its part
+	// name, if part files are enabled.
+	SetFileComplete(index int, complete bool) error
+	// SetMountPoint makes writes fail with a *MountError while the base path
+	// is not on mount. An empty mount disables the check.
+	SetMountPoint(mount string)
 }
 
 type MovePhase uint8
@@ -32,6 +51,20 @@ const (
 	MoveCleaning
 )
 
+func (p MovePhase) String() string {
+	switch p {
+	case MoveWaiting:
+		return "waiting"
+	case MoveCopying:
+		return "copying"
+	case MoveCommitting:
+		return "committing"
+	case MoveCleaning:
+		return "cleaning"
+	}
+	return fmt.Sprintf("MovePhase(%d)", p)
+}
+
 type MoveProgress struct {
 	Phase       MovePhase
 	FilesDone   int
@@ -49,17 +82,25 @@ type MoveProgressFunc func(MoveProgress)
 type FileStore struct {
 	fp               *filepool.FilePool
 	selectedFilesSet *bm.Bitmap
+	sharedFiles      *bm.LockFreeBitmap // never written, nil when there are none
 	fallocatedBm     *bm.LockFreeBitmap
+	parts            *PartFiles
 	ioc              *gfs.IOContext
 	diskIO           *gfs.PathIO
 	basePath         string
+	mount            mountGuard
+	hashers          writeHashers
 	info             meta.Info
-	fallocate        bool
 	opMu             sync.RWMutex
+	fallocate        bool
 }
 
 // NewFileStore creates a FileStore for the given torrent info and base path.
-func NewFileStore(info meta.Info, basePath string, fp *filepool.FilePool, ioc *gfs.IOContext, selectedFilesSet *bm.Bitmap, fallocate bool) *FileStore {
+// Files are written under their part name as long as parts says so. Writes to
+// sharedFiles are dropped, a nil sharedFiles writes every file.
+func NewFileStore(info meta.Info, basePath string, fp *filepool.FilePool, ioc *gfs.IOContext, selectedFilesSet *bm.Bitmap, sharedFiles *bm.LockFreeBitmap, fallocate bool, parts *PartFiles) *FileStore {
+	// the store renames files under opMu, on its own copy of the paths
+	info.Files = slices.Clone(info.Files)
 	return &FileStore{
 		info:             info,
 		basePath:         basePath,
@@ -67,7 +108,9 @@ func NewFileStore(info meta.Info, basePath string, fp *filepool.FilePool, ioc *g
 		ioc:              ioc,
 		diskIO:           ioc.ForPath(basePath),
 		selectedFilesSet: selectedFilesSet,
+		sharedFiles:      sharedFiles,
 		fallocatedBm:     bm.NewLockFreeBitmap(uint32(len(info.Files))),
 		fallocate:        fallocate,
+		parts:            parts,
 	}
 }
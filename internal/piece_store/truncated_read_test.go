@@ -43,7 +43,7 @@ func TestReadChunkTruncatedFileIsUnexpectedEOF(t *testing.T) {
 	selected := bm.New(uint32(len(info.Files)))
 	selected.Fill()
 
-	s := NewFileStore(info, basePath, filepool.New(), ioc, selected, false)
+	s := NewFileStore(info, basePath, filepool.New(), ioc, selected, nil, false, nil)
 	t.Cleanup(func() {
 		s.fp.InvalidatePaths([]string{s.filePath(0)})
 	})
@@ -75,7 +75,7 @@ func TestVerifyPieceTruncatedFileErrors(t *testing.T) {
 	selected := bm.New(uint32(len(info.Files)))
 	selected.Fill()
 
-	s := NewFileStore(info, basePath, filepool.New(), ioc, selected, false)
+	s := NewFileStore(info, basePath, filepool.New(), ioc, selected, nil, false, nil)
 	t.Cleanup(func() {
 		s.fp.InvalidatePaths([]string{s.filePath(0)})
 	})
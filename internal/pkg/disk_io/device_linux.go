@@ -37,6 +37,65 @@ func discoverPath(path string) deviceInfo {
 	}
 }
 
+// MountPointOf returns the mount point of the filesystem holding path, or of
+// its nearest existing parent. It returns an empty string if the mounts cannot
+// be read.
+func MountPointOf(path string) string {
+	data, err := os.ReadFile(mountInfoPath)
+	if err != nil {
+		return ""
+	}
+	path, err = existingParent(path)
+	if err != nil {
+		return ""
+	}
+	return longestMountPoint(string(data), path)
+}
+
+func existingParent(path string) (string, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	for {
+		resolved, err := filepath.EvalSymlinks(path)
+		if err == nil {
+			return resolved, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return "", err
+		}
+		path = parent
+	}
+}
+
+// longestMountPoint returns the deepest mount point in mountinfo that
+// contains path. Later mounts shadow earlier ones on the same mount point.
+func longestMountPoint(mountinfo string, path string) string {
+	var found string
+	for line := range strings.SplitSeq(mountinfo, "\n") {
+		device, ok := parseMountInfoLine(line)
+		if !ok || !containsPath(device.mountPoint, path) {
+			continue
+		}
+		if len(device.mountPoint) >= len(found) {
+			found = device.mountPoint
+		}
+	}
+	return found
+}
+
+func containsPath(dir, path string) bool {
+	if dir == "/" || dir == path {
+		return true
+	}
+	return strings.HasPrefix(path, dir) && path[len(dir)] == filepath.Separator
+}
+
 func discoverDevices() []deviceInfo {
 	data, err := os.ReadFile(mountInfoPath)
 	if err != nil {
@@ -83,10 +142,30 @@ func parseMountInfoLine(line string) (deviceInfo, bool) {
 	return deviceInfo{
 		id:         id,
 		filesystem: fields[separator+1],
-		mountPoint: fields[4],
+		mountPoint: unescapeMountInfo(fields[4]),
 	}, true
 }
 
+// unescapeMountInfo decodes the octal escapes mountinfo uses for spaces, tabs,
+// newlines and backslashes in paths.
+func unescapeMountInfo(value string) string {
+	if !strings.Contains(value, "\\") {
+		return value
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+4 <= len(value) {
+			if n, err := strconv.ParseUint(value[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(value[i])
+	}
+	return b.String()
+}
+
 func parseDeviceID(value string) (DeviceID, bool) {
 	major, minor, ok := strings.Cut(value, ":")
 	if !ok {
@@ -40,3 +40,37 @@ func TestDiscoverPathUsesNearestExistingParent(t *testing.T) {
 		t.Fatalf("device = %v, want %v", got.id, want.id)
 	}
 }
+
+func TestParseMountInfoLineUnescapesMountPoint(t *testing.T) {
+	device, ok := parseMountInfoLine(`36 25 8:1 / /mnt/my\040disk rw,relatime - ext4 /dev/sda1 rw`)
+	if !ok || device.mountPoint != "/mnt/my disk" {
+		t.Fatalf("device = %#v, %v", device, ok)
+	}
+}
+
+func TestLongestMountPoint(t *testing.T) {
+	mountinfo := "1 0 8:1 / / rw - ext4 /dev/sda1 rw\n" +
+		"2 1 8:2 / /mnt rw - ext4 /dev/sda2 rw\n" +
+		"3 2 8:3 / /mnt/data rw - ext4 /dev/sda3 rw\n"
+	for path, want := range map[string]string{
+		"/mnt/data/torrent": "/mnt/data",
+		"/mnt/data":         "/mnt/data",
+		"/mnt/database":     "/mnt",
+		"/home":             "/",
+	} {
+		if got := longestMountPoint(mountinfo, path); got != want {
+			t.Fatalf("longestMountPoint(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestMountPointOfMissingPath(t *testing.T) {
+	dir := t.TempDir()
+	want := MountPointOf(dir)
+	if want == "" {
+		t.Skip("mountinfo is not readable")
+	}
+	if got := MountPointOf(filepath.Join(dir, "missing", "torrent")); got != want {
+		t.Fatalf("mount = %q, want %q", got, want)
+	}
+}
@@ -12,3 +12,8 @@ func discoverPath(string) deviceInfo {
 func discoverDevices() []deviceInfo {
 	return []deviceInfo{defaultDeviceInfo()}
 }
+
+// MountPointOf returns an empty string: mounts are only detected on Linux.
+func MountPointOf(string) string {
+	return ""
+}
@@ -13,3 +13,9 @@ func TestDiscoverPathReturnsDefaultDevice(t *testing.T) {
 		t.Fatalf("device = %#v", device)
 	}
 }
+
+func TestMountPointOfIsUnknown(t *testing.T) {
+	if mount := MountPointOf("ignored"); mount != "" {
+		t.Fatalf("mount = %q", mount)
+	}
+}
@@ -19,10 +19,14 @@ const (
 	hddWorkers     = 1
 	hddQueueOps    = 128
 	hddQueuedBytes = 64 << 20
+	hddScrubBytes  = 16 << 20
+	hddScrubReads  = 16
 
 	ssdWorkers     = 8
 	ssdQueueOps    = 512
 	ssdQueuedBytes = 256 << 20
+	ssdScrubBytes  = 128 << 20
+	ssdScrubReads  = 256
 )
 
 // ErrClosed is returned when an operation is submitted after shutdown begins.
@@ -63,6 +67,7 @@ func defaultDeviceInfo() deviceInfo {
 }
 
 type profile struct {
+	scrub       ScrubBudget
 	workers     int
 	queueOps    int
 	queuedBytes int64
@@ -70,9 +75,33 @@ type profile struct {
 
 func profileFor(class DeviceClass) profile {
 	if class == DeviceSSD {
-		return profile{workers: ssdWorkers, queueOps: ssdQueueOps, queuedBytes: ssdQueuedBytes}
+		return profile{
+			workers:     ssdWorkers,
+			queueOps:    ssdQueueOps,
+			queuedBytes: ssdQueuedBytes,
+			scrub:       ScrubBudget{BytesPerSecond: ssdScrubBytes, ReadsPerSecond: ssdScrubReads},
+		}
+	}
+	return profile{
+		workers:     hddWorkers,
+		queueOps:    hddQueueOps,
+		queuedBytes: hddQueuedBytes,
+		scrub:       ScrubBudget{BytesPerSecond: hddScrubBytes, ReadsPerSecond: hddScrubReads},
 	}
-	return profile{workers: hddWorkers, queueOps: hddQueueOps, queuedBytes: hddQueuedBytes}
+}
+
+// ScrubBudget is how much background data scrubbing may read from a device,
+// small enough to leave most of it to uploads and downloads.
+type ScrubBudget struct {
+	BytesPerSecond int64
+	// ReadsPerSecond bounds the seeks, which cost more than the bytes on
+	// spinning disks.
+	ReadsPerSecond int64
+}
+
+// ScrubBudgetFor returns the scrub budget of a device class.
+func ScrubBudgetFor(class DeviceClass) ScrubBudget {
+	return profileFor(class).scrub
 }
 
 type metrics struct {
@@ -144,6 +173,7 @@ type Manager struct {
 	metrics      metrics
 	executor     Executor
 	devices      map[DeviceID]DeviceClass
+	mounts       map[DeviceID]string
 	queues       map[DeviceID]*Queue
 	defaultQueue *Queue
 	closed       bool
@@ -155,10 +185,12 @@ func New(executor Executor) *Manager {
 		metrics:  newMetrics(),
 		executor: executor,
 		devices:  make(map[DeviceID]DeviceClass),
+		mounts:   make(map[DeviceID]string),
 		queues:   make(map[DeviceID]*Queue),
 	}
 	for _, device := range discoverDevices() {
 		m.devices[device.id] = device.class
+		m.mounts[device.id] = device.mountPoint
 		log.Info().
 			Stringer("device", device.id).
 			Str("device_class", device.class.String()).
@@ -182,6 +214,32 @@ func (m *Manager) QueueForPath(path string) *Queue {
 	return m.queueForDevice(device)
 }
 
+// DeviceForPath returns the device holding path and its class. Paths that
+// cannot be resolved map to the zero DeviceID, shared with the default queue.
+func (m *Manager) DeviceForPath(path string) (DeviceID, DeviceClass) {
+	device := discoverPath(path)
+	if device.id == (DeviceID{}) {
+		return DeviceID{}, DeviceHDD
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if class, exists := m.devices[device.id]; exists {
+		return device.id, class
+	}
+	return device.id, device.class
+}
+
+// MountPoint returns where the device is mounted, or an empty string if it
+// was not discovered at startup.
+func (m *Manager) MountPoint(id DeviceID) string {
+	if id == (DeviceID{}) {
+		return ""
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.mounts[id]
+}
+
 func (m *Manager) queueForDevice(device deviceInfo) *Queue {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -235,3 +235,13 @@ func TestManagerGroupsQueuesByDevice(t *testing.T) {
 		t.Fatalf("SSD queue class = %q", ssd.devClass)
 	}
 }
+
+func TestScrubBudgetFollowsDeviceClass(t *testing.T) {
+	hdd, ssd := ScrubBudgetFor(DeviceHDD), ScrubBudgetFor(DeviceSSD)
+	if hdd.BytesPerSecond <= 0 || hdd.ReadsPerSecond <= 0 {
+		t.Fatalf("hdd budget must be limited, got %+v", hdd)
+	}
+	if ssd.BytesPerSecond <= hdd.BytesPerSecond || ssd.ReadsPerSecond <= hdd.ReadsPerSecond {
+		t.Fatalf("ssd budget %+v must exceed hdd budget %+v", ssd, hdd)
+	}
+}
@@ -0,0 +1,29 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+// Package diskfree reports the free space of file systems.
+package diskfree
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// AvailableFor is Available for a path that may not exist yet, such as the
+// base path of a torrent not started yet. It uses the nearest existing parent.
+func AvailableFor(path string) (uint64, error) {
+	for {
+		_, err := os.Stat(path)
+		if !errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			break
+		}
+		path = parent
+	}
+	return Available(path)
+}
@@ -0,0 +1,26 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package diskfree
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvailable(t *testing.T) {
+	n, err := Available(t.TempDir())
+	require.NoError(t, err)
+	require.NotZero(t, n)
+
+	_, err = Available(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
+
+func TestAvailableFor(t *testing.T) {
+	n, err := AvailableFor(filepath.Join(t.TempDir(), "missing", "dir"))
+	require.NoError(t, err)
+	require.NotZero(t, n)
+}
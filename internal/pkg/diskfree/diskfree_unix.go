@@ -0,0 +1,20 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !windows
+
+package diskfree
+
+import (
+	"golang.org/x/sys/unix"
+)
+
+// Available returns the bytes an unprivileged user can still write to the
+// file system holding path.
+func Available(path string) (uint64, error) {
+	var st unix.Statfs_t
+	if err := unix.Statfs(path, &st); err != nil {
+		return 0, err
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), nil
+}
@@ -0,0 +1,24 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build windows
+
+package diskfree
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// Available returns the bytes the current user can still write to the
+// volume holding path.
+func Available(path string) (uint64, error) {
+	p, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+	var available uint64
+	if err := windows.GetDiskFreeSpaceEx(p, &available, nil, nil); err != nil {
+		return 0, err
+	}
+	return available, nil
+}
@@ -34,6 +34,17 @@ func (ioc *IOContext) ForPath(path string) *PathIO {
 	return &PathIO{queue: ioc.scheduler.manager.QueueForPath(path)}
 }
 
+// DeviceForPath reports the device and device class the scheduler uses for path.
+func (ioc *IOContext) DeviceForPath(path string) (disk_io.DeviceID, disk_io.DeviceClass) {
+	return ioc.scheduler.manager.DeviceForPath(path)
+}
+
+// MountPoint reports where a device returned by DeviceForPath is mounted, or
+// an empty string if it is unknown.
+func (ioc *IOContext) MountPoint(id disk_io.DeviceID) string {
+	return ioc.scheduler.manager.MountPoint(id)
+}
+
 func (ioc *IOContext) Collectors() []prometheus.Collector {
 	return ioc.scheduler.manager.Collectors()
 }
@@ -0,0 +1,40 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+// Package reflink makes copy-on-write clones of files, which share their data
+// blocks with the source until either is written.
+package reflink
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrUnsupported is returned when the platform can not clone files.
+var ErrUnsupported = errors.New("reflink is not supported on this platform")
+
+// Clone creates dst as a clone of src. dst must not exist. It fails when the
+// file system does not support clones or src and dst are on different file
+// systems, and removes dst again.
+func Clone(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return err
+	}
+
+	err = clone(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(dst)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build linux
+
+package reflink
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+func clone(dst, src *os.File) error {
+	return unix.IoctlFileClone(int(dst.Fd()), int(src.Fd()))
+}
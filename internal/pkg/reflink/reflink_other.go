@@ -0,0 +1,14 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//go:build !linux
+
+package reflink
+
+import (
+	"os"
+)
+
+func clone(dst, src *os.File) error {
+	return ErrUnsupported
+}
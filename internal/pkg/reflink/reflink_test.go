@@ -0,0 +1,30 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package reflink
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src")
+	dst := filepath.Join(dir, "dst")
+	require.NoError(t, os.WriteFile(src, []byte("data"), 0o644))
+
+	// most test file systems can not clone, dst must not be left behind then
+	if err := Clone(src, dst); err != nil {
+		require.NoFileExists(t, dst)
+		t.Skipf("file system does not support reflink: %v", err)
+	}
+	b, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	require.Equal(t, "data", string(b))
+
+	require.Error(t, Clone(src, dst), "dst exists")
+}
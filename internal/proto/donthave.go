@@ -0,0 +1,24 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package proto
+
+import (
+	"encoding/binary"
+	"io"
+)
+
+// SendDontHave tells the peer we no longer have pieceIndex, with the
+// lt_donthave extension id the peer mapped in its extension handshake.
+//
+// https://www.bittorrent.org/beps/bep_0054.html
+func SendDontHave(conn io.Writer, id ExtensionMessage, pieceIndex uint32) error {
+	buf := smallBufPool.Get()
+	defer smallBufPool.Put(buf)
+
+	buf.B = binary.BigEndian.AppendUint32(buf.B, 6)
+	buf.B = append(buf.B, byte(Extended), byte(id))
+	buf.B = binary.BigEndian.AppendUint32(buf.B, pieceIndex)
+	_, err := conn.Write(buf.B)
+	return err
+}
@@ -4,6 +4,7 @@
 package proto_test
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -30,3 +31,11 @@ func TestEncodeExtHandshake(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, `d1:md6:ut_pexi10ee4:reqqi20e1:v13:neptune 0.0.1e`, string(raw))
 }
+
+func TestSendDontHave(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, proto.SendDontHave(&buf, 7, 258))
+	require.Equal(t, []byte{0, 0, 0, 6, byte(proto.Extended), 7, 0, 0, 1, 2}, buf.Bytes())
+}
@@ -0,0 +1,155 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package session
+
+import (
+	"bytes"
+	"cmp"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	"neptune/internal/metainfo"
+)
+
+// PathOwner is a file of a torrent, at the absolute path where it is stored.
+type PathOwner struct {
+	Path     string
+	File     int
+	InfoHash metainfo.Hash
+}
+
+// PathIndex maps the absolute path of every torrent file to the torrents
+// that own it, so two torrents do not write into the same file through their
+// own stores. A nil PathIndex is empty and ignores updates.
+type PathIndex struct {
+	owners   map[string][]PathOwner
+	torrent  map[metainfo.Hash][]string
+	reserved map[*pathReservation]struct{}
+	mu       sync.RWMutex
+}
+
+// pathReservation is a set of paths a torrent is about to own, such as the
+// target of a running move.
+type pathReservation struct {
+	files map[string]int
+	ih    metainfo.Hash
+}
+
+func NewPathIndex() *PathIndex {
+	return &PathIndex{
+		owners:   make(map[string][]PathOwner),
+		torrent:  make(map[metainfo.Hash][]string),
+		reserved: make(map[*pathReservation]struct{}),
+	}
+}
+
+// Set replaces the paths owned by torrent ih. files maps each absolute path
+// to the file index it holds.
+func (x *PathIndex) Set(ih metainfo.Hash, files map[string]int) {
+	if x == nil {
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+
+	x.removeLocked(ih)
+	paths := make([]string, 0, len(files))
+	for path, file := range files {
+		x.owners[path] = append(x.owners[path], PathOwner{Path: path, File: file, InfoHash: ih})
+		paths = append(paths, path)
+	}
+	x.torrent[ih] = paths
+}
+
+// Reserve makes torrent ih an owner of files, on top of the paths it owns,
+// until release is called. The torrent records its new paths with Set before
+// releasing them.
+func (x *PathIndex) Reserve(ih metainfo.Hash, files map[string]int) (release func()) {
+	if x == nil {
+		return func() {}
+	}
+	r := &pathReservation{ih: ih, files: files}
+	x.mu.Lock()
+	x.reserved[r] = struct{}{}
+	x.mu.Unlock()
+
+	return func() {
+		x.mu.Lock()
+		delete(x.reserved, r)
+		x.mu.Unlock()
+	}
+}
+
+// Remove drops every path owned by torrent ih.
+func (x *PathIndex) Remove(ih metainfo.Hash) {
+	if x == nil {
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.removeLocked(ih)
+}
+
+func (x *PathIndex) removeLocked(ih metainfo.Hash) {
+	for _, path := range x.torrent[ih] {
+		owners := slices.DeleteFunc(x.owners[path], func(o PathOwner) bool { return o.InfoHash == ih })
+		if len(owners) == 0 {
+			delete(x.owners, path)
+		} else {
+			x.owners[path] = owners
+		}
+	}
+	delete(x.torrent, ih)
+}
+
+// FileOwners returns the torrents that own or reserved the file at path.
+func (x *PathIndex) FileOwners(path string) []PathOwner {
+	if x == nil {
+		return nil
+	}
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+
+	owners := slices.Clone(x.owners[path])
+	for r := range x.reserved {
+		file, ok := r.files[path]
+		if !ok || slices.ContainsFunc(owners, func(o PathOwner) bool { return o.InfoHash == r.ih }) {
+			continue
+		}
+		owners = append(owners, PathOwner{Path: path, File: file, InfoHash: r.ih})
+	}
+	return owners
+}
+
+// Lookup returns the owners of the file at path, or of every file under it
+// when path is a directory, sorted by path.
+func (x *PathIndex) Lookup(path string) []PathOwner {
+	if x == nil {
+		return nil
+	}
+	path = filepath.Clean(path)
+	prefix := path
+	if !strings.HasSuffix(prefix, string(filepath.Separator)) {
+		prefix += string(filepath.Separator)
+	}
+
+	x.mu.RLock()
+	var found []PathOwner
+	for p, owners := range x.owners {
+		if p == path || strings.HasPrefix(p, prefix) {
+			found = append(found, owners...)
+		}
+	}
+	x.mu.RUnlock()
+
+	slices.SortFunc(found, func(a, b PathOwner) int {
+		if c := cmp.Compare(a.Path, b.Path); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.InfoHash[:], b.InfoHash[:])
+	})
+	return found
+}
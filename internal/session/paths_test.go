@@ -0,0 +1,58 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package session
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"neptune/internal/metainfo"
+)
+
+func TestPathIndex(t *testing.T) {
+	x := NewPathIndex()
+	a := metainfo.Hash{1}
+	b := metainfo.Hash{2}
+
+	x.Set(a, map[string]int{"/data/show/e01.mkv": 0, "/data/show/e02.mkv": 1})
+	x.Set(b, map[string]int{"/data/show/e01.mkv": 0, "/data/show2/e01.mkv": 1})
+
+	assert.Equal(t, []PathOwner{
+		{Path: "/data/show/e01.mkv", File: 0, InfoHash: a},
+		{Path: "/data/show/e01.mkv", File: 0, InfoHash: b},
+		{Path: "/data/show/e02.mkv", File: 1, InfoHash: a},
+	}, x.Lookup("/data/show/"), "a sibling directory with the same prefix is not under it")
+
+	// Set replaces the old paths
+	x.Set(a, map[string]int{"/data/movie.mkv": 0})
+	assert.Equal(t, []PathOwner{{Path: "/data/show/e01.mkv", File: 0, InfoHash: b}}, x.FileOwners("/data/show/e01.mkv"))
+	assert.Empty(t, x.FileOwners("/data/show/e02.mkv"))
+
+	x.Remove(b)
+	assert.Equal(t, []PathOwner{{Path: "/data/movie.mkv", File: 0, InfoHash: a}}, x.Lookup("/data"))
+
+	var empty *PathIndex
+	empty.Set(a, map[string]int{"/data/movie.mkv": 0})
+	assert.Nil(t, empty.Lookup("/data"))
+}
+
+func TestPathIndexReserve(t *testing.T) {
+	x := NewPathIndex()
+	a := metainfo.Hash{1}
+	x.Set(a, map[string]int{"/data/movie.mkv": 0})
+
+	release := x.Reserve(a, map[string]int{"/new/movie.mkv": 0, "/data/movie.mkv": 0})
+	assert.Equal(t, []PathOwner{{Path: "/new/movie.mkv", File: 0, InfoHash: a}}, x.FileOwners("/new/movie.mkv"))
+	assert.Len(t, x.FileOwners("/data/movie.mkv"), 1, "a torrent is listed once")
+
+	// the move finished: the torrent owns the new paths, then releases them
+	x.Set(a, map[string]int{"/new/movie.mkv": 0})
+	release()
+	assert.Equal(t, []PathOwner{{Path: "/new/movie.mkv", File: 0, InfoHash: a}}, x.FileOwners("/new/movie.mkv"))
+	assert.Empty(t, x.FileOwners("/data/movie.mkv"))
+
+	var empty *PathIndex
+	empty.Reserve(a, map[string]int{"/data/movie.mkv": 0})()
+}
@@ -24,6 +24,7 @@ import (
 	"neptune/internal/bep40"
 	"neptune/internal/config"
 	"neptune/internal/dht"
+	"neptune/internal/hashcheck"
 	"neptune/internal/mse"
 	"neptune/internal/pkg/filepool"
 	"neptune/internal/pkg/flowrate"
@@ -46,6 +47,8 @@ type Session struct {
 	DHT                        *dht.DHT
 	FilePool                   *filepool.FilePool
 	IOContext                  *gfs.IOContext
+	HashCheck                  *hashcheck.Scheduler
+	Paths                      *PathIndex
 	HTTP                       *resty.Client
 	ConnSem                    *semaphore.Weighted
 	DialSem                    *semaphore.Weighted
@@ -110,6 +113,20 @@ func New(cfg config.Config, sessionPath string, debug bool) *Session {
 		panic(fmt.Sprintf("invalid `application.crypto` config: %v", err))
 	}
 
+	if _, err := config.ParseMissingDataPolicy(cfg.App.MissingData); err != nil {
+		panic(fmt.Sprintf("invalid `application.missing-data` config: %v", err))
+	}
+
+	if _, err := config.ParsePathConflictPolicy(cfg.App.PathConflict); err != nil {
+		panic(fmt.Sprintf("invalid `application.path-conflict` config: %v", err))
+	}
+
+	if cfg.App.PartFiles {
+		if err := config.ValidatePartSuffix(cfg.App.PartSuffix); err != nil {
+			panic(fmt.Sprintf("invalid `application.part-suffix` config: %v", err))
+		}
+	}
+
 	switch cryptoMode {
 	case config.CryptoForce:
 		mseSelector = mse.ForceCrypto
@@ -139,6 +156,13 @@ func New(cfg config.Config, sessionPath string, debug bool) *Session {
 
 	v4, v6, _ := util.GetIPAddress()
 
+	ioc := gfs.NewIOContext()
+
+	recheckSpeedLimit := cfg.App.RecheckSpeedLimit
+	if global.Dev && recheckSpeedLimit == 0 {
+		recheckSpeedLimit = 100 << 20
+	}
+
 	s := &Session{
 		Ctx:    ctx,
 		Cancel: cancel,
@@ -147,8 +171,14 @@ func New(cfg config.Config, sessionPath string, debug bool) *Session {
 
 		DHT:       nil, // disabled for now
 		FilePool:  filepool.New(),
-		IOContext: gfs.NewIOContext(),
-		HTTP:      newTrackerHTTPClient(cfg.App.MaxHTTPParallel),
+		IOContext: ioc,
+		HashCheck: hashcheck.New(hashcheck.Config{
+			Workers:    cfg.App.HashCheckWorkers,
+			PerDevice:  cfg.App.ChecksPerDevice,
+			SpeedLimit: recheckSpeedLimit,
+		}, ioc.DeviceForPath),
+		Paths: NewPathIndex(),
+		HTTP:  newTrackerHTTPClient(cfg.App.MaxHTTPParallel),
 
 		ConnSem:     semaphore.NewWeighted(int64(cfg.App.GlobalConnectionLimit)),
 		DialSem:     semaphore.NewWeighted(max(int64(cfg.App.GlobalConnectionLimit)/10, 20)),
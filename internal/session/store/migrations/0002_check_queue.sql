@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS check_queue (
+	info_hash TEXT PRIMARY KEY,
+	position  INTEGER NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE resume ADD COLUMN partial_pieces TEXT;
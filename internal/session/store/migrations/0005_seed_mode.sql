@@ -0,0 +1 @@
+ALTER TABLE resume ADD COLUMN unverified BLOB;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS move_journal (
+	info_hash TEXT PRIMARY KEY,
+	source    TEXT NOT NULL,
+	target    TEXT NOT NULL,
+	files     TEXT NOT NULL,
+	committed INTEGER NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE resume ADD COLUMN complete_path TEXT NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+ALTER TABLE resume ADD COLUMN allocation INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1 @@
+ALTER TABLE resume ADD COLUMN mount_point TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE resume ADD COLUMN verified_at INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE resume ADD COLUMN scrub_cursor INTEGER NOT NULL DEFAULT 0;
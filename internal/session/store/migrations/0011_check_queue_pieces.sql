@@ -0,0 +1 @@
+ALTER TABLE check_queue ADD COLUMN pieces BLOB;
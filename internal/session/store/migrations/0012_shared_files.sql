@@ -0,0 +1 @@
+ALTER TABLE resume ADD COLUMN shared_files TEXT;
@@ -0,0 +1 @@
+ALTER TABLE resume ADD COLUMN complete_move_error TEXT NOT NULL DEFAULT '';
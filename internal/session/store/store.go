@@ -51,6 +51,30 @@ type Resume struct {
 	State              ResumeState
 	PiecePickStrategy  uint32
 	QueueWeight        int64
+	PartialPieces      []PartialPiece      // written blocks of incomplete pieces
+	FileStats          []FileStat          // size and mtime of each file when saved, by file index
+	Unverified         []byte              // seed mode: bitfield of pieces not hash checked yet. nil when not in seed mode.
+	CompletePath       string              // base path the data moves to when the download completes. empty when it stays.
+	Allocation         uint8               // how files get their disk space, see download.Allocation
+	MountPoint         string              // mount point BasePath was on when saved. empty when unknown.
+	VerifiedAt         timestamp.Timestamp // when all data was last hash checked, by a scrub or a full recheck. zero when never.
+	ScrubCursor        uint32              // next piece of an unfinished background scrub. 0 when none is in progress.
+	SharedFiles        []int               // indices of files holding data another torrent or the user owns, never written.
+	CompleteMoveError  string              // why the last move to CompletePath failed. empty when it did not.
+}
+
+// FileStat is the size and modification time of a torrent file as seen by the
+// last save. A missing file has Size -1.
+type FileStat struct {
+	Size    int64 `json:"size"`
+	ModTime int64 `json:"mtime"` // unix nanoseconds
+}
+
+// PartialPiece records the blocks already written for a piece that has not
+// passed its hash check yet. Blocks is a bitfield with one bit per block.
+type PartialPiece struct {
+	Blocks []byte `json:"blocks"`
+	Index  uint32 `json:"index"`
 }
 
 type migration struct {
@@ -187,6 +211,28 @@ func (s *Store) Upsert(r *Resume) error {
 	if err != nil {
 		return err
 	}
+	var partialPieces []byte
+	if len(r.PartialPieces) != 0 {
+		partialPieces, err = json.Marshal(r.PartialPieces)
+		if err != nil {
+			return err
+		}
+	}
+	var fileStats []byte
+	if r.FileStats != nil {
+		fileStats, err = json.Marshal(r.FileStats)
+		if err != nil {
+			return err
+		}
+	}
+
+	var sharedFiles []byte
+	if len(r.SharedFiles) != 0 {
+		sharedFiles, err = json.Marshal(r.SharedFiles)
+		if err != nil {
+			return err
+		}
+	}
 
 	// nil SelectedFiles means "all files" and is stored as NULL so it stays
 	// distinct from an explicitly empty selection.
@@ -201,8 +247,10 @@ func (s *Store) Upsert(r *Resume) error {
 	_, err = s.db.ExecContext(ctx, `INSERT INTO resume (
 			info_hash, base_path, bitfield, tags, custom, trackers, selected_files,
 			file_paths, download_speed_limit, upload_speed_limit, add_at, completed_at,
-			downloaded, uploaded, corrupted, tracker_key, state, piece_pick_strategy, queue_weight
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			downloaded, uploaded, corrupted, tracker_key, state, piece_pick_strategy, queue_weight,
+			partial_pieces, file_stats, unverified, complete_path, allocation, mount_point,
+			verified_at, scrub_cursor, shared_files, complete_move_error
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(info_hash) DO UPDATE SET
 			base_path = excluded.base_path,
 			bitfield = excluded.bitfield,
@@ -221,7 +269,17 @@ func (s *Store) Upsert(r *Resume) error {
 			tracker_key = excluded.tracker_key,
 			state = excluded.state,
 			piece_pick_strategy = excluded.piece_pick_strategy,
-			queue_weight = excluded.queue_weight`,
+			queue_weight = excluded.queue_weight,
+			partial_pieces = excluded.partial_pieces,
+			file_stats = excluded.file_stats,
+			unverified = excluded.unverified,
+			complete_path = excluded.complete_path,
+			allocation = excluded.allocation,
+			mount_point = excluded.mount_point,
+			verified_at = excluded.verified_at,
+			scrub_cursor = excluded.scrub_cursor,
+			shared_files = excluded.shared_files,
+			complete_move_error = excluded.complete_move_error`,
 		r.InfoHash,
 		r.BasePath,
 		r.Bitfield,
@@ -241,10 +299,32 @@ func (s *Store) Upsert(r *Resume) error {
 		r.State,
 		r.PiecePickStrategy,
 		r.QueueWeight,
+		partialPieces,
+		fileStats,
+		r.Unverified,
+		r.CompletePath,
+		r.Allocation,
+		r.MountPoint,
+		r.VerifiedAt.UnixNano(),
+		r.ScrubCursor,
+		sharedFiles,
+		r.CompleteMoveError,
 	)
 	return err
 }
 
+// SaveFileStats updates only the file stats of a saved torrent. It is used
+// after the last write of a download, when the rest of the record is already
+// up to date.
+func (s *Store) SaveFileStats(infoHash string, stats []FileStat) error {
+	data, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(context.Background(), `UPDATE resume SET file_stats = ? WHERE info_hash = ?`, data, infoHash)
+	return err
+}
+
 func (s *Store) Delete(infoHash string) error {
 	_, err := s.db.ExecContext(context.Background(), `DELETE FROM resume WHERE info_hash = ?`, infoHash)
 	return err
@@ -262,7 +342,9 @@ func (s *Store) All() ([]Resume, error) {
 	rows, err := s.db.QueryContext(ctx, `SELECT
 		info_hash, base_path, bitfield, tags, custom, trackers, selected_files,
 		file_paths, download_speed_limit, upload_speed_limit, add_at, completed_at,
-		downloaded, uploaded, corrupted, tracker_key, state, piece_pick_strategy, queue_weight
+		downloaded, uploaded, corrupted, tracker_key, state, piece_pick_strategy, queue_weight,
+		partial_pieces, file_stats, unverified, complete_path, allocation, mount_point,
+		verified_at, scrub_cursor, shared_files, complete_move_error
 	FROM resume`)
 	if err != nil {
 		return nil, err
@@ -276,7 +358,11 @@ func (s *Store) All() ([]Resume, error) {
 			trackers           []byte
 			selectedFiles      []byte
 			filePaths          []byte
+			partialPieces      []byte
+			fileStats          []byte
+			sharedFiles        []byte
 			addAt, completedAt int64
+			verifiedAt         int64
 		)
 		if err := rows.Scan(
 			&r.InfoHash,
@@ -298,6 +384,16 @@ func (s *Store) All() ([]Resume, error) {
 			&r.State,
 			&r.PiecePickStrategy,
 			&r.QueueWeight,
+			&partialPieces,
+			&fileStats,
+			&r.Unverified,
+			&r.CompletePath,
+			&r.Allocation,
+			&r.MountPoint,
+			&verifiedAt,
+			&r.ScrubCursor,
+			&sharedFiles,
+			&r.CompleteMoveError,
 		); err != nil {
 			return nil, err
 		}
@@ -319,11 +415,133 @@ func (s *Store) All() ([]Resume, error) {
 		if err := json.Unmarshal(filePaths, &r.FilePaths); err != nil {
 			return nil, err
 		}
+		if partialPieces != nil {
+			if err := json.Unmarshal(partialPieces, &r.PartialPieces); err != nil {
+				return nil, err
+			}
+		}
+		if fileStats != nil {
+			if err := json.Unmarshal(fileStats, &r.FileStats); err != nil {
+				return nil, err
+			}
+		}
+
+		if sharedFiles != nil {
+			if err := json.Unmarshal(sharedFiles, &r.SharedFiles); err != nil {
+				return nil, err
+			}
+		}
 
 		r.AddAt = timestamp.New(time.Unix(0, addAt))
 		r.CompletedAt = timestamp.New(time.Unix(0, completedAt))
+		r.VerifiedAt = timestamp.New(time.Unix(0, verifiedAt))
 
 		out = append(out, r)
 	}
 	return out, rows.Err()
 }
+
+// QueuedCheck is a hash check waiting in the check queue.
+type QueuedCheck struct {
+	InfoHash string
+	Pieces   []byte // bitfield of the pieces to check. nil checks all of them.
+}
+
+// SaveCheckQueue replaces the persisted hash check queue with checks, in
+// queue order.
+func (s *Store) SaveCheckQueue(checks []QueuedCheck) error {
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM check_queue`); err != nil {
+		return err
+	}
+	for i, c := range checks {
+		if _, err := tx.ExecContext(ctx, `INSERT INTO check_queue (info_hash, position, pieces) VALUES (?, ?, ?)`, c.InfoHash, i, c.Pieces); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// CheckQueue returns the persisted hash check queue in order.
+func (s *Store) CheckQueue() ([]QueuedCheck, error) {
+	rows, err := s.db.QueryContext(context.Background(), `SELECT info_hash, pieces FROM check_queue ORDER BY position`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []QueuedCheck
+	for rows.Next() {
+		var c QueuedCheck
+		if err := rows.Scan(&c.InfoHash, &c.Pieces); err != nil {
+			return nil, err
+		}
+		out = append(out, c)
+	}
+	return out, rows.Err()
+}
+
+// MoveJournal records a data move in progress, so a move interrupted by a
+// crash can be finished or rolled back on the next start.
+type MoveJournal struct {
+	InfoHash string
+	Source   string
+	Target   string
+	Files    []string // file paths relative to Source and Target
+	// Committed is set once every file is at Target and the move can no
+	// longer be rolled back.
+	Committed bool
+}
+
+// SaveMove inserts or replaces the journal entry of a move.
+func (s *Store) SaveMove(m *MoveJournal) error {
+	files, err := json.Marshal(m.Files)
+	if err != nil {
+		return err
+	}
+	_, err = s.db.ExecContext(context.Background(), `INSERT INTO move_journal (info_hash, source, target, files, committed)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(info_hash) DO UPDATE SET
+			source = excluded.source,
+			target = excluded.target,
+			files = excluded.files,
+			committed = excluded.committed`,
+		m.InfoHash, m.Source, m.Target, files, m.Committed)
+	return err
+}
+
+func (s *Store) DeleteMove(infoHash string) error {
+	_, err := s.db.ExecContext(context.Background(), `DELETE FROM move_journal WHERE info_hash = ?`, infoHash)
+	return err
+}
+
+// Moves returns the journal entries of moves that did not finish.
+func (s *Store) Moves() ([]MoveJournal, error) {
+	rows, err := s.db.QueryContext(context.Background(), `SELECT info_hash, source, target, files, committed FROM move_journal`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []MoveJournal
+	for rows.Next() {
+		var (
+			m     MoveJournal
+			files []byte
+		)
+		if err := rows.Scan(&m.InfoHash, &m.Source, &m.Target, &files, &m.Committed); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(files, &m.Files); err != nil {
+			return nil, err
+		}
+		out = append(out, m)
+	}
+	return out, rows.Err()
+}
@@ -132,6 +132,16 @@ func TestUpsertAllRoundTrip(t *testing.T) {
 		State:              ResumeActive,
 		PiecePickStrategy:  1,
 		QueueWeight:        42,
+		PartialPieces:      []PartialPiece{{Index: 7, Blocks: []byte{0xf0}}},
+		FileStats:          []FileStat{{Size: 10, ModTime: 20}, {Size: -1}},
+		Unverified:         []byte{0x0f},
+		CompletePath:       "/complete",
+		Allocation:         2,
+		MountPoint:         "/mnt/data",
+		VerifiedAt:         timestamp.New(at.Add(2 * time.Hour)),
+		ScrubCursor:        9,
+		SharedFiles:        []int{0, 2},
+		CompleteMoveError:  "disk full",
 	}
 	require.NoError(t, s.Upsert(&want))
 
@@ -158,6 +168,16 @@ func TestUpsertAllRoundTrip(t *testing.T) {
 	require.Equal(t, want.State, got.State)
 	require.Equal(t, want.PiecePickStrategy, got.PiecePickStrategy)
 	require.Equal(t, want.QueueWeight, got.QueueWeight)
+	require.Equal(t, want.PartialPieces, got.PartialPieces)
+	require.Equal(t, want.FileStats, got.FileStats)
+	require.Equal(t, want.Unverified, got.Unverified)
+	require.Equal(t, want.CompletePath, got.CompletePath)
+	require.Equal(t, want.Allocation, got.Allocation)
+	require.Equal(t, want.MountPoint, got.MountPoint)
+	require.True(t, want.VerifiedAt.Equal(got.VerifiedAt.Time))
+	require.Equal(t, want.ScrubCursor, got.ScrubCursor)
+	require.Equal(t, want.SharedFiles, got.SharedFiles)
+	require.Equal(t, want.CompleteMoveError, got.CompleteMoveError)
 
 	n, err := s.Count()
 	require.NoError(t, err)
@@ -239,5 +259,57 @@ func TestNilFieldsRoundTrip(t *testing.T) {
 	require.Nil(t, all[0].Trackers)
 	require.Nil(t, all[0].FilePaths)
 	require.Nil(t, all[0].Bitfield)
+	require.Nil(t, all[0].PartialPieces)
+	require.Nil(t, all[0].FileStats)
+	require.Nil(t, all[0].Unverified)
 	require.Empty(t, all[0].TrackerKey)
 }
+
+func TestCheckQueueRoundTrip(t *testing.T) {
+	s := openTestStore(t)
+
+	queue, err := s.CheckQueue()
+	require.NoError(t, err)
+	require.Empty(t, queue)
+
+	require.NoError(t, s.SaveCheckQueue([]QueuedCheck{{InfoHash: "b"}, {InfoHash: "a", Pieces: []byte{0x60}}, {InfoHash: "c"}}))
+	queue, err = s.CheckQueue()
+	require.NoError(t, err)
+	require.Equal(t, []QueuedCheck{{InfoHash: "b"}, {InfoHash: "a", Pieces: []byte{0x60}}, {InfoHash: "c"}}, queue)
+
+	require.NoError(t, s.SaveCheckQueue([]QueuedCheck{{InfoHash: "c"}}))
+	queue, err = s.CheckQueue()
+	require.NoError(t, err)
+	require.Equal(t, []QueuedCheck{{InfoHash: "c"}}, queue)
+}
+
+func TestSaveFileStats(t *testing.T) {
+	s := openTestStore(t)
+
+	require.NoError(t, s.Upsert(&Resume{InfoHash: "h", BasePath: "/a", Downloaded: 5}))
+	require.NoError(t, s.SaveFileStats("h", []FileStat{{Size: 1, ModTime: 2}}))
+
+	all, err := s.All()
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	require.Equal(t, []FileStat{{Size: 1, ModTime: 2}}, all[0].FileStats)
+	require.Equal(t, int64(5), all[0].Downloaded)
+}
+
+func TestMoveJournalRoundTrip(t *testing.T) {
+	s := openTestStore(t)
+
+	m := MoveJournal{InfoHash: "h", Source: "/a", Target: "/b", Files: []string{"x/1", "x/2"}}
+	require.NoError(t, s.SaveMove(&m))
+	m.Committed = true
+	require.NoError(t, s.SaveMove(&m))
+
+	moves, err := s.Moves()
+	require.NoError(t, err)
+	require.Equal(t, []MoveJournal{m}, moves)
+
+	require.NoError(t, s.DeleteMove("h"))
+	moves, err = s.Moves()
+	require.NoError(t, err)
+	require.Empty(t, moves)
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+//nolint:dupl
+package web
+
+import (
+	"context"
+
+	"github.com/swaggest/usecase"
+	"github.com/trim21/errgo"
+
+	"neptune/internal/client"
+	"neptune/internal/metainfo"
+	"neptune/internal/web/jsonrpc"
+)
+
+// torrent.check_cancel, torrent.check_move_top, torrent.check_move_bottom
+
+type checkQueueRequest struct {
+	InfoHash string `description:"torrent file hash" json:"info_hash" required:"true"`
+}
+
+type checkQueueResponse struct{}
+
+func cancelCheck(h *jsonrpc.Handler, c *client.Client) {
+	checkQueueMethod(h, "torrent.check_cancel", "failed to cancel hash check", c.CancelCheck)
+}
+
+func moveCheckToTop(h *jsonrpc.Handler, c *client.Client) {
+	checkQueueMethod(h, "torrent.check_move_top", "failed to move hash check", c.MoveCheckToTop)
+}
+
+func moveCheckToBottom(h *jsonrpc.Handler, c *client.Client) {
+	checkQueueMethod(h, "torrent.check_move_bottom", "failed to move hash check", c.MoveCheckToBottom)
+}
+
+func checkQueueMethod(h *jsonrpc.Handler, name, msg string, fn func(metainfo.Hash) error) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *checkQueueRequest, res *checkQueueResponse) error {
+			ih, err := checkInfoHash(req.InfoHash)
+			if err != nil {
+				return err
+			}
+
+			if err := fn(ih); err != nil {
+				return CodeError(2, errgo.Wrap(err, msg))
+			}
+			return nil
+		},
+	)
+	u.SetName(name)
+	h.Add(u)
+}
+
+// client.pause_checks
+//
+// Stops admitting queued hash checks and suspends running ones before their
+// next piece read. The queue is kept and continues on client.resume_checks.
+
+type pauseChecksRequest struct{}
+
+type pauseChecksResponse struct{}
+
+func pauseChecks(h *jsonrpc.Handler, c *client.Client) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *pauseChecksRequest, res *pauseChecksResponse) error {
+			c.PauseChecks()
+			return nil
+		},
+	)
+	u.SetName("client.pause_checks")
+	h.Add(u)
+}
+
+// client.resume_checks
+
+type resumeChecksRequest struct{}
+
+type resumeChecksResponse struct{}
+
+func resumeChecks(h *jsonrpc.Handler, c *client.Client) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *resumeChecksRequest, res *resumeChecksResponse) error {
+			c.ResumeChecks()
+			return nil
+		},
+	)
+	u.SetName("client.resume_checks")
+	h.Add(u)
+}
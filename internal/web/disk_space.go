@@ -0,0 +1,32 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package web
+
+import (
+	"context"
+
+	"github.com/swaggest/usecase"
+
+	"neptune/internal/client"
+	"neptune/internal/web/jsonrpc"
+)
+
+// client.get_disk_space
+
+type getDiskSpaceRequest struct{}
+
+type getDiskSpaceResponse struct {
+	Mounts []client.DiskSpace `description:"filesystems holding torrent data, sorted by mount point" json:"mounts" required:"true"`
+}
+
+func getDiskSpace(h *jsonrpc.Handler, c *client.Client) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *getDiskSpaceRequest, res *getDiskSpaceResponse) error {
+			res.Mounts = c.DiskSpace()
+			return nil
+		},
+	)
+	u.SetName("client.get_disk_space")
+	h.Add(u)
+}
@@ -10,6 +10,7 @@ import (
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -200,6 +201,25 @@ func TestE2E(t *testing.T) {
 		require.Equal(t, infoHash, r.Torrents[0]["hash"])
 	})
 
+	// ---------------------------------------------------------------------------
+	// client.get_disk_space
+	// ---------------------------------------------------------------------------
+	t.Run("client.get_disk_space", func(t *testing.T) {
+		resp := makeJSONRPCRequest(t, url, token, "client.get_disk_space", struct{}{})
+		requireNoRPCError(t, resp)
+
+		var r struct {
+			Mounts []struct {
+				Available uint64 `json:"available"`
+				Torrents  int    `json:"torrents"`
+			} `json:"mounts"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Result, &r))
+		require.Len(t, r.Mounts, 1)
+		require.Equal(t, 1, r.Mounts[0].Torrents)
+		require.NotZero(t, r.Mounts[0].Available)
+	})
+
 	// ---------------------------------------------------------------------------
 	// torrent.files
 	// ---------------------------------------------------------------------------
@@ -368,6 +388,112 @@ func TestE2E(t *testing.T) {
 		requireNoRPCError(t, resp)
 	})
 
+	// ---------------------------------------------------------------------------
+	// torrent.check_progress
+	// ---------------------------------------------------------------------------
+	t.Run("torrent.check_progress", func(t *testing.T) {
+		resp := makeJSONRPCRequest(t, url, token, "torrent.check_progress", struct{}{})
+		requireNoRPCError(t, resp)
+		var r struct {
+			Checks []any `json:"checks"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Result, &r))
+		require.NotNil(t, r.Checks)
+	})
+
+	// ---------------------------------------------------------------------------
+	// client.pause_checks / client.resume_checks
+	// ---------------------------------------------------------------------------
+	t.Run("client.pause_checks", func(t *testing.T) {
+		resp := makeJSONRPCRequest(t, url, token, "client.pause_checks", struct{}{})
+		requireNoRPCError(t, resp)
+
+		resp = makeJSONRPCRequest(t, url, token, "torrent.check_progress", struct{}{})
+		requireNoRPCError(t, resp)
+		var r struct {
+			Paused bool `json:"paused"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Result, &r))
+		require.True(t, r.Paused)
+	})
+
+	t.Run("client.resume_checks", func(t *testing.T) {
+		resp := makeJSONRPCRequest(t, url, token, "client.resume_checks", struct{}{})
+		requireNoRPCError(t, resp)
+
+		resp = makeJSONRPCRequest(t, url, token, "torrent.check_progress", struct{}{})
+		requireNoRPCError(t, resp)
+		var r struct {
+			Paused bool `json:"paused"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Result, &r))
+		require.False(t, r.Paused)
+	})
+
+	// ---------------------------------------------------------------------------
+	// torrent.check_cancel / check_move_top / check_move_bottom (nothing queued)
+	// ---------------------------------------------------------------------------
+	for _, method := range []string{"torrent.check_cancel", "torrent.check_move_top", "torrent.check_move_bottom"} {
+		t.Run(method, func(t *testing.T) {
+			resp := makeJSONRPCRequest(t, url, token, method, map[string]any{
+				keyInfoHash: infoHash,
+			})
+			requireRPCError(t, resp, 2)
+		})
+	}
+
+	// ---------------------------------------------------------------------------
+	// torrent.move / torrent.move_cancel
+	// ---------------------------------------------------------------------------
+	t.Run("torrent.move_empty_target", func(t *testing.T) {
+		resp := makeJSONRPCRequest(t, url, token, "torrent.move", map[string]any{
+			keyInfoHash:        infoHash,
+			"target_base_path": "",
+		})
+		requireRPCError(t, resp, 1)
+	})
+
+	t.Run("torrent.move_not_found", func(t *testing.T) {
+		resp := makeJSONRPCRequest(t, url, token, "torrent.move", map[string]any{
+			keyInfoHash:        strings.Repeat("0", 40),
+			"target_base_path": t.TempDir(),
+		})
+		requireRPCError(t, resp, 2)
+	})
+
+	t.Run("torrent.set_location_not_found", func(t *testing.T) {
+		resp := makeJSONRPCRequest(t, url, token, "torrent.set_location", map[string]any{
+			keyInfoHash: strings.Repeat("0", 40),
+			"base_path": t.TempDir(),
+		})
+		requireRPCError(t, resp, 2)
+	})
+
+	t.Run("torrent.rename_file_escapes_base", func(t *testing.T) {
+		resp := makeJSONRPCRequest(t, url, token, "torrent.rename_file", map[string]any{
+			keyInfoHash: infoHash,
+			"index":     0,
+			"path":      filepath.Join("..", "escaped"),
+		})
+		requireRPCError(t, resp, 2)
+	})
+
+	t.Run("torrent.rename_folder_not_found", func(t *testing.T) {
+		resp := makeJSONRPCRequest(t, url, token, "torrent.rename_folder", map[string]any{
+			keyInfoHash:  infoHash,
+			"folder":     "missing",
+			"new_folder": "renamed",
+		})
+		requireRPCError(t, resp, 2)
+	})
+
+	t.Run("torrent.move_cancel_not_moving", func(t *testing.T) {
+		resp := makeJSONRPCRequest(t, url, token, "torrent.move_cancel", map[string]any{
+			keyInfoHash: infoHash,
+		})
+		requireRPCError(t, resp, 2)
+	})
+
 	// ---------------------------------------------------------------------------
 	// torrent.remove
 	// ---------------------------------------------------------------------------
@@ -0,0 +1,38 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package web
+
+import (
+	"context"
+
+	"github.com/swaggest/usecase"
+
+	"neptune/internal/client"
+	"neptune/internal/web/jsonrpc"
+)
+
+// client.get_path_owners
+
+type getPathOwnersRequest struct {
+	Path string `description:"a file, or a directory to list the owners of every file under it" json:"path" required:"true"`
+}
+
+type getPathOwnersResponse struct {
+	Owners []client.PathOwner `description:"torrent files at path, sorted by path" json:"owners" required:"true"`
+}
+
+func getPathOwners(h *jsonrpc.Handler, c *client.Client) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *getPathOwnersRequest, res *getPathOwnersResponse) error {
+			owners, err := c.PathOwners(req.Path)
+			if err != nil {
+				return err
+			}
+			res.Owners = owners
+			return nil
+		},
+	)
+	u.SetName("client.get_path_owners")
+	h.Add(u)
+}
@@ -17,19 +17,34 @@ import (
 	"github.com/trim21/errgo"
 
 	"neptune/internal/client"
+	"neptune/internal/download"
 	"neptune/internal/meta"
 	"neptune/internal/metainfo"
 	"neptune/internal/web/jsonrpc"
 )
 
 type AddTorrentRequest struct {
-	TorrentFile   []byte            `description:"base64 encoded torrent file content"                                              json:"torrent_file"    required:"true" validate:"required"`
-	DownloadDir   string            `description:"base download dir"                                                                json:"download_dir"`
+	TorrentFile []byte `description:"base64 encoded torrent file content" json:"torrent_file" required:"true" validate:"required"`
+	AddTorrentOptions
+}
+
+// AddTorrentOptions are the options of torrent.add and torrent.add_url.
+type AddTorrentOptions struct {
+	DownloadDir   string            `description:"base download dir"                                                                                     json:"download_dir"`
+	IncompleteDir string            `description:"download here and move to download_dir on completion, default from config"                             json:"incomplete_dir"`
+	Allocation    string            `description:"'sparse', 'full' or 'zero-fill', default 'full' if fallocate is enabled, else 'sparse'"                json:"allocation"`
 	Tags          []string          `json:"tags"`
 	Custom        map[string]string `json:"custom"`
-	SelectedFiles []int             `description:"indices of files to download, empty means all"                                    json:"selected_files"` // if nil, all files are selected.
-	IsBaseDir     bool              `description:"if true, will not append torrent name to download_dir"                            json:"is_base_dir"`
-	SkipHashCheck bool              `description:"if true, skip piece hash check and only verify file sizes match torrent metadata" json:"skip_hash_check"`
+	CrossSeed     *crossSeedRequest `description:"reuse existing data matching the torrent files instead of downloading it, all pieces are hash checked" json:"cross_seed"`
+	SelectedFiles []int             `description:"indices of files to download, empty means all"                                                         json:"selected_files"` // if nil, all files are selected.
+	IsBaseDir     bool              `description:"if true, will not append torrent name to download_dir and incomplete_dir"                              json:"is_base_dir"`
+	SkipHashCheck bool              `description:"if true, only verify file sizes and hash check each piece before it is first uploaded"                 json:"skip_hash_check"`
+}
+
+type crossSeedRequest struct {
+	Link     string   `description:"'remap' (default) points files at the existing paths, 'hardlink' or 'reflink' links them into the download dir. Remapped and hardlinked files are never written, a file the hash check does not fully verify is reflinked into the download dir when possible, or downloaded" json:"link"`
+	Paths    []string `description:"existing files, or directories searched for files"                                                                                                                                                                                                                            json:"paths"`
+	Torrents []string `description:"info hashes of loaded torrents whose files are candidates"                                                                                                                                                                                                                    json:"torrents"`
 }
 
 type AddTorrentResponse struct {
@@ -39,49 +54,12 @@ type AddTorrentResponse struct {
 func addTorrent(h *jsonrpc.Handler, c *client.Client) {
 	u := usecase.NewInteractor(
 		func(ctx context.Context, req *AddTorrentRequest, res *AddTorrentResponse) error {
-			m, err := metainfo.Load(req.TorrentFile)
-			if err != nil {
-				return CodeError(2, errgo.Wrap(err, "failed to parse torrent file"))
-			}
-
-			info, err := meta.FromTorrent(*m)
-			if err != nil {
-				return CodeError(2, errgo.Wrap(err, "failed to parse torrent info"))
-			}
-
-			if info.PieceLength > 256*units.MiB {
-				return CodeError(4,
-					fmt.Errorf("piece length %s too big, only allow <= 256 MiB",
-						humanize.IBytes(uint64(info.PieceLength))))
-			}
-
-			var downloadDir = req.DownloadDir
-
-			if downloadDir == "" {
-				downloadDir = c.Config().App.DownloadDir
-			} else {
-				if !req.IsBaseDir {
-					downloadDir = filepath.Join(req.DownloadDir, meta.SafePathComponent(info.Name))
-				}
-			}
-
-			if req.Tags == nil {
-				req.Tags = []string{}
-			}
-
-			if req.SelectedFiles == nil {
-				req.SelectedFiles = make([]int, len(info.Files))
-				for i := range info.Files {
-					req.SelectedFiles[i] = i
-				}
-			}
-
-			err = c.AddTorrent(req.TorrentFile, m, info, downloadDir, req.Tags, req.Custom, req.SelectedFiles, req.SkipHashCheck)
+			ih, err := addTorrentFile(c, req.TorrentFile, &req.AddTorrentOptions)
 			if err != nil {
-				return CodeError(5, errgo.Wrap(err, "failed to add torrent to download"))
+				return err
 			}
 
-			res.InfoHash = info.Hash.Hex()
+			res.InfoHash = ih.Hex()
 
 			return nil
 		},
@@ -90,14 +68,136 @@ func addTorrent(h *jsonrpc.Handler, c *client.Client) {
 	h.Add(u)
 }
 
+// addTorrentFile adds the torrent file raw with the options of torrent.add.
+func addTorrentFile(c *client.Client, raw []byte, opts *AddTorrentOptions) (metainfo.Hash, error) {
+	m, info, err := loadTorrent(raw)
+	if err != nil {
+		return metainfo.Hash{}, err
+	}
+
+	allocation := download.AllocSparse
+	if c.Config().App.Fallocate {
+		allocation = download.AllocFull
+	}
+	if opts.Allocation != "" {
+		allocation, err = download.AllocationFromString(opts.Allocation)
+		if err != nil {
+			return metainfo.Hash{}, CodeError(1, err)
+		}
+	}
+
+	var downloadDir = opts.DownloadDir
+	var incompleteDir = opts.IncompleteDir
+
+	if incompleteDir == "" {
+		incompleteDir = c.Config().App.IncompleteDir
+	}
+
+	if downloadDir == "" {
+		downloadDir = c.Config().App.DownloadDir
+	} else {
+		if !opts.IsBaseDir {
+			// the incomplete dir mirrors the layout of download_dir
+			name := meta.SafePathComponent(info.Name)
+			downloadDir = filepath.Join(opts.DownloadDir, name)
+			if incompleteDir != "" {
+				incompleteDir = filepath.Join(incompleteDir, name)
+			}
+		}
+	}
+
+	if opts.Tags == nil {
+		opts.Tags = []string{}
+	}
+
+	if opts.SelectedFiles == nil {
+		opts.SelectedFiles = make([]int, len(info.Files))
+		for i := range info.Files {
+			opts.SelectedFiles[i] = i
+		}
+	}
+
+	var crossSeed *client.CrossSeed
+	if opts.CrossSeed != nil {
+		crossSeed, err = parseCrossSeed(opts.CrossSeed)
+		if err != nil {
+			return metainfo.Hash{}, CodeError(1, err)
+		}
+	}
+
+	err = c.AddTorrent(raw, m, info, downloadDir, client.AddTorrentOptions{
+		IncompletePath: incompleteDir,
+		Tags:           opts.Tags,
+		Custom:         opts.Custom,
+		SelectedFiles:  opts.SelectedFiles,
+		SkipHashCheck:  opts.SkipHashCheck,
+		Allocation:     allocation,
+		CrossSeed:      crossSeed,
+	})
+	if err != nil {
+		return metainfo.Hash{}, CodeError(5, errgo.Wrap(err, "failed to add torrent to download"))
+	}
+
+	return info.Hash, nil
+}
+
+// loadTorrent parses a torrent file the way torrent.add accepts it.
+func loadTorrent(raw []byte) (*metainfo.MetaInfo, meta.Info, error) {
+	m, err := metainfo.Load(raw)
+	if err != nil {
+		return nil, meta.Info{}, CodeError(2, errgo.Wrap(err, "failed to parse torrent file"))
+	}
+
+	info, err := meta.FromTorrent(*m)
+	if err != nil {
+		return nil, meta.Info{}, CodeError(2, errgo.Wrap(err, "failed to parse torrent info"))
+	}
+
+	if info.PieceLength > 256*units.MiB {
+		return nil, meta.Info{}, CodeError(4,
+			fmt.Errorf("piece length %s too big, only allow <= 256 MiB",
+				humanize.IBytes(uint64(info.PieceLength))))
+	}
+	return m, info, nil
+}
+
+func parseCrossSeed(req *crossSeedRequest) (*client.CrossSeed, error) {
+	link, err := client.ParseLinkMode(req.Link)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Paths) == 0 && len(req.Torrents) == 0 {
+		return nil, errors.New("cross_seed needs paths or torrents")
+	}
+
+	cs := &client.CrossSeed{Paths: req.Paths, Link: link}
+	for _, s := range req.Torrents {
+		raw, err := hex.DecodeString(s)
+		if err != nil || len(raw) != sha1.Size {
+			return nil, errInvalidInfoHash
+		}
+		cs.Torrents = append(cs.Torrents, metainfo.Hash(raw))
+	}
+	return cs, nil
+}
+
 type GetTorrentRequest struct {
 	InfoHash string `description:"torrent file hash" json:"info_hash" required:"true"`
 }
 
 type GetTorrentResponse struct {
-	Name   string            `json:"name"   required:"true"`
-	Custom map[string]string `json:"custom"`
-	Tags   []string          `json:"tags"`
+	Custom       map[string]string `json:"custom"`
+	Name         string            `json:"name"                                                          required:"true"`
+	Comment      string            `json:"comment"`
+	CreatedBy    string            `json:"created_by"`
+	Encoding     string            `json:"encoding"`
+	Source       string            `description:"source field of the info dict, set by private trackers" json:"source"`
+	Tags         []string          `json:"tags"`
+	WebSeeds     []string          `description:"BEP 19 url-list"                                        json:"web_seeds"`
+	HTTPSeeds    []string          `description:"BEP 17 httpseeds"                                       json:"http_seeds"`
+	Nodes        []string          `description:"BEP 5 DHT nodes as host:port"                           json:"nodes"`
+	CreationDate int64             `description:"unix timestamp, 0 when the torrent has none"            json:"creation_date"`
+	Private      bool              `json:"private"`
 }
 
 func getTorrent(h *jsonrpc.Handler, c *client.Client) {
@@ -115,6 +215,15 @@ func getTorrent(h *jsonrpc.Handler, c *client.Client) {
 			}
 
 			res.Name = info.Name
+			res.Comment = info.Comment
+			res.CreatedBy = info.CreatedBy
+			res.CreationDate = info.CreationDate
+			res.Encoding = info.Encoding
+			res.Source = info.Source
+			res.WebSeeds = emptyIfNil(info.WebSeeds)
+			res.HTTPSeeds = emptyIfNil(info.HTTPSeeds)
+			res.Nodes = emptyIfNil(info.Nodes)
+			res.Private = info.Private
 
 			if info.Tags == nil {
 				res.Tags = []string{}
@@ -136,33 +245,12 @@ func getTorrent(h *jsonrpc.Handler, c *client.Client) {
 	h.Add(u)
 }
 
-type MoveTorrentRequest struct {
-	InfoHash       string `description:"torrent file hash" json:"info_hash" required:"true"`
-	TargetBasePath string `json:"target_base_path"         required:"true"`
-}
-
-type MoveTorrentResponse struct {
-}
-
-func MoveTorrent(h *jsonrpc.Handler, c *client.Client) {
-	u := usecase.NewInteractor(
-		func(ctx context.Context, req *MoveTorrentRequest, res *MoveTorrentResponse) error {
-			ih, err := hex.DecodeString(req.InfoHash)
-			if err != nil || len(ih) != 20 {
-				return CodeError(1, errgo.Wrap(err, "invalid info_hash"))
-			}
-
-			err = c.ScheduleMove(metainfo.Hash(ih), req.TargetBasePath)
-			if err != nil {
-				return CodeError(2, errgo.Wrap(err, "failed to schedule move"))
-			}
-
-			return nil
-		},
-	)
-
-	u.SetName("torrent.move")
-	h.Add(u)
+// emptyIfNil keeps lists in responses as [] rather than null.
+func emptyIfNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
 }
 
 type listTorrentRequest struct {
@@ -0,0 +1,44 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package web
+
+import (
+	"context"
+	"errors"
+
+	"github.com/swaggest/usecase"
+	"github.com/trim21/errgo"
+
+	"neptune/internal/client"
+	"neptune/internal/web/jsonrpc"
+)
+
+type addTorrentURLRequest struct {
+	URL string `description:"http or https url of the torrent file, fetched with the headers and cookies configured for its domain" json:"url" required:"true" validate:"required"`
+	AddTorrentOptions
+}
+
+func addTorrentURL(h *jsonrpc.Handler, c *client.Client) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *addTorrentURLRequest, res *AddTorrentResponse) error {
+			raw, err := c.FetchTorrentFile(ctx, req.URL)
+			if err != nil {
+				if errors.Is(err, client.ErrMagnetRedirect) {
+					return CodeError(6, err)
+				}
+				return CodeError(3, errgo.Wrap(err, "failed to fetch torrent file"))
+			}
+
+			ih, err := addTorrentFile(c, raw, &req.AddTorrentOptions)
+			if err != nil {
+				return err
+			}
+
+			res.InfoHash = ih.Hex()
+			return nil
+		},
+	)
+	u.SetName("torrent.add_url")
+	h.Add(u)
+}
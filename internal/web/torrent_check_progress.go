@@ -0,0 +1,77 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package web
+
+import (
+	"context"
+
+	"github.com/swaggest/usecase"
+
+	"neptune/internal/client"
+	"neptune/internal/hashcheck"
+	"neptune/internal/web/jsonrpc"
+)
+
+type checkProgressRequest struct{}
+
+type HashCheckProgress struct {
+	InfoHash    string  `description:"torrent file hash"                                     json:"info_hash"    required:"true"`
+	Status      string  `description:"queued or hashing"                                     json:"status"       required:"true"`
+	Device      string  `description:"device id (major:minor) the torrent data is stored on" json:"device"       required:"true"`
+	DeviceClass string  `description:"hdd or ssd"                                            json:"device_class" required:"true"`
+	Progress    float64 `description:"fraction of bytes hashed, from 0 to 1"                 json:"progress"     required:"true"`
+	BytesDone   int64   `description:"bytes hashed so far"                                   json:"bytes_done"   required:"true"`
+	BytesTotal  int64   `description:"bytes to hash, 0 until the check starts"               json:"bytes_total"  required:"true"`
+	Rate        int64   `description:"average hashing speed in bytes per second"             json:"rate"         required:"true"`
+	ETA         int64   `description:"estimated seconds remaining, -1 if unknown"            json:"eta"          required:"true"`
+	QueuedAt    int64   `description:"unix timestamp the check was queued"                   json:"queued_at"    required:"true"`
+	StartedAt   int64   `description:"unix timestamp hashing started, 0 while queued"        json:"started_at"   required:"true"`
+	Position    int     `description:"1-based position in the device queue, 0 while hashing" json:"position"     required:"true"`
+}
+
+type checkProgressResponse struct {
+	Checks []HashCheckProgress `json:"checks"                                                   required:"true"`
+	Paused bool                `description:"whether checking is paused by client.pause_checks" json:"paused"   required:"true"`
+}
+
+func checkProgress(h *jsonrpc.Handler, c *client.Client) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *checkProgressRequest, res *checkProgressResponse) error {
+			checks := c.HashChecks()
+			res.Checks = make([]HashCheckProgress, len(checks))
+			for i, p := range checks {
+				res.Checks[i] = hashCheckProgress(p)
+			}
+			res.Paused = c.ChecksPaused()
+			return nil
+		},
+	)
+	u.SetName("torrent.check_progress")
+	h.Add(u)
+}
+
+func hashCheckProgress(p hashcheck.Progress) HashCheckProgress {
+	r := HashCheckProgress{
+		InfoHash:    p.InfoHash.Hex(),
+		Status:      p.Status.String(),
+		Device:      p.Device,
+		DeviceClass: p.DeviceClass,
+		BytesDone:   p.BytesDone,
+		BytesTotal:  p.BytesTotal,
+		Rate:        p.Rate,
+		ETA:         -1,
+		QueuedAt:    p.Queued.Unix(),
+		Position:    p.Position,
+	}
+	if p.BytesTotal > 0 {
+		r.Progress = float64(p.BytesDone) / float64(p.BytesTotal)
+	}
+	if p.ETA >= 0 {
+		r.ETA = int64(p.ETA.Seconds())
+	}
+	if !p.Started.IsZero() {
+		r.StartedAt = p.Started.Unix()
+	}
+	return r
+}
@@ -0,0 +1,106 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package web
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+
+	"github.com/swaggest/usecase"
+	"github.com/trim21/errgo"
+
+	"neptune/internal/client"
+	"neptune/internal/metainfo"
+	"neptune/internal/mktorrent"
+	"neptune/internal/web/jsonrpc"
+)
+
+type createTorrentRequest struct {
+	Path        string     `description:"file or directory to create the torrent of"                                                    json:"path"         required:"true" validate:"required"`
+	Source      string     `description:"source field of the info dict, makes the info hash unique to a tracker"                        json:"source"`
+	Comment     string     `json:"comment"`
+	Trackers    [][]string `description:"announce URLs grouped in tiers"                                                                json:"trackers"`
+	WebSeeds    []string   `description:"BEP 19 web seed URLs"                                                                          json:"web_seeds"`
+	Exclude     []string   `description:"glob patterns of files and directories to leave out, matched against names and relative paths" json:"exclude"`
+	Tags        []string   `description:"tags of the torrent when it is added"                                                          json:"tags"`
+	PieceLength int64      `description:"power of two from 16 KiB to 256 MiB, 0 picks one by the total size"                            json:"piece_length"`
+	Private     bool       `json:"private"`
+	Add         bool       `description:"add the torrent seeding the data it was created from, without checking it again"               json:"add"`
+}
+
+type createTorrentResponse struct {
+	JobID string `description:"id of the job, listed as info hash among hash checks" json:"job_id" required:"true"`
+}
+
+func createTorrent(h *jsonrpc.Handler, c *client.Client) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *createTorrentRequest, res *createTorrentResponse) error {
+			id, err := c.CreateTorrent(client.CreateTorrent{
+				Options: mktorrent.Options{
+					Path:        req.Path,
+					Source:      req.Source,
+					Comment:     req.Comment,
+					Trackers:    req.Trackers,
+					WebSeeds:    req.WebSeeds,
+					Exclude:     req.Exclude,
+					PieceLength: req.PieceLength,
+					Private:     req.Private,
+				},
+				Tags: req.Tags,
+				Add:  req.Add,
+			})
+			if err != nil {
+				return CodeError(1, errgo.Wrap(err, "failed to create torrent"))
+			}
+			res.JobID = id.Hex()
+			return nil
+		},
+	)
+	u.SetName("torrent.create")
+	h.Add(u)
+}
+
+type createTorrentStatusRequest struct {
+	JobID string `json:"job_id" required:"true"`
+}
+
+type createTorrentStatusResponse struct {
+	Error       string `description:"why the job failed"                                  json:"error"`
+	State       string `description:"'queued', 'hashing', 'done' or 'failed'"             json:"state"        required:"true"`
+	InfoHash    string `description:"info hash of the created torrent, when done"         json:"info_hash"`
+	TorrentFile []byte `description:"base64 encoded torrent file, when done"              json:"torrent_file"`
+	BytesDone   int64  `json:"bytes_done"                                                 required:"true"`
+	BytesTotal  int64  `json:"bytes_total"                                                required:"true"`
+	Added       bool   `description:"the torrent was added to the session and is seeding" json:"added"        required:"true"`
+}
+
+func createTorrentStatus(h *jsonrpc.Handler, c *client.Client) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *createTorrentStatusRequest, res *createTorrentStatusResponse) error {
+			raw, err := hex.DecodeString(req.JobID)
+			if err != nil || len(raw) != sha1.Size {
+				return CodeError(1, errors.New("invalid job id"))
+			}
+
+			s, err := c.CreateStatus(metainfo.Hash(raw))
+			if err != nil {
+				return CodeError(2, err)
+			}
+			res.State = s.State
+			res.Error = s.Error
+			res.BytesDone = s.BytesDone
+			res.BytesTotal = s.BytesTotal
+			res.Added = s.Added
+			if s.State == "done" {
+				res.InfoHash = s.InfoHash.Hex()
+				res.TorrentFile = s.Torrent
+			}
+			return nil
+		},
+	)
+	u.SetName("torrent.create_status")
+	h.Add(u)
+}
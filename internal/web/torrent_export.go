@@ -0,0 +1,103 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package web
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"github.com/swaggest/usecase"
+	"github.com/trim21/errgo"
+
+	"neptune/internal/client"
+	"neptune/internal/download"
+	"neptune/internal/metainfo"
+	"neptune/internal/web/jsonrpc"
+	"neptune/internal/web/res"
+)
+
+type exportTorrentRequest struct {
+	InfoHash string `json:"info_hash" required:"true"`
+	Trackers bool   `description:"write the current trackers into announce and announce-list instead of the ones the torrent was added with" json:"trackers"`
+}
+
+type exportTorrentResponse struct {
+	Magnet      string `description:"magnet link with the name and current trackers" json:"magnet"       required:"true"`
+	TorrentFile []byte `description:"base64 encoded torrent file"                     json:"torrent_file" required:"true"`
+}
+
+func exportTorrent(h *jsonrpc.Handler, c *client.Client) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *exportTorrentRequest, res *exportTorrentResponse) error {
+			r, err := hex.DecodeString(req.InfoHash)
+			if err != nil || len(r) != sha1.Size {
+				return CodeError(1, errInvalidInfoHash)
+			}
+
+			exported, err := c.ExportTorrent(metainfo.Hash(r), req.Trackers)
+			if err != nil {
+				return CodeError(2, errgo.Wrap(err, "failed to export torrent"))
+			}
+			res.Magnet = exported.Magnet
+			res.TorrentFile = exported.Torrent
+			return nil
+		},
+	)
+	u.SetName("torrent.export")
+	h.Add(u)
+}
+
+// exportTorrents serves the torrent files of the info_hash query parameters,
+// or of all torrents without any, as a tar archive. trackers=true writes the
+// current trackers into them.
+func exportTorrents(c *client.Client) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		var hashes []metainfo.Hash
+		for _, s := range query["info_hash"] {
+			raw, err := hex.DecodeString(s)
+			if err != nil || len(raw) != sha1.Size {
+				res.Text(w, http.StatusBadRequest, errInvalidInfoHash.Error())
+				return
+			}
+			hashes = append(hashes, metainfo.Hash(raw))
+		}
+		trackers, _ := strconv.ParseBool(query.Get("trackers"))
+
+		// unknown torrents fail before the first write sends the headers
+		rw := &lazyHeaderWriter{w: w}
+		if err := c.ExportTorrents(rw, hashes, trackers); err != nil {
+			if !rw.written {
+				status := http.StatusInternalServerError
+				if errors.Is(err, download.ErrTorrentNotFound) {
+					status = http.StatusNotFound
+				}
+				res.Text(w, status, err.Error())
+				return
+			}
+			log.Err(err).Msg("failed to export torrents")
+		}
+	}
+}
+
+// lazyHeaderWriter sends the tar response headers on the first write.
+type lazyHeaderWriter struct {
+	w       http.ResponseWriter
+	written bool
+}
+
+func (l *lazyHeaderWriter) Write(p []byte) (int, error) {
+	if !l.written {
+		l.written = true
+		l.w.Header().Set("Content-Type", "application/x-tar")
+		l.w.Header().Set("Content-Disposition", `attachment; filename="torrents.tar"`)
+		l.w.WriteHeader(http.StatusOK)
+	}
+	return l.w.Write(p)
+}
@@ -0,0 +1,74 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package web
+
+import (
+	"context"
+
+	"github.com/swaggest/usecase"
+	"github.com/trim21/errgo"
+
+	"neptune/internal/client"
+	"neptune/internal/web/jsonrpc"
+)
+
+type inspectTorrentRequest struct {
+	TorrentFile []byte `description:"base64 encoded torrent file content" json:"torrent_file" required:"true" validate:"required"`
+}
+
+type inspectTorrentResponse struct {
+	Tree         *client.FileTreeNode   `description:"files nested by directory, directories have index -1"   json:"tree"          required:"true"`
+	InfoHash     string                 `json:"info_hash"                                                     required:"true"`
+	Name         string                 `json:"name"                                                          required:"true"`
+	Comment      string                 `json:"comment"`
+	CreatedBy    string                 `json:"created_by"`
+	Source       string                 `description:"source field of the info dict, set by private trackers" json:"source"`
+	Files        []client.InspectedFile `json:"files"                                                         required:"true"`
+	Trackers     [][]string             `description:"announce URLs grouped in tiers"                         json:"trackers"      required:"true"`
+	CreationDate int64                  `description:"unix timestamp, 0 when the torrent has none"            json:"creation_date"`
+	TotalLength  int64                  `json:"total_length"                                                  required:"true"`
+	PieceLength  int64                  `json:"piece_length"                                                  required:"true"`
+	NumPieces    uint32                 `json:"num_pieces"                                                    required:"true"`
+	Private      bool                   `json:"private"                                                       required:"true"`
+	Loaded       bool                   `description:"a torrent with the same info hash is already loaded"    json:"loaded"        required:"true"`
+}
+
+func inspectTorrent(h *jsonrpc.Handler, c *client.Client) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *inspectTorrentRequest, res *inspectTorrentResponse) error {
+			m, info, err := loadTorrent(req.TorrentFile)
+			if err != nil {
+				return err
+			}
+
+			r, err := c.InspectTorrent(m, info)
+			if err != nil {
+				return CodeError(5, errgo.Wrap(err, "failed to inspect torrent"))
+			}
+
+			res.InfoHash = r.InfoHash.Hex()
+			res.Name = r.Name
+			res.Comment = r.Comment
+			res.CreatedBy = r.CreatedBy
+			res.Source = r.Source
+			res.Tree = r.Tree
+			res.Files = r.Files
+			res.Trackers = r.Trackers
+			if res.Trackers == nil {
+				res.Trackers = [][]string{}
+			}
+			if !r.CreationDate.IsZero() {
+				res.CreationDate = r.CreationDate.Unix()
+			}
+			res.TotalLength = r.TotalLength
+			res.PieceLength = r.PieceLength
+			res.NumPieces = r.NumPieces
+			res.Private = r.Private
+			res.Loaded = r.Loaded
+			return nil
+		},
+	)
+	u.SetName("torrent.inspect")
+	h.Add(u)
+}
@@ -0,0 +1,57 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package web
+
+import (
+	"context"
+
+	"github.com/swaggest/usecase"
+	"github.com/trim21/errgo"
+
+	"neptune/internal/client"
+	"neptune/internal/meta"
+	"neptune/internal/metainfo"
+	"neptune/internal/web/jsonrpc"
+)
+
+type matchTorrentRequest struct {
+	TorrentFile []byte `description:"base64 encoded torrent file content"                                                                  json:"torrent_file" required:"true" validate:"required"`
+	Verify      bool   `description:"hash check sample pieces against files of the same size instead of matching by name, reads from disk" json:"verify"`
+}
+
+type matchTorrentResponse struct {
+	Files            []client.FileMatch `description:"completed files of loaded torrents holding the data of a file, sorted by index"   json:"files"             required:"true"`
+	Torrents         []string           `description:"info hashes of the loaded torrents holding matched files"                         json:"torrents"          required:"true"`
+	MatchedBytes     int64              `description:"total size of the matched files"                                                  json:"matched_bytes"     required:"true"`
+	Percent          float64            `description:"share of pieces a cross-seed add can verify, 0 to 100. only verified files count" json:"percent"           required:"true"`
+	VerifiablePieces uint32             `description:"pieces whose data is all in verified files"                                       json:"verifiable_pieces" required:"true"`
+	MatchedPieces    uint32             `description:"pieces whose data is all in matched files, also those matched by name only"       json:"matched_pieces"    required:"true"`
+}
+
+func matchTorrent(h *jsonrpc.Handler, c *client.Client) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *matchTorrentRequest, res *matchTorrentResponse) error {
+			m, err := metainfo.Load(req.TorrentFile)
+			if err != nil {
+				return CodeError(2, errgo.Wrap(err, "failed to parse torrent file"))
+			}
+
+			info, err := meta.FromTorrent(*m)
+			if err != nil {
+				return CodeError(2, errgo.Wrap(err, "failed to parse torrent info"))
+			}
+
+			match := c.MatchTorrent(&info, req.Verify)
+			res.Files = match.Files
+			res.Torrents = match.Torrents
+			res.MatchedBytes = match.MatchedBytes
+			res.Percent = match.Percent
+			res.VerifiablePieces = match.VerifiablePieces
+			res.MatchedPieces = match.MatchedPieces
+			return nil
+		},
+	)
+	u.SetName("torrent.match")
+	h.Add(u)
+}
@@ -0,0 +1,102 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package web
+
+import (
+	"context"
+	"errors"
+
+	"github.com/swaggest/usecase"
+	"github.com/trim21/errgo"
+
+	"neptune/internal/client"
+	"neptune/internal/web/jsonrpc"
+)
+
+type moveTorrentRequest struct {
+	InfoHash       string `description:"torrent file hash"                                 json:"info_hash"        required:"true"`
+	TargetBasePath string `description:"new base path, files keep their relative location" json:"target_base_path" required:"true"`
+}
+
+type moveTorrentResponse struct{}
+
+// moveTorrent starts moving the data of a torrent and returns immediately.
+// Progress is reported in the move field of torrent.get and the main data.
+func moveTorrent(h *jsonrpc.Handler, c *client.Client) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *moveTorrentRequest, res *moveTorrentResponse) error {
+			ih, err := checkInfoHash(req.InfoHash)
+			if err != nil {
+				return err
+			}
+			if req.TargetBasePath == "" {
+				return CodeError(1, errors.New("target_base_path is empty"))
+			}
+
+			if err := c.ScheduleMove(ih, req.TargetBasePath); err != nil {
+				return CodeError(2, errgo.Wrap(err, "failed to schedule move"))
+			}
+			return nil
+		},
+	)
+	u.SetName("torrent.move")
+	h.Add(u)
+}
+
+type cancelMoveRequest struct {
+	InfoHash string `description:"torrent file hash" json:"info_hash" required:"true"`
+}
+
+type cancelMoveResponse struct{}
+
+// cancelMove stops a running move. Files already copied are removed and the
+// torrent stays in its old location.
+func cancelMove(h *jsonrpc.Handler, c *client.Client) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *cancelMoveRequest, res *cancelMoveResponse) error {
+			ih, err := checkInfoHash(req.InfoHash)
+			if err != nil {
+				return err
+			}
+
+			if err := c.CancelMove(ih); err != nil {
+				return CodeError(2, errgo.Wrap(err, "failed to cancel move"))
+			}
+			return nil
+		},
+	)
+	u.SetName("torrent.move_cancel")
+	h.Add(u)
+}
+
+type setLocationRequest struct {
+	InfoHash string `description:"torrent file hash"                                       json:"info_hash" required:"true"`
+	BasePath string `description:"directory the data was moved to"                         json:"base_path" required:"true"`
+	Recheck  bool   `description:"hash check all data instead of only checking file sizes" json:"recheck"`
+}
+
+type setLocationResponse struct{}
+
+// setLocation points a torrent at data the user moved, without touching any
+// file.
+func setLocation(h *jsonrpc.Handler, c *client.Client) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *setLocationRequest, res *setLocationResponse) error {
+			ih, err := checkInfoHash(req.InfoHash)
+			if err != nil {
+				return err
+			}
+			if req.BasePath == "" {
+				return CodeError(1, errors.New("base_path is empty"))
+			}
+
+			if err := c.SetLocation(ih, req.BasePath, req.Recheck); err != nil {
+				return CodeError(2, errgo.Wrap(err, "failed to set location"))
+			}
+			return nil
+		},
+	)
+	u.SetName("torrent.set_location")
+	h.Add(u)
+}
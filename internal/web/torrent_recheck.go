@@ -11,12 +11,20 @@ import (
 	"github.com/swaggest/usecase"
 
 	"neptune/internal/client"
+	"neptune/internal/download"
 	"neptune/internal/metainfo"
 	"neptune/internal/web/jsonrpc"
 )
 
 type recheckTorrentRequest struct {
-	InfoHash string `description:"torrent file hash" json:"info_hash" required:"true"`
+	InfoHash string       `description:"torrent file hash"                                                                       json:"info_hash" required:"true"`
+	Files    []int        `description:"check only the pieces of these file indices, the whole torrent when pieces is empty too" json:"files"`
+	Pieces   []pieceRange `description:"check only the pieces in these ranges"                                                   json:"pieces"`
+}
+
+type pieceRange struct {
+	First uint32 `description:"first piece index"           json:"first" required:"true"`
+	Last  uint32 `description:"last piece index, inclusive" json:"last"  required:"true"`
 }
 
 type recheckTorrentResponse struct{}
@@ -33,7 +41,11 @@ func recheckTorrent(h *jsonrpc.Handler, c *client.Client) {
 				return errInvalidInfoHash
 			}
 
-			return c.RecheckTorrent(metainfo.Hash(raw))
+			ranges := make([]download.PieceRange, len(req.Pieces))
+			for i, r := range req.Pieces {
+				ranges[i] = download.PieceRange{First: r.First, Last: r.Last}
+			}
+			return c.RecheckTorrent(metainfo.Hash(raw), req.Files, ranges)
 		},
 	)
 	u.SetName("torrent.recheck")
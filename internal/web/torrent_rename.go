@@ -0,0 +1,66 @@
+// Copyright 2026 trim21 <trim21.me@gmail.com>
+// SPDX-License-Identifier: GPL-3.0-only
+
+package web
+
+import (
+	"context"
+
+	"github.com/swaggest/usecase"
+	"github.com/trim21/errgo"
+
+	"neptune/internal/client"
+	"neptune/internal/web/jsonrpc"
+)
+
+type renameFileRequest struct {
+	InfoHash string `description:"torrent file hash"                               json:"info_hash" required:"true"`
+	Path     string `description:"new path, relative to the base path or absolute" json:"path"      required:"true"`
+	Index    int    `description:"index of the file in torrent.files"              json:"index"     required:"true"`
+}
+
+type renameFileResponse struct{}
+
+func renameFile(h *jsonrpc.Handler, c *client.Client) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *renameFileRequest, res *renameFileResponse) error {
+			ih, err := checkInfoHash(req.InfoHash)
+			if err != nil {
+				return err
+			}
+
+			if err := c.RenameFile(ih, req.Index, req.Path); err != nil {
+				return CodeError(2, errgo.Wrap(err, "failed to rename file"))
+			}
+			return nil
+		},
+	)
+	u.SetName("torrent.rename_file")
+	h.Add(u)
+}
+
+type renameFolderRequest struct {
+	InfoHash  string `description:"torrent file hash"                           json:"info_hash"  required:"true"`
+	Folder    string `description:"folder to rename, relative to the base path" json:"folder"     required:"true"`
+	NewFolder string `description:"new folder, relative to the base path"       json:"new_folder" required:"true"`
+}
+
+type renameFolderResponse struct{}
+
+func renameFolder(h *jsonrpc.Handler, c *client.Client) {
+	u := usecase.NewInteractor(
+		func(ctx context.Context, req *renameFolderRequest, res *renameFolderResponse) error {
+			ih, err := checkInfoHash(req.InfoHash)
+			if err != nil {
+				return err
+			}
+
+			if err := c.RenameFolder(ih, req.Folder, req.NewFolder); err != nil {
+				return CodeError(2, errgo.Wrap(err, "failed to rename folder"))
+			}
+			return nil
+		},
+	)
+	u.SetName("torrent.rename_folder")
+	h.Add(u)
+}
@@ -116,6 +116,11 @@ func New(c *client.Client, token string, enableDebug bool) http.Handler {
 	reannounceTorrent(h, c)
 
 	addTorrent(h, c)
+	addTorrentURL(h, c)
+	matchTorrent(h, c)
+	inspectTorrent(h, c)
+	createTorrent(h, c)
+	createTorrentStatus(h, c)
 	removeTorrent(h, c)
 	getTorrent(h, c)
 	addTags(h, c)
@@ -126,6 +131,12 @@ func New(c *client.Client, token string, enableDebug bool) http.Handler {
 	startTorrent(h, c)
 	stopTorrent(h, c)
 	recheckTorrent(h, c)
+	checkProgress(h, c)
+	cancelCheck(h, c)
+	moveCheckToTop(h, c)
+	moveCheckToBottom(h, c)
+	pauseChecks(h, c)
+	resumeChecks(h, c)
 	setFilePriority(h, c)
 	setDownloadLimit(h, c)
 	setUploadLimit(h, c)
@@ -145,7 +156,14 @@ func New(c *client.Client, token string, enableDebug bool) http.Handler {
 	setTorrentConnectionLimit(h, c)
 	getTorrentConnectionLimit(h, c)
 	torrentGetPiecePickStrategy(h, c)
-	// MoveTorrent(h, c)
+	moveTorrent(h, c)
+	cancelMove(h, c)
+	setLocation(h, c)
+	renameFile(h, c)
+	renameFolder(h, c)
+	getDiskSpace(h, c)
+	getPathOwners(h, c)
+	exportTorrent(h, c)
 
 	var auth = func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -166,6 +184,7 @@ func New(c *client.Client, token string, enableDebug bool) http.Handler {
 	}
 
 	r.With(middleware.NoCache, auth).Handle("POST /json_rpc", h)
+	r.With(middleware.NoCache, auth).Get("/export/torrents.tar", exportTorrents(c))
 
 	r.Get("/docs/openapi.json", h.OpenAPI.ServeHTTP)
 
@@ -5,19 +5,39 @@ from .exceptions import NeptuneConnectionError, NeptuneError, NeptuneRPCError
 from .models import (
     AddTorrentRequest,
     AddTorrentResponse,
+    AddTorrentURLRequest,
     AddTrackerRequest,
+    CreateTorrentRequest,
+    CreateTorrentResponse,
+    CreateTorrentStatusResponse,
+    CrossSeed,
     DelCustomRequest,
+    DiskSpace,
+    ExportTorrentRequest,
+    ExportTorrentResponse,
+    FileMatch,
+    FileTreeNode,
+    GetPathOwnersRequest,
     InfoHashRequest,
+    InspectedFile,
+    InspectTorrentResponse,
     ListTorrentRequest,
     MainDataTorrent,
+    MatchTorrentRequest,
     MoveTorrentRequest,
+    PathOwner,
     Peer,
+    PieceRange,
+    RecheckTorrentRequest,
     RemoveTorrentRequest,
     RemoveTrackerRequest,
+    RenameFileRequest,
+    RenameFolderRequest,
     ReplaceTrackersRequest,
     SetCustomRequest,
     SetFilePriorityRequest,
     SetGlobalSpeedLimitRequest,
+    SetLocationRequest,
     SetSpeedLimitRequest,
     TagsRequest,
     TorrentFile,
@@ -41,23 +61,43 @@ __all__ = [
     "NeptuneConnectionError",
     # request models
     "AddTorrentRequest",
+    "AddTorrentURLRequest",
     "AddTrackerRequest",
+    "CreateTorrentRequest",
+    "CrossSeed",
     "DelCustomRequest",
+    "ExportTorrentRequest",
+    "GetPathOwnersRequest",
     "InfoHashRequest",
     "ListTorrentRequest",
+    "MatchTorrentRequest",
     "MoveTorrentRequest",
+    "PieceRange",
+    "RecheckTorrentRequest",
     "RemoveTorrentRequest",
     "RemoveTrackerRequest",
+    "RenameFileRequest",
+    "RenameFolderRequest",
     "ReplaceTrackersRequest",
     "SetCustomRequest",
     "SetFilePriorityRequest",
     "SetGlobalSpeedLimitRequest",
+    "SetLocationRequest",
     "SetSpeedLimitRequest",
     "TagsRequest",
     "UpdateCustomRequest",
     # response / domain models
     "AddTorrentResponse",
+    "CreateTorrentResponse",
+    "CreateTorrentStatusResponse",
+    "DiskSpace",
+    "ExportTorrentResponse",
+    "FileMatch",
+    "FileTreeNode",
+    "InspectedFile",
+    "InspectTorrentResponse",
     "MainDataTorrent",
+    "PathOwner",
     "Peer",
     "TorrentFile",
     "TorrentFilesResponse",
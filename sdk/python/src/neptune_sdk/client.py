@@ -16,22 +16,41 @@ from .exceptions import NeptuneConnectionError, NeptuneRPCError
 from .models import (
     AddTorrentRequest,
     AddTorrentResponse,
+    AddTorrentURLRequest,
     AddTrackerRequest,
+    CreateTorrentRequest,
+    CreateTorrentResponse,
+    CreateTorrentStatusRequest,
+    CreateTorrentStatusResponse,
     DelCustomRequest,
+    ExportTorrentRequest,
+    ExportTorrentResponse,
+    GetDiskSpaceResponse,
     GetDownloadSlotsResponse,
+    GetPathOwnersRequest,
+    GetPathOwnersResponse,
     GetRecheckOnCompleteResponse,
     GetSlowDownloadSpeedThresholdResponse,
     GetTorrentConnectionLimitResponse,
     InfoHashRequest,
+    InspectTorrentRequest,
+    InspectTorrentResponse,
     ListTorrentRequest,
+    MatchTorrentRequest,
+    MatchTorrentResponse,
     MoveTorrentRequest,
+    PieceRange,
+    RecheckTorrentRequest,
     RemoveTorrentRequest,
     RemoveTrackerRequest,
+    RenameFileRequest,
+    RenameFolderRequest,
     ReplaceTrackersRequest,
     SetCustomRequest,
     SetDownloadSlotsRequest,
     SetFilePriorityRequest,
     SetGlobalSpeedLimitRequest,
+    SetLocationRequest,
     SetQueueWeightRequest,
     SetRecheckOnCompleteRequest,
     SetSlowDownloadSpeedThresholdRequest,
@@ -228,6 +247,72 @@ class NeptuneClient:
         """Add a torrent from raw .torrent bytes."""
         return _validate(AddTorrentResponse, self._call("torrent.add", req))
 
+    def torrent_add_url(self, req: AddTorrentURLRequest) -> AddTorrentResponse:
+        """Add a torrent from a .torrent URL fetched by the server."""
+        return _validate(AddTorrentResponse, self._call("torrent.add_url", req))
+
+    def torrent_match(
+        self, torrent_file: bytes, *, verify: bool = False
+    ) -> MatchTorrentResponse:
+        """Find completed files of loaded torrents that hold a torrent's data."""
+        return _validate(
+            MatchTorrentResponse,
+            self._call(
+                "torrent.match",
+                MatchTorrentRequest(torrent_file=torrent_file, verify=verify),
+            ),
+        )
+
+    def torrent_inspect(self, torrent_file: bytes) -> InspectTorrentResponse:
+        """Parse a torrent file the way torrent.add does, without adding it."""
+        return _validate(
+            InspectTorrentResponse,
+            self._call(
+                "torrent.inspect", InspectTorrentRequest(torrent_file=torrent_file)
+            ),
+        )
+
+    def torrent_create(self, req: CreateTorrentRequest) -> CreateTorrentResponse:
+        """Start creating a torrent from local data, returning the job id."""
+        return _validate(CreateTorrentResponse, self._call("torrent.create", req))
+
+    def torrent_create_status(self, job_id: str) -> CreateTorrentStatusResponse:
+        """Progress of a torrent creation job, and the torrent once it is done."""
+        return _validate(
+            CreateTorrentStatusResponse,
+            self._call(
+                "torrent.create_status", CreateTorrentStatusRequest(job_id=job_id)
+            ),
+        )
+
+    def torrent_export(
+        self, info_hash: str, *, trackers: bool = False
+    ) -> ExportTorrentResponse:
+        """Torrent file and magnet link of a loaded torrent."""
+        return _validate(
+            ExportTorrentResponse,
+            self._call(
+                "torrent.export",
+                ExportTorrentRequest(info_hash=info_hash, trackers=trackers),
+            ),
+        )
+
+    def export_torrents(
+        self, info_hashes: list[str] | None = None, *, trackers: bool = False
+    ) -> bytes:
+        """Tar archive of the torrent files of info_hashes, or of all torrents."""
+        params: list[tuple[str, str]] = [("info_hash", h) for h in info_hashes or []]
+        if trackers:
+            params.append(("trackers", "true"))
+        try:
+            resp = self._client.get(
+                self._url.rsplit("/", 1)[0] + "/export/torrents.tar", params=params
+            )
+            resp.raise_for_status()
+        except httpx.HTTPError as exc:
+            raise NeptuneConnectionError(str(exc)) from exc
+        return resp.content
+
     def torrent_move(self, info_hash: str, target_base_path: str) -> None:
         """Move torrent data to a new directory."""
         self._call(
@@ -235,6 +320,39 @@ class NeptuneClient:
             MoveTorrentRequest(info_hash=info_hash, target_base_path=target_base_path),
         )
 
+    def torrent_move_cancel(self, info_hash: str) -> None:
+        """Cancel a running move, keeping data in the old directory."""
+        self._call("torrent.move_cancel", InfoHashRequest(info_hash=info_hash))
+
+    def torrent_set_location(
+        self, info_hash: str, base_path: str, *, recheck: bool = False
+    ) -> None:
+        """Point a torrent at data already moved to base_path, without moving files."""
+        self._call(
+            "torrent.set_location",
+            SetLocationRequest(
+                info_hash=info_hash, base_path=base_path, recheck=recheck
+            ),
+        )
+
+    def torrent_rename_file(self, info_hash: str, index: int, path: str) -> None:
+        """Rename a file; an absolute path points it at an existing file."""
+        self._call(
+            "torrent.rename_file",
+            RenameFileRequest(info_hash=info_hash, index=index, path=path),
+        )
+
+    def torrent_rename_folder(
+        self, info_hash: str, folder: str, new_folder: str
+    ) -> None:
+        """Move every file under folder to new_folder."""
+        self._call(
+            "torrent.rename_folder",
+            RenameFolderRequest(
+                info_hash=info_hash, folder=folder, new_folder=new_folder
+            ),
+        )
+
     def torrent_remove(self, info_hash: str, *, delete_data: bool = False) -> None:
         """Remove a torrent."""
         self._call(
@@ -250,9 +368,18 @@ class NeptuneClient:
         """Stop a torrent."""
         self._call("torrent.stop", InfoHashRequest(info_hash=info_hash))
 
-    def torrent_recheck(self, info_hash: str) -> None:
-        """Recheck torrent data integrity."""
-        self._call("torrent.recheck", InfoHashRequest(info_hash=info_hash))
+    def torrent_recheck(
+        self,
+        info_hash: str,
+        *,
+        files: list[int] | None = None,
+        pieces: list[PieceRange] | None = None,
+    ) -> None:
+        """Recheck torrent data integrity, only of files and pieces if given."""
+        self._call(
+            "torrent.recheck",
+            RecheckTorrentRequest(info_hash=info_hash, files=files, pieces=pieces),
+        )
 
     def torrent_reannounce(self, info_hash: str) -> None:
         """Force immediate re-announce to all trackers."""
@@ -388,6 +515,17 @@ class NeptuneClient:
             self._call("client.get_torrent_connection_limit"),
         )
 
+    def client_get_disk_space(self) -> GetDiskSpaceResponse:
+        """Get the free space of every filesystem holding torrent data."""
+        return _validate(GetDiskSpaceResponse, self._call("client.get_disk_space"))
+
+    def client_get_path_owners(self, path: str) -> GetPathOwnersResponse:
+        """Find the torrents owning a file, or the files under a directory."""
+        return _validate(
+            GetPathOwnersResponse,
+            self._call("client.get_path_owners", GetPathOwnersRequest(path=path)),
+        )
+
     # ── torrent — file priority ────────────────────────────────────────
 
     def torrent_set_file_priority(
@@ -48,6 +48,7 @@ class MainDataTorrent:
     total_downloading: int
     connected_seeding: int
     connected_downloading: int
+    source: str = ""
 
 
 @dataclass(frozen=True, slots=True, kw_only=True)
@@ -93,6 +94,27 @@ class Tracker:
     message: str
 
 
+@dataclass(frozen=True, slots=True, kw_only=True)
+class DiskSpace:
+    """Free space of a filesystem holding torrent data."""
+
+    mount_point: str
+    device: str
+    available: int
+    torrents: int
+    paused: int
+    low: bool
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class PathOwner:
+    """A torrent file stored at an absolute path."""
+
+    path: str
+    info_hash: str
+    file: int
+
+
 @dataclass(frozen=True, slots=True, kw_only=True)
 class TorrentInfo:
     """Basic torrent metadata from torrent.get."""
@@ -100,22 +122,59 @@ class TorrentInfo:
     name: str
     tags: list[str]
     custom: dict[str, str]
+    comment: str = ""
+    created_by: str = ""
+    creation_date: int = 0
+    encoding: str = ""
+    source: str = ""
+    web_seeds: list[str] | None = None
+    http_seeds: list[str] | None = None
+    nodes: list[str] | None = None
+    private: bool = False
 
 
 # ── Request types ─────────────────────────────────────────────────────
 
 
+@dataclass(frozen=True, slots=True, kw_only=True)
+class CrossSeed:
+    """Existing data a torrent added with torrent.add reuses."""
+
+    paths: list[str] | None = None
+    torrents: list[str] | None = None
+    link: str | None = None
+
+
 @dataclass(frozen=True, slots=True, kw_only=True)
 class AddTorrentRequest:
     """Parameters for torrent.add."""
 
     torrent_file: bytes
     download_dir: str | None = None
+    incomplete_dir: str | None = None
+    allocation: str | None = None
+    tags: list[str] | None = None
+    custom: dict[str, str] | None = None
+    selected_files: list[int] | None = None
+    is_base_dir: bool = False
+    skip_hash_check: bool = False
+    cross_seed: CrossSeed | None = None
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class AddTorrentURLRequest:
+    """Parameters for torrent.add_url, the options are those of torrent.add."""
+
+    url: str
+    download_dir: str | None = None
+    incomplete_dir: str | None = None
+    allocation: str | None = None
     tags: list[str] | None = None
     custom: dict[str, str] | None = None
     selected_files: list[int] | None = None
     is_base_dir: bool = False
     skip_hash_check: bool = False
+    cross_seed: CrossSeed | None = None
 
 
 @dataclass(frozen=True, slots=True, kw_only=True)
@@ -125,6 +184,24 @@ class InfoHashRequest:
     info_hash: str
 
 
+@dataclass(frozen=True, slots=True, kw_only=True)
+class PieceRange:
+    """An inclusive range of piece indices."""
+
+    first: int
+    last: int
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class RecheckTorrentRequest:
+    """Parameters for torrent.recheck. Without files and pieces the whole
+    torrent is checked."""
+
+    info_hash: str
+    files: list[int] | None = None
+    pieces: list[PieceRange] | None = None
+
+
 @dataclass(frozen=True, slots=True, kw_only=True)
 class MoveTorrentRequest:
     """Parameters for torrent.move."""
@@ -133,6 +210,33 @@ class MoveTorrentRequest:
     target_base_path: str
 
 
+@dataclass(frozen=True, slots=True, kw_only=True)
+class SetLocationRequest:
+    """Parameters for torrent.set_location."""
+
+    info_hash: str
+    base_path: str
+    recheck: bool = False
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class RenameFileRequest:
+    """Parameters for torrent.rename_file."""
+
+    info_hash: str
+    index: int
+    path: str
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class RenameFolderRequest:
+    """Parameters for torrent.rename_folder."""
+
+    info_hash: str
+    folder: str
+    new_folder: str
+
+
 @dataclass(frozen=True, slots=True, kw_only=True)
 class RemoveTorrentRequest:
     """Parameters for torrent.remove."""
@@ -305,6 +409,142 @@ class AddTorrentResponse:
     info_hash: str
 
 
+@dataclass(frozen=True, slots=True, kw_only=True)
+class MatchTorrentRequest:
+    """Parameters for torrent.match."""
+
+    torrent_file: bytes
+    verify: bool = False
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class FileMatch:
+    """A completed file of a loaded torrent holding a file of the matched torrent."""
+
+    path: str
+    info_hash: str
+    index: int
+    file: int
+    verified: bool
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class MatchTorrentResponse:
+    """Response for torrent.match."""
+
+    files: list[FileMatch]
+    torrents: list[str]
+    matched_bytes: int
+    percent: float
+    verifiable_pieces: int
+    matched_pieces: int
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class InspectTorrentRequest:
+    """Parameters for torrent.inspect."""
+
+    torrent_file: bytes
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class InspectedFile:
+    """A file of an inspected torrent."""
+
+    path: str
+    index: int
+    length: int
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class FileTreeNode:
+    """A file or directory of an inspected torrent, directories have index -1."""
+
+    name: str
+    length: int
+    index: int
+    children: list[FileTreeNode] | None = None
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class InspectTorrentResponse:
+    """Response for torrent.inspect."""
+
+    tree: FileTreeNode
+    info_hash: str
+    name: str
+    files: list[InspectedFile]
+    trackers: list[list[str]]
+    total_length: int
+    piece_length: int
+    num_pieces: int
+    private: bool
+    loaded: bool
+    comment: str = ""
+    created_by: str = ""
+    source: str = ""
+    creation_date: int = 0
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class CreateTorrentRequest:
+    """Parameters for torrent.create."""
+
+    path: str
+    source: str | None = None
+    comment: str | None = None
+    trackers: list[list[str]] | None = None
+    web_seeds: list[str] | None = None
+    exclude: list[str] | None = None
+    tags: list[str] | None = None
+    piece_length: int = 0
+    private: bool = False
+    add: bool = False
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class CreateTorrentResponse:
+    """Response for torrent.create."""
+
+    job_id: str
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class CreateTorrentStatusRequest:
+    """Parameters for torrent.create_status."""
+
+    job_id: str
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class CreateTorrentStatusResponse:
+    """Response for torrent.create_status."""
+
+    state: str
+    bytes_done: int
+    bytes_total: int
+    added: bool
+    error: str | None = None
+    info_hash: str | None = None
+    torrent_file: str | None = None  # base64 encoded
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class ExportTorrentRequest:
+    """Parameters for torrent.export."""
+
+    info_hash: str
+    trackers: bool = False
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class ExportTorrentResponse:
+    """Response for torrent.export."""
+
+    magnet: str
+    torrent_file: str  # base64 encoded
+
+
 @dataclass(frozen=True, slots=True, kw_only=True)
 class TorrentFilesResponse:
     """Response for torrent.files."""
@@ -338,3 +578,24 @@ class GetTorrentConnectionLimitResponse:
     """Response for client.get_torrent_connection_limit."""
 
     limit: int = 0
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class GetDiskSpaceResponse:
+    """Response for client.get_disk_space."""
+
+    mounts: list[DiskSpace]
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class GetPathOwnersRequest:
+    """Parameters for client.get_path_owners."""
+
+    path: str
+
+
+@dataclass(frozen=True, slots=True, kw_only=True)
+class GetPathOwnersResponse:
+    """Response for client.get_path_owners."""
+
+    owners: list[PathOwner]
@@ -11,9 +11,13 @@ import respx
 
 from neptune_sdk import (
     AddTorrentRequest,
+    AddTorrentURLRequest,
+    CreateTorrentRequest,
+    CrossSeed,
     MainDataTorrent,
     NeptuneClient,
     NeptuneRPCError,
+    PieceRange,
     TorrentFile,
     TorrentState,
 )
@@ -126,6 +130,32 @@ def test_torrent_get(mock_api, client):
     assert result.custom == {"key1": "val1"}
 
 
+def test_torrent_get_metainfo(mock_api, client):
+    mock_api.post("/json_rpc").mock(
+        return_value=_ok(
+            {
+                "name": "my_torrent",
+                "tags": [],
+                "custom": {},
+                "comment": "",
+                "created_by": "mktorrent 1.1",
+                "creation_date": 1700000000,
+                "encoding": "UTF-8",
+                "source": "TRACKER",
+                "web_seeds": ["https://seed.example/"],
+                "http_seeds": [],
+                "nodes": ["1.2.3.4:6881"],
+                "private": True,
+            }
+        )
+    )
+    result = client.torrent_get("aabb")
+    assert result.source == "TRACKER"
+    assert result.creation_date == 1700000000
+    assert result.web_seeds == ["https://seed.example/"]
+    assert result.nodes == ["1.2.3.4:6881"]
+
+
 def test_torrent_files(mock_api, client):
     mock_api.post("/json_rpc").mock(
         return_value=_ok(
@@ -198,6 +228,163 @@ def test_torrent_add_encodes_base64(mock_api, client):
     assert result.info_hash == "aa" * 20
 
 
+def test_torrent_add_cross_seed(mock_api, client):
+    mock_api.post("/json_rpc").mock(return_value=_ok({"info_hash": "aa" * 20}))
+
+    req = AddTorrentRequest(
+        torrent_file=b"d8:announce3:url...",
+        cross_seed=CrossSeed(torrents=["bb" * 20], link="hardlink"),
+    )
+    client.torrent_add(req)
+
+    payload = json.loads(mock_api.calls.last.request.content)
+    assert payload["params"]["cross_seed"]["torrents"] == ["bb" * 20]
+    assert payload["params"]["cross_seed"]["link"] == "hardlink"
+
+def test_torrent_add_url(mock_api, client):
+    mock_api.post("/json_rpc").mock(return_value=_ok({"info_hash": "aa" * 20}))
+    result = client.torrent_add_url(
+        AddTorrentURLRequest(
+            url="https://tracker.example/download/1.torrent", tags=["auto"]
+        )
+    )
+    assert result.info_hash == "aa" * 20
+    payload = json.loads(mock_api.calls.last.request.content)
+    assert payload["method"] == "torrent.add_url"
+    assert payload["params"]["url"] == "https://tracker.example/download/1.torrent"
+    assert payload["params"]["tags"] == ["auto"]
+
+
+def test_torrent_match(mock_api, client):
+    match = {"path": "/downloads/a.mkv", "info_hash": "bb" * 20, "index": 0, "file": 2, "verified": True}
+    mock_api.post("/json_rpc").mock(
+        return_value=_ok(
+            {
+                "files": [match],
+                "torrents": ["bb" * 20],
+                "matched_bytes": 100,
+                "percent": 50.0,
+                "verifiable_pieces": 1,
+                "matched_pieces": 2,
+            }
+        )
+    )
+    torrent_bytes = b"d8:announce3:url..."
+    result = client.torrent_match(torrent_bytes, verify=True)
+    assert result.files[0].file == 2
+    assert result.files[0].verified is True
+    assert result.matched_pieces == 2
+    assert result.percent == 50.0
+    payload = json.loads(mock_api.calls.last.request.content)
+    assert payload["method"] == "torrent.match"
+    assert payload["params"]["torrent_file"] == base64.b64encode(torrent_bytes).decode()
+    assert payload["params"]["verify"] is True
+
+def test_torrent_inspect(mock_api, client):
+    mock_api.post("/json_rpc").mock(
+        return_value=_ok(
+            {
+                "tree": {
+                    "name": "Show",
+                    "length": 300,
+                    "index": -1,
+                    "children": [{"name": "e01.mkv", "length": 300, "index": 0}],
+                },
+                "info_hash": "bb" * 20,
+                "name": "Show",
+                "comment": "",
+                "created_by": "mktorrent 1.1",
+                "files": [{"path": "e01.mkv", "index": 0, "length": 300}],
+                "trackers": [["https://a.example/announce"]],
+                "creation_date": 1700000000,
+                "total_length": 300,
+                "piece_length": 16384,
+                "num_pieces": 1,
+                "private": False,
+                "loaded": True,
+            }
+        )
+    )
+    torrent_bytes = b"d4:infod...e"
+    result = client.torrent_inspect(torrent_bytes)
+    assert result.tree.children[0].name == "e01.mkv"
+    assert result.created_by == "mktorrent 1.1"
+    assert result.loaded is True
+    payload = json.loads(mock_api.calls.last.request.content)
+    assert payload["method"] == "torrent.inspect"
+    assert payload["params"] == {"torrent_file": base64.b64encode(torrent_bytes).decode()}
+
+
+def test_torrent_create(mock_api, client):
+    mock_api.post("/json_rpc").mock(return_value=_ok({"job_id": "cc" * 20}))
+    result = client.torrent_create(
+        CreateTorrentRequest(
+            path="/data/album",
+            trackers=[["https://a.example/announce"], ["udp://b.example:80"]],
+            exclude=["*.nfo"],
+            private=True,
+            add=True,
+        )
+    )
+    assert result.job_id == "cc" * 20
+    payload = json.loads(mock_api.calls.last.request.content)
+    assert payload["method"] == "torrent.create"
+    assert payload["params"]["trackers"] == [
+        ["https://a.example/announce"],
+        ["udp://b.example:80"],
+    ]
+    assert payload["params"]["piece_length"] == 0
+    assert payload["params"]["add"] is True
+
+
+def test_torrent_create_status(mock_api, client):
+    mock_api.post("/json_rpc").mock(
+        return_value=_ok(
+            {
+                "state": "done",
+                "bytes_done": 100,
+                "bytes_total": 100,
+                "added": True,
+                "error": "",
+                "info_hash": "bb" * 20,
+                "torrent_file": base64.b64encode(b"d4:infod...e").decode(),
+            }
+        )
+    )
+    result = client.torrent_create_status("cc" * 20)
+    assert result.state == "done"
+    assert base64.b64decode(result.torrent_file) == b"d4:infod...e"
+    payload = json.loads(mock_api.calls.last.request.content)
+    assert payload["method"] == "torrent.create_status"
+    assert payload["params"] == {"job_id": "cc" * 20}
+
+
+def test_torrent_export(mock_api, client):
+    mock_api.post("/json_rpc").mock(
+        return_value=_ok(
+            {
+                "magnet": "magnet:?xt=urn:btih:" + "bb" * 20,
+                "torrent_file": base64.b64encode(b"d4:infod...e").decode(),
+            }
+        )
+    )
+    result = client.torrent_export("bb" * 20, trackers=True)
+    assert result.magnet.startswith("magnet:?")
+    payload = json.loads(mock_api.calls.last.request.content)
+    assert payload["method"] == "torrent.export"
+    assert payload["params"] == {"info_hash": "bb" * 20, "trackers": True}
+
+
+def test_export_torrents(mock_api, client):
+    route = mock_api.get("/export/torrents.tar").mock(
+        return_value=httpx.Response(200, content=b"tar")
+    )
+    assert client.export_torrents(["aa" * 20, "bb" * 20], trackers=True) == b"tar"
+    params = route.calls.last.request.url.params
+    assert params.get_list("info_hash") == ["aa" * 20, "bb" * 20]
+    assert params["trackers"] == "true"
+
+
 def test_torrent_remove(mock_api, client):
     mock_api.post("/json_rpc").mock(return_value=_ok({}))
     client.torrent_remove("aabb", delete_data=True)
@@ -215,6 +402,58 @@ def test_torrent_move(mock_api, client):
     assert payload["params"]["target_base_path"] == "/new/path"
 
 
+def test_torrent_move_cancel(mock_api, client):
+    mock_api.post("/json_rpc").mock(return_value=_ok(None))
+    client.torrent_move_cancel("aabb")
+    payload = json.loads(mock_api.calls.last.request.content)
+    assert payload["method"] == "torrent.move_cancel"
+    assert payload["params"] == {"info_hash": "aabb"}
+
+
+def test_torrent_rename_file(mock_api, client):
+    mock_api.post("/json_rpc").mock(return_value=_ok(None))
+    client.torrent_rename_file("aabb", 1, "renamed.mkv")
+    payload = json.loads(mock_api.calls.last.request.content)
+    assert payload["method"] == "torrent.rename_file"
+    assert payload["params"] == {"info_hash": "aabb", "index": 1, "path": "renamed.mkv"}
+
+
+def test_torrent_rename_folder(mock_api, client):
+    mock_api.post("/json_rpc").mock(return_value=_ok(None))
+    client.torrent_rename_folder("aabb", "old", "new")
+    payload = json.loads(mock_api.calls.last.request.content)
+    assert payload["method"] == "torrent.rename_folder"
+    assert payload["params"] == {
+        "info_hash": "aabb",
+        "folder": "old",
+        "new_folder": "new",
+    }
+
+
+def test_torrent_set_location(mock_api, client):
+    mock_api.post("/json_rpc").mock(return_value=_ok(None))
+    client.torrent_set_location("aabb", "/moved", recheck=True)
+    payload = json.loads(mock_api.calls.last.request.content)
+    assert payload["method"] == "torrent.set_location"
+    assert payload["params"] == {
+        "info_hash": "aabb",
+        "base_path": "/moved",
+        "recheck": True,
+    }
+
+
+def test_torrent_recheck_pieces(mock_api, client):
+    mock_api.post("/json_rpc").mock(return_value=_ok(None))
+    client.torrent_recheck("aabb", files=[2], pieces=[PieceRange(first=0, last=9)])
+    payload = json.loads(mock_api.calls.last.request.content)
+    assert payload["method"] == "torrent.recheck"
+    assert payload["params"] == {
+        "info_hash": "aabb",
+        "files": [2],
+        "pieces": [{"first": 0, "last": 9}],
+    }
+
+
 def test_torrent_start(mock_api, client):
     mock_api.post("/json_rpc").mock(return_value=_ok(None))
     client.torrent_start("aabb")
@@ -315,3 +554,32 @@ def test_torrent_list_with_keys(mock_api, client):
     assert len(result.torrents) == 1
     payload = json.loads(mock_api.calls.last.request.content)
     assert payload["params"]["keys"] == ["label"]
+
+
+def test_client_get_disk_space(mock_api, client):
+    mount = {
+        "mount_point": "/downloads",
+        "device": "8:1",
+        "available": 1 << 30,
+        "torrents": 2,
+        "paused": 1,
+        "low": True,
+    }
+    mock_api.post("/json_rpc").mock(return_value=_ok({"mounts": [mount]}))
+    result = client.client_get_disk_space()
+    assert result.mounts[0].mount_point == "/downloads"
+    assert result.mounts[0].low is True
+    payload = json.loads(mock_api.calls.last.request.content)
+    assert payload["method"] == "client.get_disk_space"
+
+
+
+def test_client_get_path_owners(mock_api, client):
+    owner = {"path": "/downloads/a.mkv", "info_hash": "aa" * 20, "file": 0}
+    mock_api.post("/json_rpc").mock(return_value=_ok({"owners": [owner]}))
+    result = client.client_get_path_owners("/downloads")
+    assert result.owners[0].path == "/downloads/a.mkv"
+    assert result.owners[0].file == 0
+    payload = json.loads(mock_api.calls.last.request.content)
+    assert payload["method"] == "client.get_path_owners"
+    assert payload["params"] == {"path": "/downloads"}
\ No newline at end of file
@@ -2,22 +2,40 @@ import { NeptuneConnectionError, NeptuneHTTPError, NeptuneRPCError } from './err
 import type {
   AddTorrentParams,
   AddTorrentResult,
+  AddTorrentURLParams,
   AddTrackerParams,
+  CreateTorrentParams,
+  CreateTorrentResult,
+  CreateTorrentStatusParams,
+  CreateTorrentStatusResult,
   DelCustomParams,
+  ExportTorrentParams,
+  ExportTorrentResult,
+  GetDiskSpaceResult,
   GetDownloadSlotsResult,
+  GetPathOwnersParams,
+  GetPathOwnersResult,
   GetRecheckOnCompleteResult,
   GetSlowDownloadSpeedThresholdResult,
   GetTorrentConnectionLimitResult,
   GlobalSpeedLimitParams,
   InfoHashParams,
+  InspectTorrentParams,
+  InspectTorrentResult,
   ListTorrentParams,
+  MatchTorrentParams,
+  MatchTorrentResult,
   MoveTorrentParams,
+  RecheckTorrentParams,
   RemoveTorrentParams,
   RemoveTrackerParams,
+  RenameFileParams,
+  RenameFolderParams,
   ReplaceTrackersParams,
   SetCustomParams,
   SetDownloadSlotsParams,
   SetFilePriorityParams,
+  SetLocationParams,
   SetQueueWeightParams,
   SetRecheckOnCompleteParams,
   SetSlowDownloadSpeedThresholdParams,
@@ -58,11 +76,21 @@ export interface NeptuneMethodMap {
   'torrent.peers': { params: InfoHashParams; result: TorrentPeers; };
   'torrent.trackers': { params: InfoHashParams; result: TorrentTrackers; };
   'torrent.add': { params: AddTorrentParams; result: AddTorrentResult; };
+  'torrent.add_url': { params: AddTorrentURLParams; result: AddTorrentResult; };
+  'torrent.match': { params: MatchTorrentParams; result: MatchTorrentResult; };
+  'torrent.inspect': { params: InspectTorrentParams; result: InspectTorrentResult; };
+  'torrent.create': { params: CreateTorrentParams; result: CreateTorrentResult; };
+  'torrent.create_status': { params: CreateTorrentStatusParams; result: CreateTorrentStatusResult; };
+  'torrent.export': { params: ExportTorrentParams; result: ExportTorrentResult; };
   'torrent.remove': { params: RemoveTorrentParams; result: void; };
   'torrent.start': { params: InfoHashParams; result: void; };
   'torrent.stop': { params: InfoHashParams; result: void; };
-  'torrent.recheck': { params: InfoHashParams; result: void; };
+  'torrent.recheck': { params: RecheckTorrentParams; result: void; };
   'torrent.move': { params: MoveTorrentParams; result: void; };
+  'torrent.move_cancel': { params: InfoHashParams; result: void; };
+  'torrent.set_location': { params: SetLocationParams; result: void; };
+  'torrent.rename_file': { params: RenameFileParams; result: void; };
+  'torrent.rename_folder': { params: RenameFolderParams; result: void; };
   'torrent.add_tags': { params: TagsParams; result: void; };
   'torrent.remove_tags': { params: TagsParams; result: void; };
   'torrent.add_tracker': { params: AddTrackerParams; result: void; };
@@ -90,6 +118,8 @@ export interface NeptuneMethodMap {
   'client.get_recheck_on_complete': { params: Record<string, never>; result: GetRecheckOnCompleteResult; };
   'client.set_torrent_connection_limit': { params: SetTorrentConnectionLimitParams; result: void; };
   'client.get_torrent_connection_limit': { params: Record<string, never>; result: GetTorrentConnectionLimitResult; };
+  'client.get_disk_space': { params: Record<string, never>; result: GetDiskSpaceResult; };
+  'client.get_path_owners': { params: GetPathOwnersParams; result: GetPathOwnersResult; };
 }
 
 /** Union of all method name strings. */
@@ -39,6 +39,8 @@ export interface Torrent {
   total_downloading: number;
   connected_seeding: number;
   connected_downloading: number;
+  /** Source field of the info dict, set by private trackers. */
+  source: string;
 }
 
 /** Global transfer rates and totals. */
@@ -81,6 +83,20 @@ export interface TorrentInfo {
   name: string;
   tags: string[];
   custom: Record<string, string>;
+  comment: string;
+  created_by: string;
+  /** Unix timestamp, 0 when the torrent has none. */
+  creation_date: number;
+  encoding: string;
+  /** Source field of the info dict, set by private trackers. */
+  source: string;
+  /** BEP 19 url-list. */
+  web_seeds: string[];
+  /** BEP 17 httpseeds. */
+  http_seeds: string[];
+  /** BEP 5 DHT nodes as `host:port`. */
+  nodes: string[];
+  private: boolean;
 }
 
 // ── Response wrappers ────────────────────────────────────────────────
@@ -105,6 +121,97 @@ export interface AddTorrentResult {
   info_hash: string;
 }
 
+/** A completed file of a loaded torrent holding a file of the matched torrent. */
+export interface FileMatch {
+  path: string;
+  /** The loaded torrent. */
+  info_hash: string;
+  /** File index in the matched torrent. */
+  index: number;
+  /** File index in the loaded torrent. */
+  file: number;
+  /** Sample pieces passed the hash check, false when only the name and size matched. */
+  verified: boolean;
+}
+
+export interface MatchTorrentResult {
+  /** Sorted by index. */
+  files: FileMatch[];
+  /** Loaded torrents holding matched files. */
+  torrents: string[];
+  matched_bytes: number;
+  /** Share of pieces a cross-seed add can verify, 0 to 100. Only verified files count. */
+  percent: number;
+  verifiable_pieces: number;
+  /** Pieces whose data is all in matched files, also those matched by name only. */
+  matched_pieces: number;
+}
+
+/** A file of an inspected torrent. */
+export interface InspectedFile {
+  path: string;
+  index: number;
+  length: number;
+}
+
+/** A file or directory of an inspected torrent. */
+export interface FileTreeNode {
+  name: string;
+  /** Total length of the files under a directory. */
+  length: number;
+  /** File index, -1 for directories. */
+  index: number;
+  children?: FileTreeNode[];
+}
+
+export interface InspectTorrentResult {
+  /** Files nested by directory. */
+  tree: FileTreeNode;
+  info_hash: string;
+  name: string;
+  comment: string;
+  created_by: string;
+  /** Source field of the info dict, set by private trackers. */
+  source: string;
+  files: InspectedFile[];
+  /** Announce URLs grouped in tiers. */
+  trackers: string[][];
+  /** Unix timestamp, 0 when the torrent has none. */
+  creation_date: number;
+  total_length: number;
+  piece_length: number;
+  num_pieces: number;
+  private: boolean;
+  /** A torrent with the same info hash is already loaded. */
+  loaded: boolean;
+}
+
+export interface CreateTorrentResult {
+  /** Job id, listed as info hash among hash checks. */
+  job_id: string;
+}
+
+export interface CreateTorrentStatusResult {
+  state: "queued" | "hashing" | "done" | "failed";
+  /** Why the job failed. */
+  error: string;
+  bytes_done: number;
+  bytes_total: number;
+  /** The torrent was added to the session and is seeding. */
+  added: boolean;
+  /** Info hash of the created torrent, when done. */
+  info_hash: string;
+  /** Base64-encoded torrent file, when done. */
+  torrent_file: string | null;
+}
+
+export interface ExportTorrentResult {
+  /** Magnet link with the name and current trackers. */
+  magnet: string;
+  /** Base64-encoded torrent file. */
+  torrent_file: string;
+}
+
 // ── Request types ────────────────────────────────────────────────────
 
 export interface AddTorrentParams {
@@ -112,16 +219,105 @@ export interface AddTorrentParams {
   torrent_file: string;
   /** Base download directory. If omitted the global default is used. */
   download_dir?: string;
+  /**
+   * Directory to download into; the data moves to `download_dir` on
+   * completion. If omitted the global `incomplete-dir` is used.
+   */
+  incomplete_dir?: string;
+  /**
+   * How files get their disk space: `sparse`, `full` or `zero-fill`. If
+   * omitted, `full` when the global `fallocate` is enabled, else `sparse`.
+   */
+  allocation?: "sparse" | "full" | "zero-fill";
   /** Tags to attach to the torrent. */
   tags?: string[];
   /** Custom key-value metadata. */
   custom?: Record<string, string>;
   /** Indices of files to download. Omit or empty to download all. */
   selected_files?: number[];
-  /** When true, the torrent name is not appended to `download_dir`. */
+  /** When true, the torrent name is not appended to `download_dir` and `incomplete_dir`. */
   is_base_dir?: boolean;
-  /** When true, skip piece hash check and only verify file sizes. */
+  /** When true, only verify file sizes and hash check each piece before its first upload. */
   skip_hash_check?: boolean;
+  /** Reuse existing data matching the torrent files instead of downloading it. */
+  cross_seed?: CrossSeed;
+}
+
+/** Options of `torrent.add` for a torrent file the server fetches. */
+export interface AddTorrentURLParams extends Omit<AddTorrentParams, "torrent_file"> {
+  /**
+   * HTTP or HTTPS URL of the torrent file, fetched with the headers and
+   * cookies configured for its domain.
+   */
+  url: string;
+}
+
+export interface CreateTorrentParams {
+  /** File or directory to create the torrent of. */
+  path: string;
+  /** Source field of the info dict, makes the info hash unique to a tracker. */
+  source?: string;
+  comment?: string;
+  /** Announce URLs grouped in tiers. */
+  trackers?: string[][];
+  /** BEP 19 web seed URLs. */
+  web_seeds?: string[];
+  /** Glob patterns of files and directories to leave out, matched against names and relative paths. */
+  exclude?: string[];
+  /** Tags of the torrent when it is added. */
+  tags?: string[];
+  /** Power of two from 16 KiB to 256 MiB. Omit or 0 to pick one by the total size. */
+  piece_length?: number;
+  private?: boolean;
+  /** Add the torrent seeding the data it was created from, without checking it again. */
+  add?: boolean;
+}
+
+export interface CreateTorrentStatusParams {
+  job_id: string;
+}
+
+export interface ExportTorrentParams {
+  info_hash: string;
+  /**
+   * Write the current trackers into `announce` and `announce-list` instead of
+   * the ones the torrent was added with.
+   */
+  trackers?: boolean;
+}
+
+export interface InspectTorrentParams {
+  /** Base64-encoded torrent file content. */
+  torrent_file: string;
+}
+
+export interface MatchTorrentParams {
+  /** Base64-encoded torrent file content. */
+  torrent_file: string;
+  /**
+   * Hash check sample pieces against files of the same size instead of
+   * matching by name. Reads from disk.
+   */
+  verify?: boolean;
+}
+
+/**
+ * Existing data a torrent added with `torrent.add` reuses. Files are matched
+ * by size and sample piece hashes, then every piece is hash checked; pieces
+ * that fail are downloaded.
+ */
+export interface CrossSeed {
+  /** Existing files, or directories searched for files. */
+  paths?: string[];
+  /** Info hashes of loaded torrents whose files are candidates. */
+  torrents?: string[];
+  /**
+   * `remap` (default) points files at the existing paths, `hardlink` or
+   * `reflink` links them into the download dir. Remapped and hardlinked
+   * files are never written; a file the hash check does not fully verify is
+   * reflinked into the download dir when possible, or downloaded.
+   */
+  link?: "remap" | "hardlink" | "reflink";
 }
 
 export interface InfoHashParams {
@@ -134,11 +330,48 @@ export interface RemoveTorrentParams extends InfoHashParams {
   delete_data?: boolean;
 }
 
+/** An inclusive range of piece indices. */
+export interface PieceRange {
+  first: number;
+  last: number;
+}
+
+export interface RecheckTorrentParams extends InfoHashParams {
+  /**
+   * Only check the pieces of these file indices. The whole torrent is
+   * checked when both `files` and `pieces` are omitted.
+   */
+  files?: number[];
+  /** Only check the pieces in these ranges. */
+  pieces?: PieceRange[];
+}
+
 export interface MoveTorrentParams extends InfoHashParams {
   /** New base directory for the torrent data. */
   target_base_path: string;
 }
 
+export interface RenameFileParams extends InfoHashParams {
+  /** Index of the file in torrent.files. */
+  index: number;
+  /** New path relative to the base path, or absolute to use an existing file. */
+  path: string;
+}
+
+export interface RenameFolderParams extends InfoHashParams {
+  /** Folder to rename, relative to the base path. */
+  folder: string;
+  /** New folder, relative to the base path. */
+  new_folder: string;
+}
+
+export interface SetLocationParams extends InfoHashParams {
+  /** Directory the data was already moved to. No file is moved. */
+  base_path: string;
+  /** Hash check all data instead of only checking file sizes. */
+  recheck?: boolean;
+}
+
 export interface TagsParams extends InfoHashParams {
   tags: string[];
 }
@@ -192,6 +425,42 @@ export interface GetTorrentConnectionLimitResult {
 }
 
 /** Response for client.get_transfer_config. */
+/** Free space of a filesystem holding torrent data. */
+export interface DiskSpace {
+  /** Mount point, or the data path of a torrent when the mount is unknown. */
+  mount_point: string;
+  device: string;
+  /** Free bytes. */
+  available: number;
+  torrents: number;
+  /** Torrents paused for low disk space. */
+  paused: number;
+  /** Free space is below `min-free-space`. */
+  low: boolean;
+}
+
+export interface GetDiskSpaceResult {
+  mounts: DiskSpace[];
+}
+
+/** A torrent file stored at an absolute path. */
+export interface PathOwner {
+  path: string;
+  info_hash: string;
+  /** File index in the torrent. */
+  file: number;
+}
+
+export interface GetPathOwnersParams {
+  /** A file, or a directory to list the owners of every file under it. */
+  path: string;
+}
+
+export interface GetPathOwnersResult {
+  /** Sorted by path. */
+  owners: PathOwner[];
+}
+
 export interface TransferConfig {
   download_limit: number;
   upload_limit: number;